@@ -4,19 +4,25 @@ import (
 	"sync"
 
 	"github.com/haskel/capfox/internal/config"
+	"github.com/haskel/capfox/internal/decision"
 	"github.com/haskel/capfox/internal/monitor"
 )
 
 type Manager struct {
 	aggregator *monitor.Aggregator
 	checker    *ThresholdChecker
-	mu         sync.RWMutex
+	tasks      *config.TaskThresholds
+	// state returns the state Ask decides on, nil uses the smoothed state
+	state func() *monitor.SystemState
+	mu    sync.RWMutex
 }
 
 type AskRequest struct {
 	Task       string            `json:"task"`
 	Complexity int               `json:"complexity,omitempty"`
 	Resources  *ResourceEstimate `json:"resources,omitempty"`
+	// Priority selects the thresholds of a priority class, empty is normal
+	Priority config.Priority `json:"priority,omitempty"`
 }
 
 type ResourceEstimate struct {
@@ -28,6 +34,11 @@ type ResourceEstimate struct {
 type AskResponse struct {
 	Allowed bool     `json:"allowed"`
 	Reasons []string `json:"reasons,omitempty"`
+	// GPUs are the indices of GPUs within their limits, most headroom first
+	GPUs []int `json:"gpus,omitempty"`
+	// VisibleDevices is GPUs formatted for CUDA_VISIBLE_DEVICES and
+	// HIP_VISIBLE_DEVICES
+	VisibleDevices string `json:"visible_devices,omitempty"`
 }
 
 func NewManager(aggregator *monitor.Aggregator, thresholds config.ThresholdsConfig) *Manager {
@@ -41,8 +52,23 @@ func (m *Manager) Ask(req AskRequest, withReasons bool) AskResponse {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	state := m.aggregator.GetState()
-	reasons := m.checker.Check(state)
+	thresholds := m.checker.GetThresholds()
+	var reserved bool
+	if m.tasks != nil {
+		thresholds, reserved = m.tasks.ResolvePriority(req.Task, req.Priority)
+	}
+
+	var state *monitor.SystemState
+	if m.state != nil {
+		state = m.state()
+	} else {
+		state = m.aggregator.GetSmoothedState()
+	}
+	reasons := m.check(thresholds, state)
+	if len(reasons) > 0 && reserved {
+		base, _ := m.tasks.Resolve(req.Task)
+		reasons = decision.PriorityReasons(reasons, m.check(base, state))
+	}
 
 	allowed := len(reasons) == 0
 
@@ -50,6 +76,11 @@ func (m *Manager) Ask(req AskRequest, withReasons bool) AskResponse {
 		Allowed: allowed,
 	}
 
+	if gpus := decision.ThresholdsFromConfig(thresholds).PlaceGPUs(state.GPUs, 0, 0).Fitting; len(gpus) > 0 {
+		resp.GPUs = gpus
+		resp.VisibleDevices = monitor.VisibleDevices(gpus)
+	}
+
 	if withReasons && !allowed {
 		resp.Reasons = make([]string, len(reasons))
 		for i, r := range reasons {
@@ -60,8 +91,40 @@ func (m *Manager) Ask(req AskRequest, withReasons bool) AskResponse {
 	return resp
 }
 
+// check returns the reasons the state exceeds the thresholds, followed by
+// those of the thresholds contributed by monitors.
+func (m *Manager) check(thresholds config.ThresholdsConfig, state *monitor.SystemState) []Reason {
+	reasons := checkThresholds(thresholds, state)
+	for _, reason := range m.aggregator.Check(state, thresholds.Limits()) {
+		reasons = append(reasons, Reason(reason))
+	}
+	return reasons
+}
+
+// GetThresholds returns a copy of the current thresholds.
+func (m *Manager) GetThresholds() config.ThresholdsConfig {
+	return m.checker.GetThresholds()
+}
+
 func (m *Manager) UpdateThresholds(thresholds config.ThresholdsConfig) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 	m.checker.UpdateThresholds(thresholds)
 }
+
+// SetTaskThresholds sets the per-task overrides Ask resolves the thresholds
+// from. Nil checks every task against the global thresholds.
+func (m *Manager) SetTaskThresholds(tasks *config.TaskThresholds) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tasks = tasks
+}
+
+// SetStateSource sets the function returning the state Ask decides on,
+// e.g. the smoothed state with the capacity held by leases added. Nil uses
+// the smoothed state.
+func (m *Manager) SetStateSource(state func() *monitor.SystemState) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.state = state
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"slices"
 	"testing"
 	"time"
 
+	"gopkg.in/yaml.v3"
+
+	"github.com/haskel/capfox/internal/config"
 	"github.com/haskel/capfox/internal/monitor"
 )
 
@@ -16,14 +20,18 @@ func testLogger() *slog.Logger {
 
 type mockMonitor struct {
 	name string
-	data any
+	data monitor.Sample
 }
 
 func (m *mockMonitor) Name() string {
 	return m.name
 }
 
-func (m *mockMonitor) Collect() (any, error) {
+func (m *mockMonitor) Section() string {
+	return m.name
+}
+
+func (m *mockMonitor) Collect() (monitor.Sample, error) {
 	return m.data, nil
 }
 
@@ -190,3 +198,190 @@ func TestManager_UpdateThresholds(t *testing.T) {
 		t.Error("expected allowed with 90% threshold")
 	}
 }
+
+func TestManager_Ask_TaskThresholds(t *testing.T) {
+	agg := testAggregator(85, 50) // CPU at 85%
+	defer func() { _ = agg.Stop() }()
+
+	var overrides map[string]config.TaskConfig
+	if err := yaml.Unmarshal([]byte(`
+"batch_*":
+  thresholds:
+    cpu:
+      max_percent: 95
+`), &overrides); err != nil {
+		t.Fatalf("failed to parse overrides: %v", err)
+	}
+	tasks, err := config.NewTaskThresholds(defaultThresholds(), overrides, nil)
+	if err != nil {
+		t.Fatalf("failed to resolve overrides: %v", err)
+	}
+
+	manager := NewManager(agg, defaultThresholds())
+	manager.SetTaskThresholds(tasks)
+
+	if resp := manager.Ask(AskRequest{Task: "batch_report"}, true); !resp.Allowed {
+		t.Errorf("expected batch task allowed under its 95%% limit, got %v", resp.Reasons)
+	}
+
+	resp := manager.Ask(AskRequest{Task: "inference"}, true)
+	if resp.Allowed || len(resp.Reasons) != 1 || resp.Reasons[0] != string(ReasonCPUOverload) {
+		t.Errorf("expected other tasks denied by the global 80%% limit, got %+v", resp)
+	}
+}
+
+func TestManager_Ask_PriorityHeadroom(t *testing.T) {
+	agg := testAggregator(70, 90) // CPU at 70%, memory at 90%
+	defer func() { _ = agg.Stop() }()
+
+	var priorities map[config.Priority]config.PriorityConfig
+	if err := yaml.Unmarshal([]byte(`
+low:
+  thresholds:
+    cpu:
+      max_percent: 60
+high:
+  thresholds:
+    memory:
+      max_percent: 95
+`), &priorities); err != nil {
+		t.Fatalf("failed to parse priorities: %v", err)
+	}
+	tasks, err := config.NewTaskThresholds(defaultThresholds(), nil, priorities)
+	if err != nil {
+		t.Fatalf("failed to resolve priorities: %v", err)
+	}
+
+	manager := NewManager(agg, defaultThresholds())
+	manager.SetTaskThresholds(tasks)
+
+	tests := []struct {
+		priority config.Priority
+		allowed  bool
+		reasons  []string
+	}{
+		{config.PriorityLow, false, []string{"priority:cpu_overload", "memory_overload"}},
+		{config.PriorityNormal, false, []string{"memory_overload"}},
+		{config.PriorityHigh, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.priority), func(t *testing.T) {
+			resp := manager.Ask(AskRequest{Task: "report", Priority: tt.priority}, true)
+			if resp.Allowed != tt.allowed {
+				t.Errorf("expected allowed %v, got %v", tt.allowed, resp.Allowed)
+			}
+			if !slices.Equal(resp.Reasons, tt.reasons) {
+				t.Errorf("expected reasons %v, got %v", tt.reasons, resp.Reasons)
+			}
+		})
+	}
+}
+
+// licenseMonitor contributes a threshold of its own through the registry API.
+type licenseMonitor struct {
+	mockMonitor
+}
+
+func (m *licenseMonitor) Thresholds() []monitor.Threshold {
+	return []monitor.Threshold{{
+		Reason: "license_seats_exhausted",
+		Exceeded: func(state *monitor.SystemState, limits monitor.Limits) bool {
+			return state.Custom["license_seats_free"] < 1
+		},
+	}}
+}
+
+func TestManager_Ask_MonitorThresholds(t *testing.T) {
+	license := &licenseMonitor{mockMonitor{
+		name: "plugin:license",
+		data: monitor.CustomState{"license_seats_free": 0},
+	}}
+	agg := monitor.NewAggregator([]monitor.Monitor{license}, time.Second, testLogger())
+	_ = agg.Start(context.Background())
+	defer func() { _ = agg.Stop() }()
+
+	thresholds := defaultThresholds()
+	thresholds.Storage.MinFreeGB = 0
+	manager := NewManager(agg, thresholds)
+
+	resp := manager.Ask(AskRequest{Task: "render"}, true)
+	if resp.Allowed {
+		t.Fatal("expected denied by monitor threshold")
+	}
+	if len(resp.Reasons) != 1 || resp.Reasons[0] != "license_seats_exhausted" {
+		t.Errorf("expected [license_seats_exhausted], got %v", resp.Reasons)
+	}
+}
+
+// processMonitor contributes the run queue thresholds like the built-in
+// process monitor.
+type processMonitor struct {
+	mockMonitor
+}
+
+func (m *processMonitor) Thresholds() []monitor.Threshold {
+	return monitor.ProcessThresholds()
+}
+
+func TestManager_Ask_ProcessThresholds(t *testing.T) {
+	process := &processMonitor{mockMonitor{
+		name: "process",
+		data: &monitor.ProcessState{ProcsRunning: 24, ProcsBlocked: 2},
+	}}
+	agg := monitor.NewAggregator([]monitor.Monitor{process}, time.Second, testLogger())
+	_ = agg.Start(context.Background())
+	defer func() { _ = agg.Stop() }()
+
+	thresholds := defaultThresholds()
+	thresholds.Storage.MinFreeGB = 0
+	thresholds.Process = config.ProcessThreshold{MaxRunning: 32, MaxBlocked: 1}
+
+	var overrides map[string]config.TaskConfig
+	var priorities map[config.Priority]config.PriorityConfig
+	if err := yaml.Unmarshal([]byte(`
+"build_*":
+  thresholds:
+    process:
+      max_blocked: 4
+`), &overrides); err != nil {
+		t.Fatalf("failed to parse overrides: %v", err)
+	}
+	if err := yaml.Unmarshal([]byte(`
+low:
+  thresholds:
+    process:
+      max_running: 16
+`), &priorities); err != nil {
+		t.Fatalf("failed to parse priorities: %v", err)
+	}
+	tasks, err := config.NewTaskThresholds(thresholds, overrides, priorities)
+	if err != nil {
+		t.Fatalf("failed to resolve overrides: %v", err)
+	}
+
+	manager := NewManager(agg, thresholds)
+	manager.SetTaskThresholds(tasks)
+
+	tests := []struct {
+		task     string
+		priority config.Priority
+		reasons  []string
+	}{
+		{"report", config.PriorityNormal, []string{monitor.ReasonProcsBlocked}},
+		{"build_docs", config.PriorityNormal, nil},
+		{"build_docs", config.PriorityLow, []string{"priority:" + monitor.ReasonRunQueueFull}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.task+"/"+string(tt.priority), func(t *testing.T) {
+			resp := manager.Ask(AskRequest{Task: tt.task, Priority: tt.priority}, true)
+			if resp.Allowed != (tt.reasons == nil) {
+				t.Errorf("expected allowed %v, got %v", tt.reasons == nil, resp.Allowed)
+			}
+			if !slices.Equal(resp.Reasons, tt.reasons) {
+				t.Errorf("expected reasons %v, got %v", tt.reasons, resp.Reasons)
+			}
+		})
+	}
+}
@@ -4,17 +4,41 @@ import (
 	"sync"
 
 	"github.com/haskel/capfox/internal/config"
+	"github.com/haskel/capfox/internal/decision"
 	"github.com/haskel/capfox/internal/monitor"
 )
 
-type Reason string
+// Reason is the reason type of the decision engine, so /ask and /v2/ask
+// deny with the same reasons.
+type Reason = decision.Reason
 
 const (
-	ReasonCPUOverload    Reason = "cpu_overload"
-	ReasonMemoryOverload Reason = "memory_overload"
-	ReasonGPUOverload    Reason = "gpu_overload"
-	ReasonVRAMOverload   Reason = "vram_overload"
-	ReasonStorageLow     Reason = "storage_low"
+	ReasonCPUOverload        Reason = "cpu_overload"
+	ReasonMemoryOverload     Reason = "memory_overload"
+	ReasonGPUOverload        Reason = "gpu_overload"
+	ReasonVRAMOverload       Reason = "vram_overload"
+	ReasonStorageLow         Reason = "storage_low"
+	ReasonStorageInodesLow   Reason = "storage_inodes_low"
+	ReasonStorageReadOnly    Reason = "storage_readonly"
+	ReasonStorageUnavailable Reason = "storage_unavailable"
+	ReasonSwapOverload       Reason = "swap_overload"
+	ReasonSwapThrashing      Reason = "swap_thrashing"
+	ReasonCPUPressure        Reason = "cpu_pressure"
+	ReasonMemoryPressure     Reason = "memory_pressure"
+	ReasonIOPressure         Reason = "io_pressure"
+	ReasonIOSaturated        Reason = "io_saturated"
+	ReasonNetworkSaturated   Reason = "network_saturated"
+	ReasonThermalThrottling  Reason = "thermal_throttling"
+	ReasonNUMAOverload       Reason = "numa_overload"
+	ReasonMetricsStale       Reason = "metrics_stale"
+
+	// ReasonCustomPrefix starts the reason of a plugin metric over its
+	// limit, e.g. "custom:render_backlog"
+	ReasonCustomPrefix = decision.ReasonCustomPrefix
+	// ReasonPriorityPrefix starts a reason only the limits of the priority
+	// class raised, e.g. "priority:cpu_overload" while CPU usage is within
+	// the task thresholds but above the headroom reserved from low priority
+	ReasonPriorityPrefix = decision.ReasonPriorityPrefix
 )
 
 type ThresholdChecker struct {
@@ -31,42 +55,151 @@ func (c *ThresholdChecker) Check(state *monitor.SystemState) []Reason {
 	thresholds := c.thresholds
 	c.mu.RUnlock()
 
+	return checkThresholds(thresholds, state)
+}
+
+// checkThresholds returns the limits of thresholds that state violates.
+func checkThresholds(thresholds config.ThresholdsConfig, state *monitor.SystemState) []Reason {
 	var reasons []Reason
 
 	if state.CPU.UsagePercent > thresholds.CPU.MaxPercent {
 		reasons = append(reasons, ReasonCPUOverload)
 	}
 
-	if state.Memory.UsagePercent > thresholds.Memory.MaxPercent {
+	if state.Memory.Percent(thresholds.Memory.Mode) > thresholds.Memory.MaxPercent {
 		reasons = append(reasons, ReasonMemoryOverload)
 	}
 
-	// Check GPU thresholds
-	for _, gpu := range state.GPUs {
-		if gpu.UsagePercent > thresholds.GPU.MaxPercent {
+	// Check each GPU against its own limits, a task needs only one
+	limits := decision.ThresholdsFromConfig(thresholds)
+	gpus := limits.PlaceGPUs(state.GPUs, 0, 0)
+	if len(gpus.Fitting) == 0 {
+		if gpus.Busy {
 			reasons = append(reasons, ReasonGPUOverload)
-			break
 		}
-
-		vramPercent := float64(gpu.VRAMUsedBytes) / float64(gpu.VRAMTotalBytes) * 100
-		if gpu.VRAMTotalBytes > 0 && vramPercent > thresholds.VRAM.MaxPercent {
+		if gpus.Full {
 			reasons = append(reasons, ReasonVRAMOverload)
-			break
 		}
 	}
 
-	// Check storage thresholds
+	// Check storage thresholds. Unavailable and read-only paths always deny.
+	var storageLow, inodesLow, readOnly, unavailable bool
 	for _, disk := range state.Storage {
+		if disk.Error != "" {
+			unavailable = true
+			continue
+		}
 		freeGB := float64(disk.TotalBytes-disk.UsedBytes) / (1024 * 1024 * 1024)
 		if freeGB < thresholds.Storage.MinFreeGB {
-			reasons = append(reasons, ReasonStorageLow)
+			storageLow = true
+		}
+		if thresholds.Storage.MinFreeInodesPercent > 0 && disk.FreeInodesPercent() < thresholds.Storage.MinFreeInodesPercent {
+			inodesLow = true
+		}
+		if disk.ReadOnly {
+			readOnly = true
+		}
+	}
+	if storageLow {
+		reasons = append(reasons, ReasonStorageLow)
+	}
+	if inodesLow {
+		reasons = append(reasons, ReasonStorageInodesLow)
+	}
+	if readOnly {
+		reasons = append(reasons, ReasonStorageReadOnly)
+	}
+	if unavailable {
+		reasons = append(reasons, ReasonStorageUnavailable)
+	}
+
+	// Check swap thresholds (0 = disabled)
+	if thresholds.Swap.MaxPercent > 0 && state.Swap.UsagePercent > thresholds.Swap.MaxPercent {
+		reasons = append(reasons, ReasonSwapOverload)
+	}
+
+	if thresholds.Swap.MaxPagesPerSec > 0 && state.Swap.PagesPerSec() > thresholds.Swap.MaxPagesPerSec {
+		reasons = append(reasons, ReasonSwapThrashing)
+	}
+
+	// Check utilization of the devices backing monitored paths (0 = disabled)
+	for path, util := range state.IO.PathUtil() {
+		if limit := thresholds.IO.Limit(path); limit > 0 && util > limit {
+			reasons = append(reasons, ReasonIOSaturated)
 			break
 		}
 	}
 
+	// Check interface utilization against capacity (0 = disabled)
+	if thresholds.Network.MaxPercent > 0 && state.Network.MaxPercent() > thresholds.Network.MaxPercent {
+		reasons = append(reasons, ReasonNetworkSaturated)
+	}
+
+	// Check temperatures and active CPU throttling (0 = disabled)
+	hot := thresholds.Thermal.MaxCelsius > 0 &&
+		(state.Thermal.MaxCelsius() > thresholds.Thermal.MaxCelsius || state.Thermal.ThrottleEventsPerSec > 0)
+	if hot || (len(gpus.Fitting) == 0 && gpus.Hot) {
+		reasons = append(reasons, ReasonThermalThrottling)
+	}
+
+	// Check that at least one NUMA node is within the per-node limits (0 = disabled)
+	if numaExceeded(thresholds, state) {
+		reasons = append(reasons, ReasonNUMAOverload)
+	}
+
+	// Check pressure stall thresholds (0 = disabled)
+	window := thresholds.Pressure.Window
+	if pressureExceeded(thresholds.Pressure.CPU, state.Pressure.CPU, window) {
+		reasons = append(reasons, ReasonCPUPressure)
+	}
+	if pressureExceeded(thresholds.Pressure.Memory, state.Pressure.Memory, window) {
+		reasons = append(reasons, ReasonMemoryPressure)
+	}
+	if pressureExceeded(thresholds.Pressure.IO, state.Pressure.IO, window) {
+		reasons = append(reasons, ReasonIOPressure)
+	}
+
+	// Fail closed when a monitor keeps failing (0 = disabled)
+	if thresholds.Health.MaxFailures > 0 && len(state.Health.Failing(thresholds.Health.MaxFailures)) > 0 {
+		reasons = append(reasons, ReasonMetricsStale)
+	}
+
+	reasons = append(reasons, limits.CustomExceeded(state.Custom)...)
+
 	return reasons
 }
 
+// pressureExceeded reports whether PSI for a resource violates its limit.
+func pressureExceeded(limit config.PressureLimit, res monitor.PressureResource, window string) bool {
+	some, full := res.Window(window)
+	if limit.MaxSome > 0 && some > limit.MaxSome {
+		return true
+	}
+	return limit.MaxFull > 0 && full > limit.MaxFull
+}
+
+// numaExceeded reports whether per-node limits are set and every node with
+// both CPUs and memory is over them. Without NUMA information the check is skipped.
+func numaExceeded(thresholds config.ThresholdsConfig, state *monitor.SystemState) bool {
+	limits := thresholds.NUMA
+	if (limits.MaxCPUPercent <= 0 && limits.MaxMemoryPercent <= 0) || len(state.NUMA.Nodes) == 0 {
+		return false
+	}
+	for _, node := range state.NUMA.Nodes {
+		if node.TotalBytes == 0 || len(node.CPUs) == 0 {
+			continue
+		}
+		if limits.MaxCPUPercent > 0 && state.NodeCPUPercent(node) > limits.MaxCPUPercent {
+			continue
+		}
+		if limits.MaxMemoryPercent > 0 && node.MemoryPercent(thresholds.Memory.Mode) > limits.MaxMemoryPercent {
+			continue
+		}
+		return false
+	}
+	return true
+}
+
 func (c *ThresholdChecker) UpdateThresholds(thresholds config.ThresholdsConfig) {
 	c.mu.Lock()
 	c.thresholds = thresholds
@@ -4,6 +4,7 @@ import (
 	"testing"
 
 	"github.com/haskel/capfox/internal/config"
+	"github.com/haskel/capfox/internal/decision"
 	"github.com/haskel/capfox/internal/monitor"
 )
 
@@ -90,6 +91,93 @@ func TestThresholdChecker_StorageLow(t *testing.T) {
 	}
 }
 
+func TestThresholdChecker_StorageHealth(t *testing.T) {
+	thresholds := defaultThresholds()
+	thresholds.Storage.MinFreeInodesPercent = 5
+	checker := NewThresholdChecker(thresholds)
+
+	healthy := monitor.DiskState{
+		UsedBytes:   100 * 1024 * 1024 * 1024,
+		TotalBytes:  500 * 1024 * 1024 * 1024,
+		InodesUsed:  1000,
+		InodesTotal: 100000,
+	}
+
+	tests := []struct {
+		name string
+		disk monitor.DiskState
+		want []Reason
+	}{
+		{"healthy", healthy, nil},
+		{"inodes low", monitor.DiskState{
+			UsedBytes:   healthy.UsedBytes,
+			TotalBytes:  healthy.TotalBytes,
+			InodesUsed:  99000,
+			InodesTotal: 100000,
+		}, []Reason{ReasonStorageInodesLow}},
+		{"no inode table", monitor.DiskState{
+			UsedBytes:  healthy.UsedBytes,
+			TotalBytes: healthy.TotalBytes,
+		}, nil},
+		{"read-only", monitor.DiskState{
+			UsedBytes:  healthy.UsedBytes,
+			TotalBytes: healthy.TotalBytes,
+			ReadOnly:   true,
+		}, []Reason{ReasonStorageReadOnly}},
+		{"unavailable", monitor.DiskState{Error: "no such file or directory"}, []Reason{ReasonStorageUnavailable}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &monitor.SystemState{
+				CPU:    monitor.CPUState{UsagePercent: 50},
+				Memory: monitor.MemoryState{UsagePercent: 50},
+				Storage: monitor.StorageState{
+					"/":     healthy,
+					"/data": tt.disk,
+				},
+			}
+
+			reasons := checker.Check(state)
+
+			if len(reasons) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, reasons)
+			}
+			for i := range tt.want {
+				if reasons[i] != tt.want[i] {
+					t.Errorf("expected %v, got %v", tt.want, reasons)
+				}
+			}
+		})
+	}
+}
+
+func TestThresholdChecker_MemoryMode(t *testing.T) {
+	// 40% used, but page cache and shmem leave only 10% available
+	state := &monitor.SystemState{
+		CPU: monitor.CPUState{UsagePercent: 50},
+		Memory: monitor.MemoryState{
+			TotalBytes:     1000,
+			UsedBytes:      400,
+			UsagePercent:   40,
+			AvailableBytes: 100,
+			AvailableKnown: true,
+		},
+	}
+
+	thresholds := defaultThresholds()
+	thresholds.Memory.Mode = "used"
+	if reasons := NewThresholdChecker(thresholds).Check(state); len(reasons) != 0 {
+		t.Errorf("expected no reasons in used mode, got %v", reasons)
+	}
+
+	thresholds.Memory.Mode = "available"
+	reasons := NewThresholdChecker(thresholds).Check(state)
+	if len(reasons) != 1 || reasons[0] != ReasonMemoryOverload {
+		t.Errorf("expected [memory_overload] in available mode, got %v", reasons)
+	}
+}
+
 func TestThresholdChecker_GPUOverload(t *testing.T) {
 	checker := NewThresholdChecker(defaultThresholds())
 
@@ -133,6 +221,362 @@ func TestThresholdChecker_VRAMOverload(t *testing.T) {
 	}
 }
 
+func TestThresholdChecker_GPUsIndependent(t *testing.T) {
+	thresholds := defaultThresholds()
+	thresholds.GPU.Devices = map[int]config.GPUDeviceThreshold{
+		2: {MaxPercent: 50},
+	}
+	checker := NewThresholdChecker(thresholds)
+
+	state := &monitor.SystemState{
+		CPU:    monitor.CPUState{UsagePercent: 50},
+		Memory: monitor.MemoryState{UsagePercent: 50},
+		GPUs: []monitor.GPUState{
+			{Index: 0, UsagePercent: 95},
+			{Index: 1, UsagePercent: 70},
+			{Index: 2, UsagePercent: 60}, // over its own 50% limit
+			{Index: 3, UsagePercent: 20},
+		},
+	}
+
+	if reasons := checker.Check(state); len(reasons) != 0 {
+		t.Errorf("expected no reasons while a GPU fits, got %v", reasons)
+	}
+
+	gpus := decision.ThresholdsFromConfig(thresholds).PlaceGPUs(state.GPUs, 0, 0).Fitting
+	if len(gpus) != 2 || gpus[0] != 3 || gpus[1] != 1 {
+		t.Errorf("expected GPUs [3 1], got %v", gpus)
+	}
+
+	// VRAM of the idle GPU is full
+	state.GPUs[3].VRAMUsedBytes, state.GPUs[3].VRAMTotalBytes = 95, 100
+	state.GPUs[1].UsagePercent = 92
+
+	reasons := checker.Check(state)
+	if len(reasons) != 2 || reasons[0] != ReasonGPUOverload || reasons[1] != ReasonVRAMOverload {
+		t.Errorf("expected [gpu_overload vram_overload], got %v", reasons)
+	}
+}
+
+func TestThresholdChecker_Swap(t *testing.T) {
+	thresholds := defaultThresholds()
+	thresholds.Swap = config.SwapThreshold{MaxPercent: 50, MaxPagesPerSec: 1000}
+	checker := NewThresholdChecker(thresholds)
+
+	tests := []struct {
+		name string
+		swap monitor.SwapState
+		want []Reason
+	}{
+		{"idle", monitor.SwapState{UsagePercent: 10, PagesInPerSec: 10}, nil},
+		{"full", monitor.SwapState{UsagePercent: 60}, []Reason{ReasonSwapOverload}},
+		{"thrashing", monitor.SwapState{UsagePercent: 20, PagesInPerSec: 600, PagesOutPerSec: 600}, []Reason{ReasonSwapThrashing}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &monitor.SystemState{
+				CPU:     monitor.CPUState{UsagePercent: 50},
+				Memory:  monitor.MemoryState{UsagePercent: 50},
+				Storage: monitor.StorageState{},
+				Swap:    tt.swap,
+			}
+
+			reasons := checker.Check(state)
+
+			if len(reasons) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, reasons)
+			}
+			for i := range reasons {
+				if reasons[i] != tt.want[i] {
+					t.Errorf("expected %v, got %v", tt.want, reasons)
+				}
+			}
+		})
+	}
+}
+
+func TestThresholdChecker_IO(t *testing.T) {
+	thresholds := defaultThresholds()
+	thresholds.IO = config.IOThreshold{
+		MaxUtilPercent: 80,
+		Paths:          map[string]float64{"/data": 95},
+	}
+	checker := NewThresholdChecker(thresholds)
+
+	tests := []struct {
+		name     string
+		rootUtil float64
+		dataUtil float64
+		want     bool
+	}{
+		{"idle", 20, 20, false},
+		{"root saturated", 85, 20, true},
+		{"data within override", 20, 90, false},
+		{"data saturated", 20, 97, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &monitor.SystemState{
+				CPU:    monitor.CPUState{UsagePercent: 50},
+				Memory: monitor.MemoryState{UsagePercent: 50},
+				IO: monitor.IOState{
+					Devices: map[string]monitor.DeviceIOState{
+						"nvme0n1p2": {UtilPercent: tt.rootUtil},
+						"sdb1":      {UtilPercent: tt.dataUtil},
+					},
+					Paths: map[string]string{"/": "nvme0n1p2", "/data": "sdb1"},
+				},
+			}
+
+			reasons := checker.Check(state)
+
+			if tt.want && (len(reasons) != 1 || reasons[0] != ReasonIOSaturated) {
+				t.Errorf("expected [io_saturated], got %v", reasons)
+			}
+			if !tt.want && len(reasons) != 0 {
+				t.Errorf("expected no reasons, got %v", reasons)
+			}
+		})
+	}
+}
+
+func TestThresholdChecker_Network(t *testing.T) {
+	thresholds := defaultThresholds()
+	thresholds.Network = config.NetworkThreshold{MaxPercent: 80}
+	checker := NewThresholdChecker(thresholds)
+
+	tests := []struct {
+		name string
+		rx   float64
+		tx   float64
+		want bool
+	}{
+		{"idle", 10, 10, false},
+		{"rx saturated", 85, 10, true},
+		{"tx saturated", 10, 90, true},
+		{"at limit", 80, 80, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &monitor.SystemState{
+				CPU:    monitor.CPUState{UsagePercent: 50},
+				Memory: monitor.MemoryState{UsagePercent: 50},
+				Network: monitor.NetworkState{
+					"eth0": {RxPercent: tt.rx, TxPercent: tt.tx},
+					"eth1": {RxPercent: 5},
+				},
+			}
+
+			reasons := checker.Check(state)
+
+			if tt.want && (len(reasons) != 1 || reasons[0] != ReasonNetworkSaturated) {
+				t.Errorf("expected [network_saturated], got %v", reasons)
+			}
+			if !tt.want && len(reasons) != 0 {
+				t.Errorf("expected no reasons, got %v", reasons)
+			}
+		})
+	}
+}
+
+func TestThresholdChecker_Thermal(t *testing.T) {
+	thresholds := defaultThresholds()
+	thresholds.Thermal = config.ThermalThreshold{MaxCelsius: 90}
+	thresholds.GPU.MaxCelsius = 85
+	checker := NewThresholdChecker(thresholds)
+
+	tests := []struct {
+		name      string
+		cpuTemp   float64
+		throttles float64
+		gpuTemp   int
+		want      bool
+	}{
+		{"cool", 60, 0, 70, false},
+		{"cpu hot", 95, 0, 70, true},
+		{"throttling", 80, 2, 70, true},
+		{"gpu hot", 60, 0, 88, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := &monitor.SystemState{
+				CPU:    monitor.CPUState{UsagePercent: 50},
+				Memory: monitor.MemoryState{UsagePercent: 50},
+				GPUs:   []monitor.GPUState{{Index: 0, UsagePercent: 10, Temperature: tt.gpuTemp}},
+				Thermal: monitor.ThermalState{
+					Sensors:              map[string]float64{"coretemp/Package id 0": tt.cpuTemp},
+					ThrottleEventsPerSec: tt.throttles,
+				},
+			}
+
+			reasons := checker.Check(state)
+
+			if tt.want && (len(reasons) != 1 || reasons[0] != ReasonThermalThrottling) {
+				t.Errorf("expected [thermal_throttling], got %v", reasons)
+			}
+			if !tt.want && len(reasons) != 0 {
+				t.Errorf("expected no reasons, got %v", reasons)
+			}
+		})
+	}
+}
+
+func TestThresholdChecker_ThermalDisabled(t *testing.T) {
+	checker := NewThresholdChecker(defaultThresholds())
+
+	state := &monitor.SystemState{
+		CPU:    monitor.CPUState{UsagePercent: 50},
+		Memory: monitor.MemoryState{UsagePercent: 50},
+		GPUs:   []monitor.GPUState{{Index: 0, UsagePercent: 10, Temperature: 99}},
+		Thermal: monitor.ThermalState{
+			Sensors:              map[string]float64{"acpitz": 105},
+			ThrottleEventsPerSec: 50,
+		},
+	}
+
+	if reasons := checker.Check(state); len(reasons) != 0 {
+		t.Errorf("expected no reasons with thermal checks disabled, got %v", reasons)
+	}
+}
+
+func TestThresholdChecker_NUMA(t *testing.T) {
+	thresholds := defaultThresholds()
+	thresholds.NUMA = config.NUMAThreshold{MaxMemoryPercent: 80}
+	checker := NewThresholdChecker(thresholds)
+
+	state := func(node1Used uint64) *monitor.SystemState {
+		return &monitor.SystemState{
+			CPU:    monitor.CPUState{UsagePercent: 50, Cores: []float64{50, 50}},
+			Memory: monitor.MemoryState{UsagePercent: 70},
+			NUMA: monitor.NUMAState{
+				Nodes: []monitor.NUMANodeState{
+					{ID: 0, CPUs: []int{0}, TotalBytes: 100, UsedBytes: 90},
+					{ID: 1, CPUs: []int{1}, TotalBytes: 100, UsedBytes: node1Used},
+				},
+			},
+		}
+	}
+
+	if reasons := checker.Check(state(50)); len(reasons) != 0 {
+		t.Errorf("expected no reasons while node1 fits, got %v", reasons)
+	}
+
+	reasons := checker.Check(state(85))
+	if len(reasons) != 1 || reasons[0] != ReasonNUMAOverload {
+		t.Errorf("expected [numa_overload], got %v", reasons)
+	}
+}
+
+func TestThresholdChecker_MetricsStale(t *testing.T) {
+	thresholds := defaultThresholds()
+	checker := NewThresholdChecker(thresholds)
+
+	state := &monitor.SystemState{
+		CPU:    monitor.CPUState{UsagePercent: 50},
+		Memory: monitor.MemoryState{UsagePercent: 50},
+		Health: monitor.HealthState{
+			"cpu":     {},
+			"storage": {Stale: true, ConsecutiveFailures: 3, LastError: "collection timed out after 5s"},
+		},
+	}
+
+	if reasons := checker.Check(state); len(reasons) != 0 {
+		t.Errorf("expected no reasons with health check disabled, got %v", reasons)
+	}
+
+	thresholds.Health.MaxFailures = 3
+	checker.UpdateThresholds(thresholds)
+	reasons := checker.Check(state)
+	if len(reasons) != 1 || reasons[0] != ReasonMetricsStale {
+		t.Errorf("expected [metrics_stale], got %v", reasons)
+	}
+
+	thresholds.Health.MaxFailures = 4
+	checker.UpdateThresholds(thresholds)
+	if reasons := checker.Check(state); len(reasons) != 0 {
+		t.Errorf("expected no reasons below max_failures, got %v", reasons)
+	}
+}
+
+func TestThresholdChecker_Custom(t *testing.T) {
+	maxBacklog, minSeats := 100.0, 1.0
+	thresholds := defaultThresholds()
+	thresholds.Custom = map[string]config.CustomThreshold{
+		"render_backlog":     {Max: &maxBacklog},
+		"license_seats_free": {Min: &minSeats},
+	}
+	checker := NewThresholdChecker(thresholds)
+
+	state := func(custom monitor.CustomState) *monitor.SystemState {
+		return &monitor.SystemState{
+			CPU:    monitor.CPUState{UsagePercent: 50},
+			Memory: monitor.MemoryState{UsagePercent: 50},
+			Custom: custom,
+		}
+	}
+
+	if reasons := checker.Check(state(monitor.CustomState{"render_backlog": 100, "license_seats_free": 1})); len(reasons) != 0 {
+		t.Errorf("expected no reasons at the limits, got %v", reasons)
+	}
+
+	reasons := checker.Check(state(monitor.CustomState{"render_backlog": 250, "license_seats_free": 0}))
+	if len(reasons) != 2 || reasons[0] != "custom:license_seats_free" || reasons[1] != "custom:render_backlog" {
+		t.Errorf("expected [custom:license_seats_free custom:render_backlog], got %v", reasons)
+	}
+
+	// A metric that was never reported fails closed
+	reasons = checker.Check(state(monitor.CustomState{"render_backlog": 10}))
+	if len(reasons) != 1 || reasons[0] != "custom:license_seats_free" {
+		t.Errorf("expected [custom:license_seats_free], got %v", reasons)
+	}
+}
+
+func TestThresholdChecker_Pressure(t *testing.T) {
+	thresholds := defaultThresholds()
+	thresholds.Pressure = config.PressureThreshold{
+		Window: "avg10",
+		CPU:    config.PressureLimit{MaxSome: 50},
+		Memory: config.PressureLimit{MaxFull: 10},
+		IO:     config.PressureLimit{MaxSome: 40, MaxFull: 20},
+	}
+	checker := NewThresholdChecker(thresholds)
+
+	state := &monitor.SystemState{
+		CPU:     monitor.CPUState{UsagePercent: 50},
+		Memory:  monitor.MemoryState{UsagePercent: 50},
+		Storage: monitor.StorageState{},
+		Pressure: monitor.PressureState{
+			CPU:    monitor.PressureResource{Some: monitor.PressureStats{Avg10: 60}},
+			Memory: monitor.PressureResource{Some: monitor.PressureStats{Avg10: 90}, Full: monitor.PressureStats{Avg10: 5}},
+			IO:     monitor.PressureResource{Full: monitor.PressureStats{Avg10: 25, Avg60: 5}},
+		},
+	}
+
+	reasons := checker.Check(state)
+
+	expected := []Reason{ReasonCPUPressure, ReasonIOPressure}
+	if len(reasons) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, reasons)
+	}
+	for i := range expected {
+		if reasons[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, reasons)
+		}
+	}
+
+	// avg60 window only sees the slower averages
+	thresholds.Pressure.Window = "avg60"
+	checker.UpdateThresholds(thresholds)
+
+	if reasons := checker.Check(state); len(reasons) != 0 {
+		t.Errorf("expected no reasons for avg60 window, got %v", reasons)
+	}
+}
+
 func TestThresholdChecker_MultipleReasons(t *testing.T) {
 	checker := NewThresholdChecker(defaultThresholds())
 
@@ -7,6 +7,8 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+
+	"github.com/haskel/capfox/internal/config"
 )
 
 var askCmd = &cobra.Command{
@@ -17,7 +19,8 @@ var askCmd = &cobra.Command{
 Examples:
   capfox ask video_encoding
   capfox ask video_encoding --complexity 100
-  capfox ask ml_training --complexity 500 --reason`,
+  capfox ask ml_training --complexity 500 --reason
+  capfox ask backup --priority low`,
 	Args: cobra.ExactArgs(1),
 	RunE: runAsk,
 }
@@ -29,6 +32,7 @@ var (
 	memEst     float64
 	gpuEst     float64
 	vramEst    float64
+	priority   string
 )
 
 func init() {
@@ -38,6 +42,7 @@ func init() {
 	askCmd.Flags().Float64Var(&memEst, "mem", 0, "estimated memory usage percent")
 	askCmd.Flags().Float64Var(&gpuEst, "gpu", 0, "estimated GPU usage percent")
 	askCmd.Flags().Float64Var(&vramEst, "vram", 0, "estimated VRAM usage percent")
+	askCmd.Flags().StringVar(&priority, "priority", "", "priority class: low, normal, high, or -1, 0, 1")
 	rootCmd.AddCommand(askCmd)
 }
 
@@ -45,6 +50,7 @@ type askRequest struct {
 	Task       string            `json:"task"`
 	Complexity int               `json:"complexity,omitempty"`
 	Resources  *resourceEstimate `json:"resources,omitempty"`
+	Priority   config.Priority   `json:"priority,omitempty"`
 }
 
 type resourceEstimate struct {
@@ -55,16 +61,24 @@ type resourceEstimate struct {
 }
 
 type askResponse struct {
-	Allowed bool     `json:"allowed"`
-	Reasons []string `json:"reasons,omitempty"`
+	Allowed        bool     `json:"allowed"`
+	Reasons        []string `json:"reasons,omitempty"`
+	GPUs           []int    `json:"gpus,omitempty"`
+	VisibleDevices string   `json:"visible_devices,omitempty"`
 }
 
 func runAsk(cmd *cobra.Command, args []string) error {
 	task := args[0]
 
+	class, err := parsePriorityFlag(priority)
+	if err != nil {
+		return err
+	}
+
 	req := askRequest{
 		Task:       task,
 		Complexity: complexity,
+		Priority:   class,
 	}
 
 	// Add resource estimates if provided
@@ -117,3 +131,16 @@ func runAsk(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// parsePriorityFlag validates a --priority value. Empty leaves the class to
+// the server.
+func parsePriorityFlag(value string) (config.Priority, error) {
+	if value == "" {
+		return "", nil
+	}
+	class, err := config.ParsePriority(value)
+	if err != nil {
+		return "", fmt.Errorf("--priority: %w", err)
+	}
+	return class, nil
+}
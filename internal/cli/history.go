@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show recent metric history",
+	Long: `Query the capfox server for recorded metrics, averaged per step.
+
+Examples:
+  capfox history                              # CPU and memory, last 15 minutes
+  capfox history --since 1h --step 5m
+  capfox history --metrics cpu,io_util,memory_pressure --json`,
+	Args: cobra.NoArgs,
+	RunE: runHistory,
+}
+
+var (
+	historySince   time.Duration
+	historyStep    time.Duration
+	historyMetrics string
+)
+
+func init() {
+	historyCmd.Flags().DurationVar(&historySince, "since", 15*time.Minute, "how far back to show")
+	historyCmd.Flags().DurationVar(&historyStep, "step", time.Minute, "averaging step, 0 for raw samples")
+	historyCmd.Flags().StringVar(&historyMetrics, "metrics", "cpu,memory", "comma-separated metrics")
+	rootCmd.AddCommand(historyCmd)
+}
+
+type historyResponse struct {
+	Timestamps []int64              `json:"timestamps"`
+	Series     map[string][]float64 `json:"series"`
+}
+
+func runHistory(cmd *cobra.Command, args []string) error {
+	client := NewClient()
+
+	query := url.Values{}
+	query.Set("from", strconv.FormatInt(time.Now().Add(-historySince).Unix(), 10))
+	query.Set("step", historyStep.String())
+	if historyMetrics != "" {
+		query.Set("metrics", historyMetrics)
+	}
+
+	data, status, err := client.Get("/v2/history?" + query.Encode())
+	if err != nil {
+		return fmt.Errorf("failed to get history: %w", err)
+	}
+
+	if status != http.StatusOK {
+		return fmt.Errorf("server returned status %d: %s", status, strings.TrimSpace(string(data)))
+	}
+
+	if jsonOut {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	var resp historyResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+
+	if len(resp.Timestamps) == 0 {
+		fmt.Println("No history recorded yet")
+		return nil
+	}
+
+	// Keep the requested column order
+	names := strings.Split(historyMetrics, ",")
+	if historyMetrics == "" {
+		names = names[:0]
+		for name := range resp.Series {
+			names = append(names, name)
+		}
+	}
+
+	fmt.Printf("%-8s", "TIME")
+	for _, name := range names {
+		fmt.Printf(" %16s", name)
+	}
+	fmt.Println()
+
+	for i, ts := range resp.Timestamps {
+		fmt.Printf("%-8s", time.UnixMilli(ts).Format("15:04:05"))
+		for _, name := range names {
+			if values := resp.Series[name]; i < len(values) {
+				fmt.Printf(" %16.1f", values[i])
+			} else {
+				fmt.Printf(" %16s", "-")
+			}
+		}
+		fmt.Println()
+	}
+
+	return nil
+}
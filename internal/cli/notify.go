@@ -29,11 +29,14 @@ func init() {
 type notifyRequest struct {
 	Task       string `json:"task"`
 	Complexity int    `json:"complexity,omitempty"`
+	Attributed bool   `json:"attributed,omitempty"`
 }
 
 type notifyResponse struct {
-	Received bool   `json:"received"`
-	Task     string `json:"task"`
+	Received       bool   `json:"received"`
+	Task           string `json:"task"`
+	TaskID         string `json:"task_id,omitempty"`
+	ObserveAfterMS int64  `json:"observe_after_ms,omitempty"`
 }
 
 func runNotify(cmd *cobra.Command, args []string) error {
@@ -3,11 +3,16 @@ package cli
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
+
+	"github.com/haskel/capfox/internal/monitor"
 )
 
 var runCmd = &cobra.Command{
@@ -16,6 +21,10 @@ var runCmd = &cobra.Command{
 	Long: `Run a command only if server has available capacity.
 Works like 'time' or 'nice' - wrap any command with capfox run.
 
+On GPU hosts the command only sees the GPUs that fit: CUDA_VISIBLE_DEVICES
+and HIP_VISIBLE_DEVICES are set to the indices returned by the server, most
+headroom first. Variables that are already set are left alone.
+
 Exit codes:
   0-125  Command's exit code
   75     No capacity available (command not started)
@@ -24,7 +33,8 @@ Exit codes:
 	Example: `  capfox run ./script.sh
   capfox run --task ml python train.py
   capfox run --complexity 100 make build
-  capfox run --cpu 50 --mem 30 ./heavy.sh`,
+  capfox run --cpu 50 --mem 30 ./heavy.sh
+  capfox run --priority low ./nightly-report.sh`,
 	Args: cobra.MinimumNArgs(1),
 	RunE: runRun,
 }
@@ -38,6 +48,8 @@ var (
 	runVRAM       float64
 	runReason     bool
 	runQuiet      bool
+	runNoGPUEnv   bool
+	runPriority   string
 )
 
 func init() {
@@ -49,6 +61,8 @@ func init() {
 	runCmd.Flags().Float64Var(&runVRAM, "vram", 0, "estimated VRAM usage percent")
 	runCmd.Flags().BoolVar(&runReason, "reason", false, "show denial reasons")
 	runCmd.Flags().BoolVar(&runQuiet, "quiet", false, "suppress capfox output")
+	runCmd.Flags().BoolVar(&runNoGPUEnv, "no-gpu-env", false, "do not restrict the command to the GPUs that fit")
+	runCmd.Flags().StringVar(&runPriority, "priority", "", "priority class: low, normal, high, or -1, 0, 1")
 	rootCmd.AddCommand(runCmd)
 }
 
@@ -66,9 +80,15 @@ func runRun(cmd *cobra.Command, args []string) error {
 	}
 
 	// 2. Build ask request
+	class, err := parsePriorityFlag(runPriority)
+	if err != nil {
+		return err
+	}
+
 	req := askRequest{
 		Task:       taskName,
 		Complexity: runComplexity,
+		Priority:   class,
 	}
 
 	// Add resource estimates if provided
@@ -81,18 +101,17 @@ func runRun(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// 3. Call /ask endpoint
+	// 3. Ask the decision engine, which also places the command on GPUs
 	client := NewClient()
 
-	path := "/ask?reason=true"
-	data, _, err := client.Post(path, req)
+	data, err := askRun(client, req)
 	if err != nil {
 		if !runQuiet {
 			fmt.Fprintf(os.Stderr, "capfox: failed to check capacity: %v\n", err)
 		}
 		// If we can't reach the server, still try to run the command
 		// This is a design decision - fail open
-		return executeCommand(args)
+		return executeCommand(args, nil)
 	}
 
 	var resp askResponse
@@ -100,7 +119,7 @@ func runRun(cmd *cobra.Command, args []string) error {
 		if !runQuiet {
 			fmt.Fprintf(os.Stderr, "capfox: failed to parse response: %v\n", err)
 		}
-		return executeCommand(args)
+		return executeCommand(args, nil)
 	}
 
 	// 4. If denied, exit with code 75
@@ -116,14 +135,19 @@ func runRun(cmd *cobra.Command, args []string) error {
 		os.Exit(exitNoCapacity)
 	}
 
-	// 5. Notify server about task start (only if complexity is specified)
+	// 5. Notify server about task start (only if complexity is specified).
+	// The usage of the command's process tree is reported for learning.
+	var notified notifyResponse
 	if runComplexity > 0 {
 		notifyReq := notifyRequest{
 			Task:       taskName,
 			Complexity: runComplexity,
+			Attributed: true,
 		}
 		// Fire-and-forget: ignore errors
-		_, _, _ = client.Post("/task/notify", notifyReq)
+		if data, status, err := client.Post("/task/notify", notifyReq); err == nil && status == http.StatusOK {
+			_ = json.Unmarshal(data, &notified)
+		}
 	}
 
 	// 6. If allowed, execute command
@@ -131,16 +155,131 @@ func runRun(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "capfox: allowed\n")
 	}
 
-	return executeCommand(args)
+	var env []string
+	if !runNoGPUEnv {
+		env = gpuEnv(resp.VisibleDevices)
+	}
+
+	if notified.TaskID != "" {
+		return executeTracked(client, args, env, notified)
+	}
+	return executeCommand(args, env)
 }
 
-func executeCommand(args []string) error {
+// askRun posts req to /v2/ask, whose GPUs fit the predicted impact of the
+// task, and falls back to /ask on servers without the decision engine.
+func askRun(client *Client, req askRequest) ([]byte, error) {
+	data, status, err := client.Post("/v2/ask", req)
+	if err != nil {
+		return nil, err
+	}
+	// A denial is JSON, a missing decision engine is not
+	if status == http.StatusNotFound || (status == http.StatusServiceUnavailable && !json.Valid(data)) {
+		data, _, err = client.Post("/ask?reason=true", req)
+	}
+	return data, err
+}
+
+// gpuEnv returns the variables restricting a command to the given GPUs.
+// Variables set by the caller take precedence.
+func gpuEnv(visibleDevices string) []string {
+	if visibleDevices == "" {
+		return nil
+	}
+
+	vars := []string{
+		"CUDA_VISIBLE_DEVICES=" + visibleDevices,
+		"HIP_VISIBLE_DEVICES=" + visibleDevices,
+		// Indices follow the PCI bus order of nvidia-smi, CUDA defaults
+		// to fastest first
+		"CUDA_DEVICE_ORDER=PCI_BUS_ID",
+	}
+
+	var env []string
+	for _, v := range vars {
+		name, _, _ := strings.Cut(v, "=")
+		if _, ok := os.LookupEnv(name); !ok {
+			env = append(env, v)
+		}
+	}
+	return env
+}
+
+// trackInterval is how often the process tree of a command is sampled.
+const trackInterval = 500 * time.Millisecond
+
+// usageRequest is the request body for POST /task/usage.
+type usageRequest struct {
+	TaskID string `json:"task_id"`
+	monitor.ProcessUsage
+}
+
+// executeTracked runs the command like executeCommand while measuring its
+// process tree. The usage is reported once the server's observation delay
+// has passed, or when the command exits if that is earlier.
+func executeTracked(client *Client, args, env []string, task notifyResponse) error {
+	execCmd := newCommand(args, env)
+	if err := execCmd.Start(); err != nil {
+		return exitOnError(err)
+	}
+
+	tracker := monitor.NewProcessTracker(monitor.DefaultProcRoot, execCmd.Process.Pid)
+	_ = tracker.Sample()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- execCmd.Wait()
+	}()
+
+	report := func() {
+		// Fire-and-forget: ignore errors
+		_, _, _ = client.Post("/task/usage", usageRequest{
+			TaskID:       task.TaskID,
+			ProcessUsage: tracker.Usage(),
+		})
+	}
+
+	observe := time.NewTimer(time.Duration(task.ObserveAfterMS) * time.Millisecond)
+	defer observe.Stop()
+	ticker := time.NewTicker(trackInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			_ = tracker.Sample()
+		case <-observe.C:
+			_ = tracker.Sample()
+			report()
+			return exitOnError(<-done)
+		case err := <-done:
+			tracker.Finish(execCmd.ProcessState)
+			report()
+			return exitOnError(err)
+		}
+	}
+}
+
+func executeCommand(args, env []string) error {
+	return exitOnError(newCommand(args, env).Run())
+}
+
+// newCommand creates the command with the given variables added to the
+// environment of capfox.
+func newCommand(args, env []string) *exec.Cmd {
 	execCmd := exec.Command(args[0], args[1:]...)
 	execCmd.Stdin = os.Stdin
 	execCmd.Stdout = os.Stdout
 	execCmd.Stderr = os.Stderr
+	if len(env) > 0 {
+		execCmd.Env = append(os.Environ(), env...)
+	}
+	return execCmd
+}
 
-	err := execCmd.Run()
+// exitOnError exits with the code matching a failed command, see the exit
+// codes of runCmd.
+func exitOnError(err error) error {
 	if err != nil {
 		if exitErr, ok := err.(*exec.ExitError); ok {
 			os.Exit(exitErr.ExitCode())
@@ -1,10 +1,15 @@
 package cli
 
 import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"testing"
+
+	"github.com/haskel/capfox/internal/config"
 )
 
 func TestRunCmd_Exists(t *testing.T) {
@@ -34,6 +39,8 @@ func TestRunCmd_Flags(t *testing.T) {
 		{"vram flag", "vram"},
 		{"reason flag", "reason"},
 		{"quiet flag", "quiet"},
+		{"no-gpu-env flag", "no-gpu-env"},
+		{"priority flag", "priority"},
 	}
 
 	for _, tt := range tests {
@@ -58,6 +65,99 @@ func TestExitCodes(t *testing.T) {
 	}
 }
 
+func TestGPUEnv(t *testing.T) {
+	if env := gpuEnv(""); env != nil {
+		t.Errorf("expected no variables without GPUs, got %v", env)
+	}
+
+	t.Setenv("HIP_VISIBLE_DEVICES", "3")
+	env := gpuEnv("2,0")
+
+	expected := []string{"CUDA_VISIBLE_DEVICES=2,0", "CUDA_DEVICE_ORDER=PCI_BUS_ID"}
+	if len(env) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, env)
+	}
+	for i := range expected {
+		if env[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected, env)
+		}
+	}
+}
+
+func TestAskRun(t *testing.T) {
+	tests := []struct {
+		name     string
+		v2       http.HandlerFunc
+		wantGPUs string
+	}{
+		{
+			name: "decision engine",
+			v2: func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"allowed": true, "gpus": [2], "visible_devices": "2"}`))
+			},
+			wantGPUs: "2",
+		},
+		{
+			name: "decision engine not enabled",
+			v2: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "decision engine not enabled", http.StatusServiceUnavailable)
+			},
+			wantGPUs: "0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("POST /v2/ask", tt.v2)
+			mux.HandleFunc("POST /ask", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte(`{"allowed": true, "gpus": [0], "visible_devices": "0"}`))
+			})
+			srv := httptest.NewServer(mux)
+			defer srv.Close()
+
+			data, err := askRun(&Client{baseURL: srv.URL, client: srv.Client()}, askRequest{Task: "train"})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var resp askResponse
+			if err := json.Unmarshal(data, &resp); err != nil {
+				t.Fatalf("failed to parse response: %v", err)
+			}
+			if !resp.Allowed || resp.VisibleDevices != tt.wantGPUs {
+				t.Errorf("expected GPUs %s, got %+v", tt.wantGPUs, resp)
+			}
+		})
+	}
+}
+
+func TestParsePriorityFlag(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected config.Priority
+		wantErr  bool
+	}{
+		{"", "", false},
+		{"low", config.PriorityLow, false},
+		{"HIGH", config.PriorityHigh, false},
+		{"-1", config.PriorityLow, false},
+		{"-5", "", true},
+		{"urgent", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parsePriorityFlag(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
 func TestTaskNameDerivation(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -90,7 +190,7 @@ func TestTaskNameDerivation(t *testing.T) {
 func TestExecuteCommand_Success(t *testing.T) {
 	if os.Getenv("TEST_SUBPROCESS") == "1" {
 		// This is the subprocess - run executeCommand
-		err := executeCommand([]string{"true"})
+		err := executeCommand([]string{"true"}, nil)
 		if err != nil {
 			os.Exit(1)
 		}
@@ -111,7 +211,7 @@ func TestExecuteCommand_ExitCode(t *testing.T) {
 	if os.Getenv("TEST_SUBPROCESS") == "1" {
 		code := os.Getenv("TEST_EXIT_CODE")
 		// executeCommand will call os.Exit with the command's exit code
-		_ = executeCommand([]string{"sh", "-c", "exit " + code})
+		_ = executeCommand([]string{"sh", "-c", "exit " + code}, nil)
 		return
 	}
 
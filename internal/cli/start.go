@@ -3,30 +3,51 @@ package cli
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/haskel/capfox/internal/capacity"
 	"github.com/haskel/capfox/internal/config"
+	"github.com/haskel/capfox/internal/decision"
+	"github.com/haskel/capfox/internal/decision/model"
+	"github.com/haskel/capfox/internal/decision/scheduler"
+	"github.com/haskel/capfox/internal/decision/strategy"
 	"github.com/haskel/capfox/internal/learning"
 	"github.com/haskel/capfox/internal/logger"
 	"github.com/haskel/capfox/internal/monitor"
 	"github.com/haskel/capfox/internal/server"
 	"github.com/haskel/capfox/internal/storage"
+	"github.com/haskel/capfox/internal/trace"
 )
 
 var startCmd = &cobra.Command{
 	Use:   "start",
 	Short: "Start the capfox server",
-	Long:  `Start the capfox server in foreground mode.`,
-	RunE:  runStart,
+	Long: `Start the capfox server in foreground mode.
+
+With --replay, snapshots of a trace recorded through trace.path are fed to
+the decision engine instead of the host's metrics.
+
+Examples:
+  capfox start
+  capfox start --replay trace.ndjson --replay-speed 10`,
+	RunE: runStart,
 }
 
+var (
+	replayPath  string
+	replaySpeed float64
+)
+
 func init() {
+	startCmd.Flags().StringVar(&replayPath, "replay", "", "replay snapshots from an NDJSON trace instead of monitoring the host")
+	startCmd.Flags().Float64Var(&replaySpeed, "replay-speed", 1, "replay speed multiplier, e.g. 10 for ten times faster")
 	rootCmd.AddCommand(startCmd)
 }
 
@@ -50,17 +71,62 @@ func runStart(cmd *cobra.Command, args []string) error {
 		"config", cfgFile,
 	)
 
-	// Create monitors
-	monitors := []monitor.Monitor{
-		monitor.NewCPUMonitor(),
-		monitor.NewMemoryMonitor(),
-		monitor.NewStorageMonitor(cfg.Monitoring.Paths),
-		monitor.NewProcessMonitor(),
-		monitor.NewGPUMonitor(),
+	// Create monitors, or replay a recorded trace instead of the host
+	interval := cfg.MonitoringInterval()
+	var monitors []monitor.Monitor
+	if replayPath != "" {
+		replay, err := trace.NewReplayMonitor(replayPath, replaySpeed)
+		if err != nil {
+			return fmt.Errorf("failed to load replay: %w", err)
+		}
+		log.Info("replaying trace",
+			"path", replayPath,
+			"snapshots", replay.Len(),
+			"duration", replay.Duration().String(),
+			"speed", replaySpeed,
+		)
+		monitors = []monitor.Monitor{replay}
+		// Collect often enough to see every snapshot when accelerated
+		interval = time.Duration(float64(interval) / replaySpeed)
+	} else {
+		var err error
+		if monitors, err = createMonitors(cfg, log); err != nil {
+			return err
+		}
 	}
 
-	// Create aggregator
-	agg := monitor.NewAggregator(monitors, cfg.MonitoringInterval(), log)
+	registry := monitor.NewRegistry()
+	for _, m := range monitors {
+		if err := registry.Register(m); err != nil {
+			return fmt.Errorf("failed to register monitor: %w", err)
+		}
+	}
+
+	// Create aggregator, recording snapshots if history is enabled
+	var history *monitor.History
+	if samples := cfg.HistorySamples(); samples > 0 {
+		history = monitor.NewHistory(samples)
+	}
+	agg := monitor.NewAggregatorWithHistory(registry.Monitors(), interval, log, history)
+
+	smoothing, err := monitor.ParseSmoothingSpecs(cfg.Monitoring.Smoothing.Specs())
+	if err != nil {
+		return fmt.Errorf("invalid smoothing: %w", err)
+	}
+	agg.SetSmoothing(smoothing)
+	agg.SetTimeouts(cfg.CollectTimeouts())
+
+	// Record snapshots and API requests if tracing is enabled
+	var recorder *trace.Recorder
+	if cfg.Trace.Path != "" {
+		recorder, err = trace.NewRecorder(cfg.Trace.Path, log)
+		if err != nil {
+			return err
+		}
+		defer recorder.Close()
+		agg.SetObserver(recorder.RecordSnapshot)
+		log.Info("recording trace", "path", cfg.Trace.Path)
+	}
 
 	// Start aggregator
 	ctx, cancel := context.WithCancel(context.Background())
@@ -70,8 +136,15 @@ func runStart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to start aggregator: %w", err)
 	}
 
+	// Resolve per-task and per-priority threshold overrides
+	taskThresholds, err := config.NewTaskThresholds(cfg.Thresholds, cfg.Tasks, cfg.Priorities)
+	if err != nil {
+		return fmt.Errorf("failed to resolve task thresholds: %w", err)
+	}
+
 	// Create capacity manager
 	cm := capacity.NewManager(agg, cfg.Thresholds)
+	cm.SetTaskThresholds(taskThresholds)
 
 	// Create storage
 	store := storage.New(cfg.Persistence.DataDir, cfg.FlushInterval(), log)
@@ -81,15 +154,30 @@ func runStart(cmd *cobra.Command, args []string) error {
 		log.Warn("failed to load persisted data", "error", err)
 	}
 
-	// Create learning engine
-	model := learning.NewMovingAverageModel(0.2)
+	// Create prediction model shared by the learning and decision engines
+	modelFactory := model.NewFactory(model.Config{
+		Type:            model.ModelType(cfg.PredictionModel()),
+		MinObservations: cfg.Decision.MinObservations,
+		Alpha:           cfg.Decision.ModelParams.Alpha,
+	})
+	predictionModel, err := modelFactory.Create()
+	if err != nil {
+		return fmt.Errorf("failed to create prediction model: %w", err)
+	}
+
+	// Wrap model for the learning engine
+	adapter := learning.NewModelAdapter(predictionModel)
 
-	// Load persisted stats into model
-	savedStats := store.GetAllTaskStats()
-	if len(savedStats) > 0 {
+	// Load persisted model state, or seed the model from the task stats
+	// saved before the model was persisted
+	modelStore := storage.NewModelStorage(store)
+	if modelStore.ModelExists() {
+		if err := modelStore.LoadModel(predictionModel); err != nil {
+			log.Warn("failed to load persisted model", "error", err)
+		}
+	} else if savedStats := store.GetAllTaskStats(); len(savedStats) > 0 {
 		allStats := &learning.AllStats{
-			Tasks:      make(map[string]*learning.TaskStats),
-			TotalTasks: 0,
+			Tasks: make(map[string]*learning.TaskStats, len(savedStats)),
 		}
 		for task, data := range savedStats {
 			allStats.Tasks[task] = &learning.TaskStats{
@@ -102,16 +190,61 @@ func runStart(cmd *cobra.Command, args []string) error {
 			}
 			allStats.TotalTasks += data.Count
 		}
-		model.LoadStats(allStats)
-		log.Info("loaded persisted stats", "tasks", len(savedStats))
+		adapter.LoadStats(allStats)
+		log.Info("seeded model from persisted stats", "tasks", len(savedStats))
 	}
 
-	// Set observer to persist stats on update
-	model.SetObserver(func(task string, stats *learning.TaskStats) {
+	// Save the model with every stats flush, so a crash does not lose it
+	modelStore.SaveOnFlush(predictionModel)
+
+	// Persist stats on update
+	adapter.SetObserver(func(task string, stats *learning.TaskStats) {
 		store.UpdateTaskStats(task, stats.Count, stats.AvgCPUDelta, stats.AvgMemDelta, stats.AvgGPUDelta, stats.AvgVRAMDelta)
 	})
 
-	le := learning.NewEngine(model, agg, cfg.ObservationDelay(), log)
+	le := learning.NewEngine(adapter, agg, cfg.ObservationDelay(), log)
+	le.SetMemoryMode(cfg.Thresholds.Memory.Mode)
+
+	// Create decision strategy
+	strategyFactory := strategy.NewFactory(predictionModel, strategy.Config{
+		Type:             strategy.StrategyType(cfg.Decision.Strategy),
+		SafetyBufferPct:  cfg.Decision.SafetyBufferPercent,
+		FallbackStrategy: strategy.StrategyType(cfg.Decision.FallbackStrategy),
+		MinObservations:  cfg.Decision.MinObservations,
+	})
+	decisionStrategy, err := strategyFactory.Create()
+	if err != nil {
+		return fmt.Errorf("failed to create decision strategy: %w", err)
+	}
+
+	// Create decision manager
+	dm := decision.NewManager(decisionStrategy, predictionModel, agg, decision.ManagerConfig{
+		Thresholds:   decision.ThresholdsFromConfig(cfg.Thresholds),
+		SafetyBuffer: cfg.Decision.SafetyBufferPercent / 100,
+	})
+	dm.SetTaskThresholds(taskThresholds)
+	// Leases hold capacity for /ask as well
+	cm.SetStateSource(dm.ReservedState)
+	// Notified tasks are pending load until observed
+	le.SetOnDone(dm.RemovePendingTask)
+
+	// Restore leases that outlived the previous run
+	leaseStore := storage.NewLeaseStorage(store)
+	if err := leaseStore.LoadLeases(dm.Leases()); err != nil {
+		log.Warn("failed to load persisted leases", "error", err)
+	}
+
+	// Start model retrain scheduler
+	sched := scheduler.NewScheduler(predictionModel, scheduler.Config{Logger: log})
+	if err := sched.Start(ctx); err != nil {
+		return fmt.Errorf("failed to start scheduler: %w", err)
+	}
+
+	log.Info("decision engine configured",
+		"strategy", decisionStrategy.Name(),
+		"model", predictionModel.Name(),
+		"route_v1_ask", cfg.Decision.RouteV1Ask,
+	)
 
 	// Start storage periodic flush
 	store.Start(ctx)
@@ -127,11 +260,19 @@ func runStart(cmd *cobra.Command, args []string) error {
 
 	// Create and start server
 	srv := server.New(cfg, agg, cm, le, log, Version)
+	srv.SetDecisionComponents(&server.V2Components{
+		DecisionManager: dm,
+		Scheduler:       sched,
+		Model:           predictionModel,
+		LeaseStorage:    leaseStore,
+	})
+	srv.SetRecorder(recorder)
 
 	// Signal channels
 	sighupCh := make(chan os.Signal, 1)
 	sigCh := make(chan os.Signal, 1)
 	shutdownDone := make(chan struct{})
+	cleanupDone := make(chan struct{})
 
 	signal.Notify(sighupCh, syscall.SIGHUP)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
@@ -158,6 +299,8 @@ func runStart(cmd *cobra.Command, args []string) error {
 
 	// Handle shutdown signals
 	go func() {
+		defer close(cleanupDone)
+
 		<-sigCh
 
 		log.Info("shutdown signal received")
@@ -179,8 +322,14 @@ func runStart(cmd *cobra.Command, args []string) error {
 			log.Error("storage shutdown error", "error", err)
 		}
 
-		// Stop learning engine
+		// Stop learning engine and scheduler
 		le.Stop()
+		sched.Stop()
+
+		// Save model state
+		if err := modelStore.SaveModel(predictionModel); err != nil {
+			log.Error("model save error", "error", err)
+		}
 
 		_ = agg.Stop()
 		cancel()
@@ -192,6 +341,9 @@ func runStart(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("server error: %w", err)
 	}
 
+	// Wait for storage and model state to be saved
+	<-cleanupDone
+
 	log.Info("capfox stopped")
 	return nil
 }
@@ -213,3 +365,60 @@ func writePIDFile(path string) error {
 
 	return nil
 }
+
+// createMonitors creates the monitors of the local host.
+func createMonitors(cfg *config.Config, log *slog.Logger) ([]monitor.Monitor, error) {
+	// CPU and memory are host-wide unless limits come from a cgroup
+	var cpuMonitor monitor.Monitor = monitor.NewCPUMonitor()
+	var memoryMonitor monitor.Monitor = monitor.NewMemoryMonitor()
+	if cfg.Monitoring.Cgroup.Enabled {
+		if err := monitor.CheckCgroup(cfg.Monitoring.Cgroup.Path); err != nil {
+			return nil, fmt.Errorf("cgroup monitoring: %w", err)
+		}
+		cpuMonitor = monitor.NewCgroupCPUMonitor(cfg.Monitoring.Cgroup.Path)
+		memoryMonitor = monitor.NewCgroupMemoryMonitor(cfg.Monitoring.Cgroup.Path)
+		log.Info("using cgroup limits for cpu and memory", "path", cfg.Monitoring.Cgroup.Path)
+	}
+
+	monitors := []monitor.Monitor{
+		cpuMonitor,
+		memoryMonitor,
+		monitor.NewSwapMonitor(cfg.Monitoring.ProcRoot),
+		monitor.NewStorageMonitorWithProcRoot(cfg.Monitoring.ProcRoot, cfg.Monitoring.Paths),
+		monitor.NewDiskIOMonitor(cfg.Monitoring.ProcRoot, cfg.Monitoring.Paths),
+		monitor.NewProcessMonitorWithProcRoot(cfg.Monitoring.ProcRoot),
+		monitor.NewPressureMonitor(cfg.Monitoring.ProcRoot),
+		monitor.NewNetworkMonitor(monitor.NetworkMonitorConfig{
+			ProcRoot:     cfg.Monitoring.ProcRoot,
+			SysfsRoot:    cfg.Monitoring.Network.SysfsRoot,
+			Interfaces:   cfg.Monitoring.Network.Interfaces,
+			CapacityMbps: cfg.Monitoring.Network.CapacityMbps,
+		}),
+		monitor.NewThermalMonitor(cfg.Monitoring.Thermal.SysfsRoot),
+		monitor.NewNUMAMonitor(cfg.Monitoring.NUMA.SysfsRoot),
+	}
+
+	for _, p := range cfg.Monitoring.Plugins {
+		monitors = append(monitors, monitor.NewPluginMonitor(monitor.PluginConfig{
+			Name:     p.Name,
+			Command:  p.Command,
+			Interval: p.Interval(),
+			Timeout:  p.Timeout(),
+		}))
+	}
+
+	gpuMonitor, err := monitor.NewGPUBackend(monitor.GPUBackendConfig{
+		Backend:       cfg.Monitoring.GPU.Backend,
+		NvidiaSMIPath: cfg.Monitoring.GPU.NvidiaSMIPath,
+		Timeout:       cfg.GPUTimeout(),
+		SysfsRoot:     cfg.Monitoring.GPU.SysfsRoot,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GPU monitor: %w", err)
+	}
+	if gpuMonitor != nil {
+		monitors = append(monitors, gpuMonitor)
+	}
+
+	return monitors, nil
+}
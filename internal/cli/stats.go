@@ -31,6 +31,17 @@ type taskStats struct {
 	AvgMemDelta  float64 `json:"avg_mem_delta"`
 	AvgGPUDelta  float64 `json:"avg_gpu_delta,omitempty"`
 	AvgVRAMDelta float64 `json:"avg_vram_delta,omitempty"`
+
+	AvgSwapDelta      float64 `json:"avg_swap_delta,omitempty"`
+	AvgSwapPagesDelta float64 `json:"avg_swap_pages_delta,omitempty"`
+
+	AvgIOReadDelta  float64 `json:"avg_io_read_delta,omitempty"`
+	AvgIOWriteDelta float64 `json:"avg_io_write_delta,omitempty"`
+	AvgIOUtilDelta  float64 `json:"avg_io_util_delta,omitempty"`
+
+	AvgNetRxDelta   float64 `json:"avg_net_rx_delta,omitempty"`
+	AvgNetTxDelta   float64 `json:"avg_net_tx_delta,omitempty"`
+	AvgNetUtilDelta float64 `json:"avg_net_util_delta,omitempty"`
 }
 
 type allStats struct {
@@ -107,4 +118,24 @@ func printTaskStats(stats *taskStats) {
 	if stats.AvgVRAMDelta != 0 {
 		fmt.Printf("  Avg VRAM delta: %+.2f%%\n", stats.AvgVRAMDelta)
 	}
+	if stats.AvgSwapDelta != 0 {
+		fmt.Printf("  Avg Swap delta: %+.2f%%\n", stats.AvgSwapDelta)
+	}
+	if stats.AvgSwapPagesDelta != 0 {
+		fmt.Printf("  Avg Swap paging delta: %+.0f pages/s\n", stats.AvgSwapPagesDelta)
+	}
+	if stats.AvgIOReadDelta != 0 || stats.AvgIOWriteDelta != 0 {
+		fmt.Printf("  Avg I/O delta: read %+.1f MB/s, write %+.1f MB/s\n",
+			stats.AvgIOReadDelta/(1024*1024), stats.AvgIOWriteDelta/(1024*1024))
+	}
+	if stats.AvgIOUtilDelta != 0 {
+		fmt.Printf("  Avg I/O util delta: %+.2f%%\n", stats.AvgIOUtilDelta)
+	}
+	if stats.AvgNetRxDelta != 0 || stats.AvgNetTxDelta != 0 {
+		fmt.Printf("  Avg Network delta: rx %+.1f MB/s, tx %+.1f MB/s\n",
+			stats.AvgNetRxDelta/(1024*1024), stats.AvgNetTxDelta/(1024*1024))
+	}
+	if stats.AvgNetUtilDelta != 0 {
+		fmt.Printf("  Avg Network util delta: %+.2f%%\n", stats.AvgNetUtilDelta)
+	}
 }
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"sort"
 
 	"github.com/spf13/cobra"
 )
@@ -62,17 +63,121 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		if used, ok := mem["used_bytes"].(float64); ok {
 			fmt.Printf("  Used:  %.1f GB\n", used/1024/1024/1024)
 		}
+		if available, ok := mem["available_bytes"].(float64); ok && mem["available_known"] == true {
+			fmt.Printf("  Available: %.1f GB\n", available/1024/1024/1024)
+		}
+		if cached, ok := mem["cached_bytes"].(float64); ok && cached > 0 {
+			fmt.Printf("  Cached: %.1f GB\n", cached/1024/1024/1024)
+		}
 	}
 
 	if storage, ok := result["storage"].(map[string]any); ok {
 		fmt.Printf("\nStorage:\n")
 		for path, info := range storage {
 			if diskInfo, ok := info.(map[string]any); ok {
+				if errMsg, ok := diskInfo["error"].(string); ok && errMsg != "" {
+					fmt.Printf("  %s: unavailable (%s)\n", path, errMsg)
+					continue
+				}
 				total, totalOK := diskInfo["total_bytes"].(float64)
 				free, freeOK := diskInfo["free_bytes"].(float64)
 				if totalOK && freeOK {
 					fmt.Printf("  %s: %.1f GB free / %.1f GB total\n", path, free/1024/1024/1024, total/1024/1024/1024)
 				}
+				if readOnly, _ := diskInfo["read_only"].(bool); readOnly {
+					fmt.Printf("  %s: read-only\n", path)
+				}
+			}
+		}
+	}
+
+	if io, ok := result["io"].(map[string]any); ok {
+		fmt.Printf("\nDisk I/O:\n")
+		if iowait, ok := io["iowait_percent"].(float64); ok {
+			fmt.Printf("  iowait: %.1f%%\n", iowait)
+		}
+		paths, _ := io["paths"].(map[string]any)
+		devices, _ := io["devices"].(map[string]any)
+		for path, name := range paths {
+			dev, ok := devices[fmt.Sprint(name)].(map[string]any)
+			if !ok {
+				continue
+			}
+			util, _ := dev["util_percent"].(float64)
+			read, _ := dev["read_bytes_per_sec"].(float64)
+			write, _ := dev["write_bytes_per_sec"].(float64)
+			fmt.Printf("  %s (%v): %.1f%% util, read %.1f MB/s, write %.1f MB/s\n",
+				path, name, util, read/1024/1024, write/1024/1024)
+		}
+	}
+
+	if network, ok := result["network"].(map[string]any); ok && len(network) > 0 {
+		fmt.Printf("\nNetwork:\n")
+		for name, info := range network {
+			iface, ok := info.(map[string]any)
+			if !ok {
+				continue
+			}
+			rx, _ := iface["rx_bytes_per_sec"].(float64)
+			tx, _ := iface["tx_bytes_per_sec"].(float64)
+			fmt.Printf("  %s: rx %.1f MB/s, tx %.1f MB/s", name, rx/1024/1024, tx/1024/1024)
+			if capacity, ok := iface["capacity_mbps"].(float64); ok && capacity > 0 {
+				rxPct, _ := iface["rx_percent"].(float64)
+				txPct, _ := iface["tx_percent"].(float64)
+				fmt.Printf(" (%.1f%% / %.1f%% of %.0f Mbit/s)", rxPct, txPct, capacity)
+			}
+			fmt.Println()
+		}
+	}
+
+	if thermal, ok := result["thermal"].(map[string]any); ok {
+		sensors, _ := thermal["sensors"].(map[string]any)
+		cores, _ := thermal["cores"].([]any)
+		if len(sensors) > 0 || len(cores) > 0 {
+			fmt.Printf("\nThermal:\n")
+			var hottest float64
+			var hottestName string
+			for name, v := range sensors {
+				if celsius, ok := v.(float64); ok && (hottestName == "" || celsius > hottest) {
+					hottest, hottestName = celsius, name
+				}
+			}
+			if hottestName != "" {
+				fmt.Printf("  Hottest: %.1f°C (%s)\n", hottest, hottestName)
+			}
+			var cur, maxFreq float64
+			for _, c := range cores {
+				if core, ok := c.(map[string]any); ok {
+					curMHz, _ := core["cur_mhz"].(float64)
+					maxMHz, _ := core["max_mhz"].(float64)
+					cur += curMHz
+					maxFreq += maxMHz
+				}
+			}
+			if maxFreq > 0 {
+				fmt.Printf("  Frequency: %.0f%% of max (%d cores)\n", cur/maxFreq*100, len(cores))
+			}
+			if rate, ok := thermal["throttle_events_per_sec"].(float64); ok && rate > 0 {
+				fmt.Printf("  Throttling: %.1f events/s\n", rate)
+			}
+		}
+	}
+
+	if numa, ok := result["numa"].(map[string]any); ok {
+		// A single node carries no placement information
+		if nodes, _ := numa["nodes"].([]any); len(nodes) > 1 {
+			fmt.Printf("\nNUMA:\n")
+			for _, n := range nodes {
+				node, ok := n.(map[string]any)
+				if !ok {
+					continue
+				}
+				id, _ := node["id"].(float64)
+				cpus, _ := node["cpus"].([]any)
+				free, _ := node["free_bytes"].(float64)
+				total, _ := node["total_bytes"].(float64)
+				fmt.Printf("  Node %.0f: %d CPUs, %.1f / %.1f GB free\n",
+					id, len(cpus), free/1024/1024/1024, total/1024/1024/1024)
 			}
 		}
 	}
@@ -83,6 +188,9 @@ func runStatus(cmd *cobra.Command, args []string) error {
 			if g, ok := gpu.(map[string]any); ok {
 				usage, _ := g["usage_percent"].(float64)
 				fmt.Printf("  GPU %d: %.1f%% usage", i, usage)
+				if temp, ok := g["temperature"].(float64); ok && temp > 0 {
+					fmt.Printf(", %.0f°C", temp)
+				}
 				if vramTotal, ok := g["vram_total_bytes"].(float64); ok && vramTotal > 0 {
 					if vramUsed, ok := g["vram_used_bytes"].(float64); ok {
 						fmt.Printf(", VRAM: %.1f / %.1f GB",
@@ -95,14 +203,63 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if proc, ok := result["process"].(map[string]any); ok {
+	if processes, ok := result["processes"].(float64); ok {
 		fmt.Printf("\nProcesses:\n")
-		if total, ok := proc["total_processes"].(float64); ok {
-			fmt.Printf("  Total: %.0f\n", total)
-		}
-		if threads, ok := proc["total_threads"].(float64); ok {
+		fmt.Printf("  Total: %.0f\n", processes)
+		if threads, ok := result["threads"].(float64); ok {
 			fmt.Printf("  Threads: %.0f\n", threads)
 		}
+		running, _ := result["procs_running"].(float64)
+		blocked, _ := result["procs_blocked"].(float64)
+		fmt.Printf("  Running: %.0f, Blocked: %.0f\n", running, blocked)
+		if ctxt, ok := result["context_switches_per_sec"].(float64); ok {
+			fmt.Printf("  Context switches: %.0f/s\n", ctxt)
+		}
+		if intr, ok := result["interrupts_per_sec"].(float64); ok {
+			fmt.Printf("  Interrupts: %.0f/s\n", intr)
+		}
+	}
+
+	if custom, ok := result["custom"].(map[string]any); ok && len(custom) > 0 {
+		fmt.Printf("\nCustom:\n")
+		names := make([]string, 0, len(custom))
+		for name := range custom {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("  %s: %v\n", name, custom[name])
+		}
+	}
+
+	if leases, ok := result["leases"].([]any); ok && len(leases) > 0 {
+		fmt.Printf("\nLeases:\n")
+		for _, l := range leases {
+			lease, ok := l.(map[string]any)
+			if !ok {
+				continue
+			}
+			fmt.Printf("  %v (%v): expires %v\n", lease["task"], lease["id"], lease["expires_at"])
+		}
+	}
+
+	if health, ok := result["health"].(map[string]any); ok {
+		var stale []string
+		for name, h := range health {
+			if monitorHealth, ok := h.(map[string]any); ok && monitorHealth["stale"] == true {
+				stale = append(stale, name)
+			}
+		}
+		if len(stale) > 0 {
+			sort.Strings(stale)
+			fmt.Printf("\nStale monitors:\n")
+			for _, name := range stale {
+				monitorHealth := health[name].(map[string]any)
+				failures, _ := monitorHealth["consecutive_failures"].(float64)
+				lastError, _ := monitorHealth["last_error"].(string)
+				fmt.Printf("  %s: %.0f failures, %s\n", name, failures, lastError)
+			}
+		}
 	}
 
 	return nil
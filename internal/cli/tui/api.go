@@ -21,6 +21,11 @@ type statsMsg struct {
 	err  error
 }
 
+type historyMsg struct {
+	data *HistoryData
+	err  error
+}
+
 type tickMsg time.Time
 
 // API client for TUI
@@ -101,6 +106,26 @@ func fetchStats(cfg Config) tea.Cmd {
 	}
 }
 
+// fetchHistory fetches CPU and memory trends from API as tea.Cmd
+func fetchHistory(cfg Config) tea.Cmd {
+	return func() tea.Msg {
+		client := newAPIClient(cfg)
+		from := time.Now().Add(-trendWindow).Unix()
+		path := fmt.Sprintf("/v2/history?from=%d&step=%s&metrics=cpu,memory", from, trendWindow/trendPoints)
+		data, err := client.get(path)
+		if err != nil {
+			return historyMsg{err: err}
+		}
+
+		var history HistoryData
+		if err := json.Unmarshal(data, &history); err != nil {
+			return historyMsg{err: fmt.Errorf("failed to parse history: %w", err)}
+		}
+
+		return historyMsg{data: &history}
+	}
+}
+
 // tick creates a periodic tick command
 func tick(interval time.Duration) tea.Cmd {
 	return tea.Tick(interval, func(t time.Time) tea.Msg {
@@ -17,8 +17,9 @@ type Model struct {
 	config Config
 
 	// Data from API
-	status *StatusData
-	stats  *StatsData
+	status  *StatusData
+	stats   *StatsData
+	history *HistoryData
 
 	// UI state
 	width       int
@@ -38,6 +39,8 @@ type StatusData struct {
 	GPUs    []GPUStatus   `json:"gpus"`
 	Storage StorageStatus `json:"storage"`
 	Process ProcessStatus `json:"process"`
+	// Custom holds plugin and pushed metrics
+	Custom map[string]float64 `json:"custom"`
 }
 
 type CPUStatus struct {
@@ -65,6 +68,8 @@ type DiskStatus struct {
 	FreeBytes  uint64  `json:"free_bytes"`
 	UsedBytes  uint64  `json:"used_bytes"`
 	UsedPct    float64 `json:"used_percent"`
+	ReadOnly   bool    `json:"read_only"`
+	Error      string  `json:"error"`
 }
 
 type ProcessStatus struct {
@@ -87,6 +92,18 @@ type TaskStats struct {
 	AvgVRAMDelta float64 `json:"avg_vram_delta,omitempty"`
 }
 
+// Trend sparklines cover trendWindow in trendPoints averaged steps
+const (
+	trendWindow = 10 * time.Minute
+	trendPoints = 20
+)
+
+// HistoryData represents metric series from /v2/history endpoint
+type HistoryData struct {
+	Timestamps []int64              `json:"timestamps"`
+	Series     map[string][]float64 `json:"series"`
+}
+
 // NewModel creates a new TUI model
 func NewModel(cfg Config) Model {
 	return Model{
@@ -11,6 +11,7 @@ func (m Model) Init() tea.Cmd {
 	return tea.Batch(
 		fetchStatus(m.config),
 		fetchStats(m.config),
+		fetchHistory(m.config),
 		tick(m.config.RefreshInterval),
 	)
 }
@@ -48,11 +49,19 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case historyMsg:
+		// History is optional on the server, so errors only hide the trends
+		if msg.err == nil {
+			m.history = msg.data
+		}
+		return m, nil
+
 	case tickMsg:
 		m.loading = true
 		return m, tea.Batch(
 			fetchStatus(m.config),
 			fetchStats(m.config),
+			fetchHistory(m.config),
 			tick(m.config.RefreshInterval),
 		)
 	}
@@ -71,6 +80,7 @@ func (m Model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, tea.Batch(
 			fetchStatus(m.config),
 			fetchStats(m.config),
+			fetchHistory(m.config),
 		)
 
 	case "up", "k":
@@ -3,6 +3,7 @@ package tui
 import (
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -28,6 +29,9 @@ func (m Model) View() string {
 	if m.status != nil {
 		// CPU & Memory bar
 		sections = append(sections, m.renderCPUMemory())
+		if m.history != nil && len(m.history.Timestamps) > 1 {
+			sections = append(sections, m.renderTrends())
+		}
 
 		// GPU section
 		if len(m.status.GPUs) > 0 {
@@ -38,6 +42,11 @@ func (m Model) View() string {
 		if len(m.status.Storage) > 0 {
 			sections = append(sections, m.renderStorage())
 		}
+
+		// Plugin and pushed metrics
+		if len(m.status.Custom) > 0 {
+			sections = append(sections, m.renderCustom())
+		}
 	}
 
 	// Task statistics
@@ -78,6 +87,36 @@ func (m Model) renderCPUMemory() string {
 	return fmt.Sprintf("  %s    %s", cpuBar, memBar)
 }
 
+// renderTrends renders CPU and memory sparklines aligned with their bars.
+func (m Model) renderTrends() string {
+	cpu := renderSparkline(m.history.Series["cpu"], 20)
+	mem := renderSparkline(m.history.Series["memory"], 20)
+
+	// Pad to the layout of renderCPUMemory: label, " [", bar, "] 100.0%"
+	cpuPad := strings.Repeat(" ", lipgloss.Width(labelStyle.Render("CPU"))+2)
+	memPad := strings.Repeat(" ", lipgloss.Width(labelStyle.Render("Memory"))+2)
+	window := helpStyle.Render(fmt.Sprintf("%-7s", fmt.Sprintf("%.0fm", trendWindow.Minutes())))
+	return fmt.Sprintf("  %s%s %s    %s%s %s", cpuPad, cpu, window, memPad, mem, window)
+}
+
+// renderSparkline renders the last width values of a 0-100 series.
+func renderSparkline(values []float64, width int) string {
+	levels := []rune("▁▂▃▄▅▆▇█")
+
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.Repeat(" ", width-len(values)))
+	for _, v := range values {
+		level := int(v / 100 * float64(len(levels)-1))
+		level = max(0, min(level, len(levels)-1))
+		b.WriteString(lipgloss.NewStyle().Foreground(getProgressColor(v)).Render(string(levels[level])))
+	}
+	return b.String()
+}
+
 func (m Model) renderProgressBar(label string, percent float64, width int) string {
 	filled := int(percent / 100 * float64(width))
 	if filled > width {
@@ -144,10 +183,44 @@ func (m Model) renderStorage() string {
 		}
 		pathDisplay = fmt.Sprintf("%-6s", pathDisplay)
 
+		if disk.Error != "" {
+			lines = append(lines, fmt.Sprintf("  %s  %s", labelStyle.Render(pathDisplay), errorStyle.Render("unavailable")))
+			continue
+		}
+
 		bar := m.renderProgressBar(pathDisplay, disk.UsedPct, 20)
 		info := fmt.Sprintf("(%.1f / %.1f GB)", usedGB, totalGB)
 
-		lines = append(lines, fmt.Sprintf("  %s  %s", bar, valueStyle.Render(info)))
+		line := fmt.Sprintf("  %s  %s", bar, valueStyle.Render(info))
+		if disk.ReadOnly {
+			line += " " + errorStyle.Render("read-only")
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// renderCustom renders plugin and pushed metrics, several per line.
+func (m Model) renderCustom() string {
+	var lines []string
+	lines = append(lines, sectionHeaderStyle.Render("  Custom Metrics"))
+
+	names := make([]string, 0, len(m.status.Custom))
+	for name := range m.status.Custom {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	const perLine = 3
+	for i := 0; i < len(names); i += perLine {
+		var cells []string
+		for _, name := range names[i:min(i+perLine, len(names))] {
+			cells = append(cells, fmt.Sprintf("%s %s",
+				labelStyle.Render(name),
+				valueStyle.Render(fmt.Sprintf("%-10s", strconv.FormatFloat(m.status.Custom[name], 'g', 6, 64)))))
+		}
+		lines = append(lines, "  "+strings.Join(cells, "  "))
 	}
 
 	return strings.Join(lines, "\n")
@@ -1,6 +1,10 @@
 package config
 
-import "time"
+import (
+	"time"
+
+	"github.com/haskel/capfox/internal/monitor"
+)
 
 type Config struct {
 	Server      ServerConfig      `yaml:"server"`
@@ -12,6 +16,18 @@ type Config struct {
 	Learning    LearningConfig    `yaml:"learning"`
 	Decision    DecisionConfig    `yaml:"decision"`
 	Debug       DebugConfig       `yaml:"debug"`
+	Trace       TraceConfig       `yaml:"trace"`
+	// Tasks overrides settings for task name patterns, e.g. "video_*"
+	Tasks map[string]TaskConfig `yaml:"tasks"`
+	// Priorities overrides thresholds for priority classes: low, normal, high
+	Priorities map[Priority]PriorityConfig `yaml:"priorities"`
+}
+
+// TraceConfig holds trace recording configuration.
+type TraceConfig struct {
+	// Path is the NDJSON file that snapshots, asks, notifies and decisions
+	// are appended to; empty disables recording
+	Path string `yaml:"path"`
 }
 
 // DebugConfig holds debug mode configuration.
@@ -62,11 +78,21 @@ type AuthConfig struct {
 }
 
 type ThresholdsConfig struct {
-	CPU     CPUThreshold     `yaml:"cpu"`
-	Memory  MemoryThreshold  `yaml:"memory"`
-	GPU     GPUThreshold     `yaml:"gpu"`
-	VRAM    VRAMThreshold    `yaml:"vram"`
-	Storage StorageThreshold `yaml:"storage"`
+	CPU      CPUThreshold      `yaml:"cpu"`
+	Memory   MemoryThreshold   `yaml:"memory"`
+	GPU      GPUThreshold      `yaml:"gpu"`
+	VRAM     VRAMThreshold     `yaml:"vram"`
+	Storage  StorageThreshold  `yaml:"storage"`
+	Swap     SwapThreshold     `yaml:"swap"`
+	Process  ProcessThreshold  `yaml:"process"`
+	Pressure PressureThreshold `yaml:"pressure"`
+	IO       IOThreshold       `yaml:"io"`
+	Network  NetworkThreshold  `yaml:"network"`
+	Thermal  ThermalThreshold  `yaml:"thermal"`
+	NUMA     NUMAThreshold     `yaml:"numa"`
+	Health   HealthThreshold   `yaml:"health"`
+	// Custom limits plugin metrics by metric name
+	Custom map[string]CustomThreshold `yaml:"custom"`
 }
 
 type CPUThreshold struct {
@@ -75,10 +101,54 @@ type CPUThreshold struct {
 
 type MemoryThreshold struct {
 	MaxPercent float64 `yaml:"max_percent"`
+	// Mode selects how usage is computed: used (kernel used figure) or
+	// available (everything not in MemAvailable)
+	Mode string `yaml:"mode"`
 }
 
+// GPUThreshold limits each GPU on its own: a task is denied only if no GPU
+// is within its limits.
 type GPUThreshold struct {
 	MaxPercent float64 `yaml:"max_percent"`
+	// MaxCelsius is the maximum temperature of a GPU (0 = disabled)
+	MaxCelsius float64 `yaml:"max_celsius"`
+	// Devices overrides the limits of individual GPUs by index
+	Devices map[int]GPUDeviceThreshold `yaml:"devices"`
+}
+
+// GPUDeviceThreshold holds the limits of one GPU. Zero keeps the value of
+// gpu.max_percent, vram.max_percent or gpu.max_celsius.
+type GPUDeviceThreshold struct {
+	MaxPercent     float64 `yaml:"max_percent"`
+	MaxVRAMPercent float64 `yaml:"max_vram_percent"`
+	MaxCelsius     float64 `yaml:"max_celsius"`
+}
+
+// GPULimits returns the limits of the GPU at index.
+func (t ThresholdsConfig) GPULimits(index int) GPUDeviceThreshold {
+	limits := GPUDeviceThreshold{
+		MaxPercent:     t.GPU.MaxPercent,
+		MaxVRAMPercent: t.VRAM.MaxPercent,
+		MaxCelsius:     t.GPU.MaxCelsius,
+	}
+	if override, ok := t.GPU.Devices[index]; ok {
+		if override.MaxPercent > 0 {
+			limits.MaxPercent = override.MaxPercent
+		}
+		if override.MaxVRAMPercent > 0 {
+			limits.MaxVRAMPercent = override.MaxVRAMPercent
+		}
+		if override.MaxCelsius > 0 {
+			limits.MaxCelsius = override.MaxCelsius
+		}
+	}
+	return limits
+}
+
+// Limits returns the limits the thresholds contributed by monitors are
+// checked against.
+func (t ThresholdsConfig) Limits() monitor.Limits {
+	return monitor.Limits{Process: monitor.ProcessLimits(t.Process)}
 }
 
 type VRAMThreshold struct {
@@ -87,11 +157,220 @@ type VRAMThreshold struct {
 
 type StorageThreshold struct {
 	MinFreeGB float64 `yaml:"min_free_gb"`
+	// MinFreeInodesPercent is the minimum share of free inodes (0 = disabled)
+	MinFreeInodesPercent float64 `yaml:"min_free_inodes_percent"`
+}
+
+// SwapThreshold limits swap usage and paging activity. Zero disables a check.
+type SwapThreshold struct {
+	// MaxPercent is the maximum swap usage
+	MaxPercent float64 `yaml:"max_percent"`
+	// MaxPagesPerSec is the maximum swap-in plus swap-out rate (pswpin + pswpout)
+	MaxPagesPerSec float64 `yaml:"max_pages_per_sec"`
+}
+
+// ProcessThreshold limits the scheduler run queue. Zero disables a check.
+type ProcessThreshold struct {
+	// MaxRunning is the maximum number of runnable processes (procs_running)
+	MaxRunning int `yaml:"max_running"`
+	// MaxBlocked is the maximum number of processes blocked on I/O (procs_blocked)
+	MaxBlocked int `yaml:"max_blocked"`
+}
+
+// PressureThreshold limits Pressure Stall Information (PSI) per resource.
+type PressureThreshold struct {
+	// Window is the PSI averaging window checked: avg10 or avg60
+	Window string        `yaml:"window"`
+	CPU    PressureLimit `yaml:"cpu"`
+	Memory PressureLimit `yaml:"memory"`
+	IO     PressureLimit `yaml:"io"`
+}
+
+// PressureLimit holds PSI limits in percent of stalled time. Zero disables a check.
+type PressureLimit struct {
+	MaxSome float64 `yaml:"max_some"`
+	MaxFull float64 `yaml:"max_full"`
+}
+
+// IOThreshold limits utilization of the block devices backing
+// monitoring.paths. Zero disables the check.
+type IOThreshold struct {
+	// MaxUtilPercent is the maximum share of time a device may be busy
+	MaxUtilPercent float64 `yaml:"max_util_percent"`
+	// Paths overrides MaxUtilPercent for individual monitored paths
+	Paths map[string]float64 `yaml:"paths"`
+}
+
+// Limit returns the utilization limit for a monitored path.
+func (t IOThreshold) Limit(path string) float64 {
+	if limit, ok := t.Paths[path]; ok {
+		return limit
+	}
+	return t.MaxUtilPercent
+}
+
+// NetworkThreshold limits interface utilization relative to capacity.
+// Interfaces with unknown capacity are not checked. Zero disables the check.
+type NetworkThreshold struct {
+	// MaxPercent is the maximum rx or tx usage of any interface
+	MaxPercent float64 `yaml:"max_percent"`
+}
+
+// ThermalThreshold limits CPU and board temperatures. When enabled, active
+// CPU throttling also denies. Zero disables the check.
+type ThermalThreshold struct {
+	// MaxCelsius is the maximum temperature of any non-GPU sensor
+	MaxCelsius float64 `yaml:"max_celsius"`
+}
+
+// NUMAThreshold limits usage of each NUMA node. A node over either limit is
+// not offered for placement, and a task is denied only when no node fits.
+// Zero disables a check.
+type NUMAThreshold struct {
+	// MaxMemoryPercent is the maximum memory usage of a node, in memory.mode
+	MaxMemoryPercent float64 `yaml:"max_memory_percent"`
+	// MaxCPUPercent is the maximum average usage of the node's CPUs
+	MaxCPUPercent float64 `yaml:"max_cpu_percent"`
+}
+
+// HealthThreshold fails closed on stale metrics. Zero disables the check.
+type HealthThreshold struct {
+	// MaxFailures is the number of consecutive failed collections of any
+	// monitor after which tasks are denied and /ready reports not ready
+	MaxFailures int `yaml:"max_failures"`
+}
+
+// CustomThreshold limits a plugin metric. Unlike the built-in thresholds,
+// zero is a valid limit; an unset bound is not checked.
+type CustomThreshold struct {
+	Max *float64 `yaml:"max,omitempty"`
+	Min *float64 `yaml:"min,omitempty"`
 }
 
 type MonitoringConfig struct {
-	IntervalMS int      `yaml:"interval_ms"`
-	Paths      []string `yaml:"paths"`
+	IntervalMS int                 `yaml:"interval_ms"`
+	Paths      []string            `yaml:"paths"`
+	GPU        GPUMonitoringConfig `yaml:"gpu"`
+	// ProcRoot is where procfs is mounted (e.g. /host/proc in a container)
+	ProcRoot string `yaml:"proc_root"`
+	// Cgroup reports CPU and memory relative to cgroup v2 limits
+	Cgroup CgroupMonitoringConfig `yaml:"cgroup"`
+	// Network selects interfaces and their capacity
+	Network NetworkMonitoringConfig `yaml:"network"`
+	// Thermal configures temperature and CPU frequency collection
+	Thermal ThermalMonitoringConfig `yaml:"thermal"`
+	// NUMA configures per-node memory and topology collection
+	NUMA NUMAMonitoringConfig `yaml:"numa"`
+	// History configures the in-memory metric history
+	History HistoryConfig `yaml:"history"`
+	// Smoothing selects how samples are aggregated for decisions
+	Smoothing SmoothingConfig `yaml:"smoothing"`
+	// CollectTimeoutMS bounds a single collection of each monitor
+	CollectTimeoutMS int `yaml:"collect_timeout_ms"`
+	// CollectTimeoutsMS overrides CollectTimeoutMS by monitor name
+	CollectTimeoutsMS map[string]int `yaml:"collect_timeouts_ms"`
+	// Plugins are external commands reporting custom metrics
+	Plugins []PluginMonitoringConfig `yaml:"plugins"`
+	// Push configures custom metrics reported through POST /v2/metrics
+	Push PushMonitoringConfig `yaml:"push"`
+}
+
+// PushMonitoringConfig holds push API configuration.
+type PushMonitoringConfig struct {
+	// DefaultTTLSec is how long a pushed value stays valid when the push
+	// sets no ttl_sec
+	DefaultTTLSec int `yaml:"default_ttl_sec"`
+}
+
+// DefaultTTL returns the validity of a pushed value without ttl_sec.
+func (p PushMonitoringConfig) DefaultTTL() time.Duration {
+	return time.Duration(p.DefaultTTLSec) * time.Second
+}
+
+// PluginMonitoringConfig holds the settings of one exec-based plugin. The
+// command must print a JSON object of numeric metrics to stdout.
+type PluginMonitoringConfig struct {
+	Name string `yaml:"name"`
+	// Command is the executable and its arguments, run without a shell
+	Command []string `yaml:"command"`
+	// IntervalMS is the minimum time between runs, 0 runs on every collection
+	IntervalMS int `yaml:"interval_ms"`
+	// TimeoutMS bounds a single run, 0 uses the default of 5s
+	TimeoutMS int `yaml:"timeout_ms"`
+}
+
+// SmoothingConfig holds the aggregation of decision inputs per resource:
+// instant, ewma(alpha), max(window) or pXX(window), e.g. "p95(1m)".
+// /status always reports the latest sample.
+type SmoothingConfig struct {
+	CPU     string `yaml:"cpu"`
+	Memory  string `yaml:"memory"`
+	Swap    string `yaml:"swap"`
+	GPU     string `yaml:"gpu"`
+	VRAM    string `yaml:"vram"`
+	IO      string `yaml:"io"`
+	Network string `yaml:"network"`
+}
+
+// Specs returns the smoothing specs keyed by resource name.
+func (s SmoothingConfig) Specs() map[string]string {
+	return map[string]string{
+		"cpu":     s.CPU,
+		"memory":  s.Memory,
+		"swap":    s.Swap,
+		"gpu":     s.GPU,
+		"vram":    s.VRAM,
+		"io":      s.IO,
+		"network": s.Network,
+	}
+}
+
+// HistoryConfig holds metric history configuration.
+type HistoryConfig struct {
+	// RetentionSec is how far back snapshots are kept, 0 disables history
+	RetentionSec int `yaml:"retention_sec"`
+}
+
+// ThermalMonitoringConfig holds thermal collection configuration.
+type ThermalMonitoringConfig struct {
+	// SysfsRoot is where sysfs is mounted, used for thermal zones, hwmon and cpufreq
+	SysfsRoot string `yaml:"sysfs_root"`
+}
+
+// NUMAMonitoringConfig holds NUMA collection configuration.
+type NUMAMonitoringConfig struct {
+	// SysfsRoot is where sysfs is mounted, used for /devices/system/node
+	SysfsRoot string `yaml:"sysfs_root"`
+}
+
+// NetworkMonitoringConfig holds network collection configuration.
+type NetworkMonitoringConfig struct {
+	// Interfaces to collect; empty means all physical interfaces
+	Interfaces []string `yaml:"interfaces"`
+	// CapacityMbps sets interface capacity in Mbit/s, overriding the link speed
+	CapacityMbps map[string]float64 `yaml:"capacity_mbps"`
+	// SysfsRoot is where sysfs is mounted, used to detect physical interfaces and link speed
+	SysfsRoot string `yaml:"sysfs_root"`
+}
+
+// CgroupMonitoringConfig holds cgroup v2 collection configuration.
+type CgroupMonitoringConfig struct {
+	// Enabled replaces host-wide CPU/memory metrics with cgroup metrics
+	Enabled bool `yaml:"enabled"`
+	// Path is the cgroup v2 directory holding memory.max, cpu.max, etc.
+	Path string `yaml:"path"`
+}
+
+// GPUMonitoringConfig holds GPU collection configuration.
+type GPUMonitoringConfig struct {
+	// Backend selects the GPU collector: auto, nvidia, amd, none
+	Backend string `yaml:"backend"`
+	// NvidiaSMIPath is the nvidia-smi binary (name in PATH or absolute path)
+	NvidiaSMIPath string `yaml:"nvidia_smi_path"`
+	// TimeoutMS bounds a single GPU query
+	TimeoutMS int `yaml:"timeout_ms"`
+	// SysfsRoot is where sysfs is mounted, used by the amd backend
+	SysfsRoot string `yaml:"sysfs_root"`
 }
 
 type PersistenceConfig struct {
@@ -114,7 +393,8 @@ type DecisionConfig struct {
 	// Strategy type: threshold, predictive, conservative, queue_aware
 	Strategy string `yaml:"strategy"`
 
-	// Model type: none, moving_average, linear
+	// Model type: none, moving_average, linear. Empty falls back to
+	// learning.model, then linear
 	Model string `yaml:"model"`
 
 	// Fallback strategy when insufficient data
@@ -128,6 +408,21 @@ type DecisionConfig struct {
 
 	// Model-specific parameters
 	ModelParams ModelParamsConfig `yaml:"model_params"`
+	// RouteV1Ask makes POST /ask use the decision engine instead of static thresholds
+	RouteV1Ask bool `yaml:"route_v1_ask"`
+
+	// LeaseTTLSec is how long a POST /v2/reserve lease lasts when the
+	// request sets no ttl_sec
+	LeaseTTLSec int `yaml:"lease_ttl_sec"`
+}
+
+// MaxLeaseTTLSec is the longest lease, a week. Longer leases would rather
+// be a capacity limit.
+const MaxLeaseTTLSec = 7 * 24 * 60 * 60
+
+// LeaseTTL returns the duration of a lease without ttl_sec.
+func (d DecisionConfig) LeaseTTL() time.Duration {
+	return time.Duration(d.LeaseTTLSec) * time.Second
 }
 
 // ModelParamsConfig holds model-specific parameters.
@@ -136,10 +431,65 @@ type ModelParamsConfig struct {
 	Alpha float64 `yaml:"alpha"`
 }
 
+// PredictionModel returns the prediction model type shared by the learning
+// engine and the decision engine: decision.model if set, otherwise the
+// legacy learning.model key, otherwise linear.
+func (c *Config) PredictionModel() string {
+	if c.Decision.Model != "" {
+		return c.Decision.Model
+	}
+	if c.Learning.Model != "" {
+		return learningModelType(c.Learning.Model)
+	}
+	return "linear"
+}
+
+// learningModelType maps a learning.model name to the decision model type.
+func learningModelType(name string) string {
+	if name == "linear_regression" {
+		return "linear"
+	}
+	return name
+}
+
 func (c *Config) MonitoringInterval() time.Duration {
 	return time.Duration(c.Monitoring.IntervalMS) * time.Millisecond
 }
 
+// HistorySamples returns the number of snapshots kept by the metric
+// history, or 0 if history is disabled.
+func (c *Config) HistorySamples() int {
+	if c.Monitoring.History.RetentionSec <= 0 || c.Monitoring.IntervalMS <= 0 {
+		return 0
+	}
+	return c.Monitoring.History.RetentionSec * 1000 / c.Monitoring.IntervalMS
+}
+
+// CollectTimeouts returns the default collection timeout and the
+// per-monitor overrides.
+func (c *Config) CollectTimeouts() (time.Duration, map[string]time.Duration) {
+	overrides := make(map[string]time.Duration, len(c.Monitoring.CollectTimeoutsMS))
+	for name, ms := range c.Monitoring.CollectTimeoutsMS {
+		overrides[name] = time.Duration(ms) * time.Millisecond
+	}
+	return time.Duration(c.Monitoring.CollectTimeoutMS) * time.Millisecond, overrides
+}
+
+// Interval returns the minimum time between runs of the plugin.
+func (p PluginMonitoringConfig) Interval() time.Duration {
+	return time.Duration(p.IntervalMS) * time.Millisecond
+}
+
+// Timeout returns the bound of a single plugin run.
+func (p PluginMonitoringConfig) Timeout() time.Duration {
+	return time.Duration(p.TimeoutMS) * time.Millisecond
+}
+
+// GPUTimeout returns the timeout for a single GPU query.
+func (c *Config) GPUTimeout() time.Duration {
+	return time.Duration(c.Monitoring.GPU.TimeoutMS) * time.Millisecond
+}
+
 func (c *Config) FlushInterval() time.Duration {
 	return time.Duration(c.Persistence.FlushIntervalSec) * time.Second
 }
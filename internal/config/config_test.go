@@ -93,3 +93,29 @@ func TestLoadOrDefault(t *testing.T) {
 		t.Errorf("expected default port 9329, got %d", cfg.Server.Port)
 	}
 }
+
+func TestPredictionModel(t *testing.T) {
+	tests := []struct {
+		name          string
+		decisionModel string
+		learningModel string
+		expected      string
+	}{
+		{"decision model wins", "linear", "moving_average", "linear"},
+		{"fallback to learning model", "", "moving_average", "moving_average"},
+		{"legacy linear_regression name", "", "linear_regression", "linear"},
+		{"linear when neither is set", "", "", "linear"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.Decision.Model = tt.decisionModel
+			cfg.Learning.Model = tt.learningModel
+
+			if got := cfg.PredictionModel(); got != tt.expected {
+				t.Errorf("expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
@@ -24,6 +24,7 @@ func Default() *Config {
 			},
 			Memory: MemoryThreshold{
 				MaxPercent: 85.0,
+				Mode:       "used",
 			},
 			GPU: GPUThreshold{
 				MaxPercent: 90.0,
@@ -34,10 +35,49 @@ func Default() *Config {
 			Storage: StorageThreshold{
 				MinFreeGB: 10.0,
 			},
+			Pressure: PressureThreshold{
+				Window: "avg10",
+			},
 		},
 		Monitoring: MonitoringConfig{
 			IntervalMS: 1000,
 			Paths:      []string{"/"},
+			GPU: GPUMonitoringConfig{
+				Backend:       "auto",
+				NvidiaSMIPath: "nvidia-smi",
+				TimeoutMS:     2000,
+				SysfsRoot:     "/sys",
+			},
+			ProcRoot: "/proc",
+			Cgroup: CgroupMonitoringConfig{
+				Enabled: false,
+				Path:    "/sys/fs/cgroup",
+			},
+			Network: NetworkMonitoringConfig{
+				SysfsRoot: "/sys",
+			},
+			Thermal: ThermalMonitoringConfig{
+				SysfsRoot: "/sys",
+			},
+			NUMA: NUMAMonitoringConfig{
+				SysfsRoot: "/sys",
+			},
+			History: HistoryConfig{
+				RetentionSec: 3600,
+			},
+			Smoothing: SmoothingConfig{
+				CPU:     "instant",
+				Memory:  "instant",
+				Swap:    "instant",
+				GPU:     "instant",
+				VRAM:    "instant",
+				IO:      "instant",
+				Network: "instant",
+			},
+			CollectTimeoutMS: 5000,
+			Push: PushMonitoringConfig{
+				DefaultTTLSec: 60,
+			},
 		},
 		Persistence: PersistenceConfig{
 			DataDir:          "/var/lib/capfox",
@@ -48,18 +88,17 @@ func Default() *Config {
 			Format: "json",
 		},
 		Learning: LearningConfig{
-			Model:               "moving_average",
 			ObservationDelaySec: 5,
 		},
 		Decision: DecisionConfig{
 			Strategy:            "predictive",
-			Model:               "linear",
 			FallbackStrategy:    "threshold",
 			MinObservations:     5,
 			SafetyBufferPercent: 10.0,
 			ModelParams: ModelParamsConfig{
 				Alpha: 0.2,
 			},
+			LeaseTTLSec: 300,
 		},
 	}
 }
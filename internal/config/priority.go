@@ -0,0 +1,72 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Priority is the class of a task, deciding which thresholds it is
+// checked against.
+type Priority string
+
+// Priority classes.
+const (
+	PriorityLow    Priority = "low"
+	PriorityNormal Priority = "normal"
+	PriorityHigh   Priority = "high"
+)
+
+// Priorities are the valid classes, lowest first.
+var Priorities = []Priority{PriorityLow, PriorityNormal, PriorityHigh}
+
+// PriorityConfig holds settings for the tasks of a priority class.
+type PriorityConfig struct {
+	// Thresholds replaces the thresholds of the task it sets, e.g. a lower
+	// cpu.max_percent for low keeps headroom for other classes
+	Thresholds ThresholdsOverride `yaml:"thresholds"`
+}
+
+// ParsePriority parses a class name or its number: -1 is low, 0 is normal
+// and 1 is high. Empty means normal.
+func ParsePriority(s string) (Priority, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	switch Priority(s) {
+	case "":
+		return PriorityNormal, nil
+	case PriorityLow, PriorityNormal, PriorityHigh:
+		return Priority(s), nil
+	}
+
+	n, err := strconv.Atoi(s)
+	switch {
+	case err != nil:
+	case n == -1:
+		return PriorityLow, nil
+	case n == 0:
+		return PriorityNormal, nil
+	case n == 1:
+		return PriorityHigh, nil
+	}
+	return "", fmt.Errorf("invalid priority %q (valid: low, normal, high, -1, 0, 1)", s)
+}
+
+// UnmarshalJSON accepts a class name or its number.
+func (p *Priority) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("invalid priority %s", data)
+		}
+		s = n.String()
+	}
+
+	parsed, err := ParsePriority(s)
+	if err != nil {
+		return err
+	}
+	*p = parsed
+	return nil
+}
@@ -0,0 +1,69 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParsePriority(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Priority
+		wantErr  bool
+	}{
+		{"", PriorityNormal, false},
+		{"low", PriorityLow, false},
+		{" High ", PriorityHigh, false},
+		{"normal", PriorityNormal, false},
+		{"-1", PriorityLow, false},
+		{"0", PriorityNormal, false},
+		{"1", PriorityHigh, false},
+		{"10", "", true},
+		{"-2", "", true},
+		{"urgent", "", true},
+		{"1.5", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParsePriority(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestPriority_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected Priority
+		wantErr  bool
+	}{
+		{`{"priority": "low"}`, PriorityLow, false},
+		{`{"priority": 1}`, PriorityHigh, false},
+		{`{"priority": -1}`, PriorityLow, false},
+		{`{"priority": 5}`, "", true},
+		{`{}`, "", false},
+		{`{"priority": "urgent"}`, "", true},
+		{`{"priority": true}`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var req struct {
+				Priority Priority `json:"priority"`
+			}
+			err := json.Unmarshal([]byte(tt.input), &req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if req.Priority != tt.expected {
+				t.Errorf("expected %q, got %q", tt.expected, req.Priority)
+			}
+		})
+	}
+}
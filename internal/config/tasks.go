@@ -0,0 +1,225 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// TaskConfig holds settings for the tasks whose name matches a pattern.
+type TaskConfig struct {
+	// Thresholds replaces the global thresholds it sets
+	Thresholds ThresholdsOverride `yaml:"thresholds"`
+}
+
+// ThresholdsOverride is a partial thresholds section. Options it sets
+// replace the global ones, others are kept; map entries such as io.paths
+// or custom limits are replaced per key.
+type ThresholdsOverride struct {
+	node *yaml.Node
+}
+
+// UnmarshalYAML keeps the section for Apply, rejecting options that do not
+// decode into thresholds.
+func (o *ThresholdsOverride) UnmarshalYAML(node *yaml.Node) error {
+	var probe ThresholdsConfig
+	if err := node.Decode(&probe); err != nil {
+		return err
+	}
+	o.node = node
+	return nil
+}
+
+// MarshalYAML writes the section as it was loaded.
+func (o ThresholdsOverride) MarshalYAML() (any, error) {
+	if o.node == nil {
+		return map[string]any{}, nil
+	}
+	return o.node, nil
+}
+
+// MarshalJSON writes the section with its yaml option names.
+func (o ThresholdsOverride) MarshalJSON() ([]byte, error) {
+	if o.node == nil {
+		return []byte("{}"), nil
+	}
+	v, err := nodeValue(o.node)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// nodeValue converts a yaml node to values encoding/json accepts, keeping
+// mapping keys such as GPU indices as strings.
+func nodeValue(node *yaml.Node) (any, error) {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return nodeValue(node.Alias)
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return nodeValue(node.Content[0])
+	case yaml.MappingNode:
+		m := make(map[string]any, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			v, err := nodeValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			m[node.Content[i].Value] = v
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		s := make([]any, 0, len(node.Content))
+		for _, child := range node.Content {
+			v, err := nodeValue(child)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, v)
+		}
+		return s, nil
+	default:
+		var v any
+		err := node.Decode(&v)
+		return v, err
+	}
+}
+
+// Apply returns base with the options of the override replaced.
+func (o ThresholdsOverride) Apply(base ThresholdsConfig) (ThresholdsConfig, error) {
+	// Maps are shared by copies of base, decoding must not touch them
+	t := base
+	t.GPU.Devices = maps.Clone(base.GPU.Devices)
+	t.IO.Paths = maps.Clone(base.IO.Paths)
+	t.Custom = maps.Clone(base.Custom)
+
+	if o.node == nil {
+		return t, nil
+	}
+	if err := o.node.Decode(&t); err != nil {
+		return base, err
+	}
+	return t, nil
+}
+
+// TaskThresholds resolves the thresholds of a task from the global
+// thresholds, the tasks section and the priorities section.
+type TaskThresholds struct {
+	defaults taskThresholds
+	// patterns are in precedence order
+	patterns []taskThresholds
+}
+
+type taskThresholds struct {
+	pattern    string
+	thresholds ThresholdsConfig
+	// priorities holds the thresholds of each configured priority class
+	priorities map[Priority]ThresholdsConfig
+}
+
+// NewTaskThresholds applies the overrides of each task pattern to the
+// global thresholds, then the overrides of each priority class on top.
+// Patterns use path.Match syntax, e.g. "video_*".
+func NewTaskThresholds(defaults ThresholdsConfig, tasks map[string]TaskConfig, priorities map[Priority]PriorityConfig) (*TaskThresholds, error) {
+	for priority := range priorities {
+		if !slices.Contains(Priorities, priority) {
+			return nil, fmt.Errorf("invalid priority %q", priority)
+		}
+	}
+
+	withPriorities := func(pattern string, thresholds ThresholdsConfig) (taskThresholds, error) {
+		entry := taskThresholds{pattern: pattern, thresholds: thresholds}
+		for priority, class := range priorities {
+			resolved, err := class.Thresholds.Apply(thresholds)
+			if err != nil {
+				return entry, fmt.Errorf("priorities.%s.thresholds: %w", priority, err)
+			}
+			if entry.priorities == nil {
+				entry.priorities = make(map[Priority]ThresholdsConfig, len(priorities))
+			}
+			entry.priorities[priority] = resolved
+		}
+		return entry, nil
+	}
+
+	t := &TaskThresholds{}
+	var err error
+	if t.defaults, err = withPriorities("", defaults); err != nil {
+		return nil, err
+	}
+
+	for pattern, task := range tasks {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			return nil, fmt.Errorf("invalid task pattern %q", pattern)
+		}
+		thresholds, err := task.Thresholds.Apply(defaults)
+		if err != nil {
+			return nil, fmt.Errorf("tasks.%s.thresholds: %w", pattern, err)
+		}
+		entry, err := withPriorities(pattern, thresholds)
+		if err != nil {
+			return nil, err
+		}
+		t.patterns = append(t.patterns, entry)
+	}
+
+	// Exact names first, then longer and so more specific patterns
+	sort.Slice(t.patterns, func(i, j int) bool {
+		a, b := t.patterns[i].pattern, t.patterns[j].pattern
+		if isGlob(a) != isGlob(b) {
+			return !isGlob(a)
+		}
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return a < b
+	})
+
+	return t, nil
+}
+
+// Resolve returns the thresholds of a task and the pattern they come from.
+// Without a matching pattern it returns the global thresholds and "". An
+// exact name wins over globs, then the longest pattern.
+func (t *TaskThresholds) Resolve(task string) (ThresholdsConfig, string) {
+	entry := t.find(task)
+	return entry.thresholds, entry.pattern
+}
+
+// ResolvePriority returns the thresholds of a task run at priority and
+// whether the priority class overrides them. Empty priority is normal.
+func (t *TaskThresholds) ResolvePriority(task string, priority Priority) (ThresholdsConfig, bool) {
+	if priority == "" {
+		priority = PriorityNormal
+	}
+
+	entry := t.find(task)
+	if thresholds, ok := entry.priorities[priority]; ok {
+		return thresholds, true
+	}
+	return entry.thresholds, false
+}
+
+// find returns the first entry matching task, or the defaults.
+func (t *TaskThresholds) find(task string) taskThresholds {
+	for _, p := range t.patterns {
+		if ok, _ := path.Match(p.pattern, task); ok {
+			return p
+		}
+	}
+	return t.defaults
+}
+
+// isGlob reports whether a pattern contains match syntax.
+func isGlob(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
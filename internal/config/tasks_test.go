@@ -0,0 +1,268 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func parseConfig(t *testing.T, content string) *Config {
+	t.Helper()
+	cfg := Default()
+	if err := yaml.Unmarshal([]byte(content), cfg); err != nil {
+		t.Fatalf("failed to parse config: %v", err)
+	}
+	return cfg
+}
+
+func TestTaskThresholds_Resolve(t *testing.T) {
+	cfg := parseConfig(t, `
+thresholds:
+  cpu:
+    max_percent: 80
+  io:
+    paths:
+      "/": 90
+tasks:
+  "video_*":
+    thresholds:
+      cpu:
+        max_percent: 95
+      io:
+        paths:
+          "/data": 70
+  "video_preview":
+    thresholds:
+      cpu:
+        max_percent: 60
+  "*":
+    thresholds:
+      memory:
+        mode: available
+`)
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	tasks, err := NewTaskThresholds(cfg.Thresholds, cfg.Tasks, cfg.Priorities)
+	if err != nil {
+		t.Fatalf("failed to resolve tasks: %v", err)
+	}
+
+	tests := []struct {
+		task        string
+		wantPattern string
+		wantCPU     float64
+		wantMode    string
+	}{
+		{"video_encode", "video_*", 95, "used"},
+		{"video_preview", "video_preview", 60, "used"},
+		{"backup", "*", 80, "available"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.task, func(t *testing.T) {
+			thresholds, pattern := tasks.Resolve(tt.task)
+			if pattern != tt.wantPattern {
+				t.Errorf("expected pattern %q, got %q", tt.wantPattern, pattern)
+			}
+			if thresholds.CPU.MaxPercent != tt.wantCPU {
+				t.Errorf("expected cpu.max_percent %v, got %v", tt.wantCPU, thresholds.CPU.MaxPercent)
+			}
+			if thresholds.Memory.Mode != tt.wantMode {
+				t.Errorf("expected memory.mode %s, got %s", tt.wantMode, thresholds.Memory.Mode)
+			}
+			// Options the override does not set are kept
+			if thresholds.Memory.MaxPercent != 85 {
+				t.Errorf("expected default memory.max_percent 85, got %v", thresholds.Memory.MaxPercent)
+			}
+		})
+	}
+
+	video, _ := tasks.Resolve("video_encode")
+	if video.IO.Limit("/") != 90 || video.IO.Limit("/data") != 70 {
+		t.Errorf("expected io.paths merged per key, got %v", video.IO.Paths)
+	}
+	if _, ok := cfg.Thresholds.IO.Paths["/data"]; ok {
+		t.Error("override must not change the global io.paths")
+	}
+}
+
+func TestTaskThresholds_NoMatch(t *testing.T) {
+	cfg := parseConfig(t, `
+tasks:
+  "video_*":
+    thresholds:
+      cpu:
+        max_percent: 95
+`)
+	tasks, err := NewTaskThresholds(cfg.Thresholds, cfg.Tasks, cfg.Priorities)
+	if err != nil {
+		t.Fatalf("failed to resolve tasks: %v", err)
+	}
+
+	thresholds, pattern := tasks.Resolve("backup")
+	if pattern != "" || thresholds.CPU.MaxPercent != cfg.Thresholds.CPU.MaxPercent {
+		t.Errorf("expected global thresholds, got pattern %q and cpu %v", pattern, thresholds.CPU.MaxPercent)
+	}
+}
+
+func TestTaskThresholds_ResolvePriority(t *testing.T) {
+	cfg := parseConfig(t, `
+thresholds:
+  cpu:
+    max_percent: 80
+priorities:
+  low:
+    thresholds:
+      cpu:
+        max_percent: 60
+  high:
+    thresholds:
+      cpu:
+        max_percent: 95
+tasks:
+  "video_*":
+    thresholds:
+      memory:
+        max_percent: 70
+`)
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("expected valid config, got %v", err)
+	}
+
+	tasks, err := NewTaskThresholds(cfg.Thresholds, cfg.Tasks, cfg.Priorities)
+	if err != nil {
+		t.Fatalf("failed to resolve tasks: %v", err)
+	}
+
+	tests := []struct {
+		task         string
+		priority     Priority
+		wantCPU      float64
+		wantMemory   float64
+		wantReserved bool
+	}{
+		{"backup", PriorityLow, 60, 85, true},
+		{"backup", PriorityHigh, 95, 85, true},
+		{"backup", "", 80, 85, false},
+		{"video_encode", PriorityLow, 60, 70, true},
+		{"video_encode", PriorityNormal, 80, 70, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.task+"/"+string(tt.priority), func(t *testing.T) {
+			thresholds, reserved := tasks.ResolvePriority(tt.task, tt.priority)
+			if reserved != tt.wantReserved {
+				t.Errorf("expected reserved %v, got %v", tt.wantReserved, reserved)
+			}
+			if thresholds.CPU.MaxPercent != tt.wantCPU {
+				t.Errorf("expected cpu.max_percent %v, got %v", tt.wantCPU, thresholds.CPU.MaxPercent)
+			}
+			// Priority classes apply on top of the task overrides
+			if thresholds.Memory.MaxPercent != tt.wantMemory {
+				t.Errorf("expected memory.max_percent %v, got %v", tt.wantMemory, thresholds.Memory.MaxPercent)
+			}
+		})
+	}
+}
+
+func TestValidateTasks(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name: "invalid pattern",
+			content: `
+tasks:
+  "video_[":
+    thresholds:
+      cpu:
+        max_percent: 95
+`,
+			wantErr: `invalid pattern "video_["`,
+		},
+		{
+			name: "invalid override",
+			content: `
+tasks:
+  "batch_*":
+    thresholds:
+      cpu:
+        max_percent: 150
+`,
+			wantErr: "batch_*.thresholds: cpu.max_percent must be between 0 and 100",
+		},
+		{
+			name: "invalid priority",
+			content: `
+priorities:
+  urgent:
+    thresholds:
+      cpu:
+        max_percent: 95
+`,
+			wantErr: `priorities: invalid priority "urgent"`,
+		},
+		{
+			name: "invalid priority override",
+			content: `
+priorities:
+  low:
+    thresholds:
+      memory:
+        max_percent: -5
+`,
+			wantErr: "low.thresholds: memory.max_percent must be between 0 and 100",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := parseConfig(t, tt.content).Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestThresholdsOverride_UnknownType(t *testing.T) {
+	cfg := Default()
+	err := yaml.Unmarshal([]byte(`
+tasks:
+  "video_*":
+    thresholds:
+      cpu:
+        max_percent: high
+`), cfg)
+	if err == nil {
+		t.Error("expected error for a non-numeric override")
+	}
+}
+
+func TestThresholdsOverride_MarshalJSON(t *testing.T) {
+	cfg := parseConfig(t, `
+tasks:
+  "render_*":
+    thresholds:
+      gpu:
+        devices:
+          1:
+            max_percent: 50
+`)
+
+	data, err := json.Marshal(cfg.Tasks)
+	if err != nil {
+		t.Fatalf("failed to marshal tasks: %v", err)
+	}
+
+	expected := `{"render_*":{"Thresholds":{"gpu":{"devices":{"1":{"max_percent":50}}}}}}`
+	if string(data) != expected {
+		t.Errorf("expected %s, got %s", expected, data)
+	}
+}
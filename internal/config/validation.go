@@ -3,6 +3,10 @@ package config
 import (
 	"errors"
 	"fmt"
+	"path"
+	"slices"
+
+	"github.com/haskel/capfox/internal/monitor"
 )
 
 func (c *Config) Validate() error {
@@ -32,14 +36,34 @@ func (c *Config) Validate() error {
 		errs = append(errs, fmt.Errorf("learning: %w", err))
 	}
 
+	if err := c.Decision.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("decision: %w", err))
+	}
+
 	if err := c.Auth.Validate(); err != nil {
 		errs = append(errs, fmt.Errorf("auth: %w", err))
 	}
 
+	if err := c.validateModel(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if err := c.validateIOPaths(); err != nil {
+		errs = append(errs, fmt.Errorf("thresholds: %w", err))
+	}
+
 	if err := c.validateDebugSecurity(); err != nil {
 		errs = append(errs, fmt.Errorf("debug security: %w", err))
 	}
 
+	if err := c.validateTasks(); err != nil {
+		errs = append(errs, fmt.Errorf("tasks: %w", err))
+	}
+
+	if err := c.validatePriorities(); err != nil {
+		errs = append(errs, fmt.Errorf("priorities: %w", err))
+	}
+
 	return errors.Join(errs...)
 }
 
@@ -61,26 +85,214 @@ func (t *ThresholdsConfig) Validate() error {
 		errs = append(errs, fmt.Errorf("memory.max_percent must be between 0 and 100"))
 	}
 
+	if t.Memory.Mode != "used" && t.Memory.Mode != "available" {
+		errs = append(errs, fmt.Errorf("invalid memory.mode: %s (valid: used, available)", t.Memory.Mode))
+	}
+
 	if t.GPU.MaxPercent < 0 || t.GPU.MaxPercent > 100 {
 		errs = append(errs, fmt.Errorf("gpu.max_percent must be between 0 and 100"))
 	}
 
+	if t.GPU.MaxCelsius < 0 {
+		errs = append(errs, fmt.Errorf("gpu.max_celsius must be non-negative"))
+	}
+
 	if t.VRAM.MaxPercent < 0 || t.VRAM.MaxPercent > 100 {
 		errs = append(errs, fmt.Errorf("vram.max_percent must be between 0 and 100"))
 	}
 
+	for index, device := range t.GPU.Devices {
+		if index < 0 {
+			errs = append(errs, fmt.Errorf("gpu.devices: index must be non-negative, got %d", index))
+		}
+		if device.MaxPercent < 0 || device.MaxPercent > 100 {
+			errs = append(errs, fmt.Errorf("gpu.devices[%d].max_percent must be between 0 and 100", index))
+		}
+		if device.MaxVRAMPercent < 0 || device.MaxVRAMPercent > 100 {
+			errs = append(errs, fmt.Errorf("gpu.devices[%d].max_vram_percent must be between 0 and 100", index))
+		}
+		if device.MaxCelsius < 0 {
+			errs = append(errs, fmt.Errorf("gpu.devices[%d].max_celsius must be non-negative", index))
+		}
+	}
+
 	if t.Storage.MinFreeGB < 0 {
 		errs = append(errs, fmt.Errorf("storage.min_free_gb must be non-negative"))
 	}
 
+	if t.Storage.MinFreeInodesPercent < 0 || t.Storage.MinFreeInodesPercent > 100 {
+		errs = append(errs, fmt.Errorf("storage.min_free_inodes_percent must be between 0 and 100"))
+	}
+
+	if t.Swap.MaxPercent < 0 || t.Swap.MaxPercent > 100 {
+		errs = append(errs, fmt.Errorf("swap.max_percent must be between 0 and 100"))
+	}
+
+	if t.Swap.MaxPagesPerSec < 0 {
+		errs = append(errs, fmt.Errorf("swap.max_pages_per_sec must be non-negative"))
+	}
+
+	if t.Process.MaxRunning < 0 {
+		errs = append(errs, fmt.Errorf("process.max_running must be non-negative"))
+	}
+
+	if t.Process.MaxBlocked < 0 {
+		errs = append(errs, fmt.Errorf("process.max_blocked must be non-negative"))
+	}
+
+	if err := t.Pressure.Validate(); err != nil {
+		errs = append(errs, fmt.Errorf("pressure: %w", err))
+	}
+
+	if t.IO.MaxUtilPercent < 0 || t.IO.MaxUtilPercent > 100 {
+		errs = append(errs, fmt.Errorf("io.max_util_percent must be between 0 and 100"))
+	}
+
+	for path, limit := range t.IO.Paths {
+		if limit < 0 || limit > 100 {
+			errs = append(errs, fmt.Errorf("io.paths[%s] must be between 0 and 100", path))
+		}
+	}
+
+	if t.Network.MaxPercent < 0 || t.Network.MaxPercent > 100 {
+		errs = append(errs, fmt.Errorf("network.max_percent must be between 0 and 100"))
+	}
+
+	if t.Thermal.MaxCelsius < 0 {
+		errs = append(errs, fmt.Errorf("thermal.max_celsius must be non-negative"))
+	}
+
+	if t.NUMA.MaxMemoryPercent < 0 || t.NUMA.MaxMemoryPercent > 100 {
+		errs = append(errs, fmt.Errorf("numa.max_memory_percent must be between 0 and 100"))
+	}
+
+	if t.NUMA.MaxCPUPercent < 0 || t.NUMA.MaxCPUPercent > 100 {
+		errs = append(errs, fmt.Errorf("numa.max_cpu_percent must be between 0 and 100"))
+	}
+
+	if t.Health.MaxFailures < 0 {
+		errs = append(errs, fmt.Errorf("health.max_failures must be non-negative"))
+	}
+
+	for name, limit := range t.Custom {
+		if name == "" {
+			errs = append(errs, fmt.Errorf("custom metric name cannot be empty"))
+		}
+		if limit.Max == nil && limit.Min == nil {
+			errs = append(errs, fmt.Errorf("custom.%s must set max or min", name))
+		}
+		if limit.Max != nil && limit.Min != nil && *limit.Min > *limit.Max {
+			errs = append(errs, fmt.Errorf("custom.%s.min must not exceed max", name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (p *PressureThreshold) Validate() error {
+	var errs []error
+
+	if p.Window != "avg10" && p.Window != "avg60" {
+		errs = append(errs, fmt.Errorf("invalid window: %s (valid: avg10, avg60)", p.Window))
+	}
+
+	limits := []struct {
+		name  string
+		limit PressureLimit
+	}{
+		{"cpu", p.CPU},
+		{"memory", p.Memory},
+		{"io", p.IO},
+	}
+
+	for _, l := range limits {
+		if l.limit.MaxSome < 0 || l.limit.MaxSome > 100 {
+			errs = append(errs, fmt.Errorf("%s.max_some must be between 0 and 100", l.name))
+		}
+		if l.limit.MaxFull < 0 || l.limit.MaxFull > 100 {
+			errs = append(errs, fmt.Errorf("%s.max_full must be between 0 and 100", l.name))
+		}
+	}
+
 	return errors.Join(errs...)
 }
 
 func (m *MonitoringConfig) Validate() error {
+	var errs []error
+
 	if m.IntervalMS < 100 {
-		return fmt.Errorf("interval_ms must be at least 100, got %d", m.IntervalMS)
+		errs = append(errs, fmt.Errorf("interval_ms must be at least 100, got %d", m.IntervalMS))
 	}
-	return nil
+
+	validBackends := map[string]bool{
+		"auto":   true,
+		"nvidia": true,
+		"amd":    true,
+		"none":   true,
+	}
+
+	if !validBackends[m.GPU.Backend] {
+		errs = append(errs, fmt.Errorf("invalid gpu.backend: %s (valid: auto, nvidia, amd, none)", m.GPU.Backend))
+	}
+
+	if m.Cgroup.Enabled && m.Cgroup.Path == "" {
+		errs = append(errs, fmt.Errorf("cgroup.path is required when cgroup is enabled"))
+	}
+
+	if m.GPU.TimeoutMS < 1 {
+		errs = append(errs, fmt.Errorf("gpu.timeout_ms must be at least 1, got %d", m.GPU.TimeoutMS))
+	}
+
+	if m.CollectTimeoutMS < 1 {
+		errs = append(errs, fmt.Errorf("collect_timeout_ms must be at least 1, got %d", m.CollectTimeoutMS))
+	}
+
+	for name, ms := range m.CollectTimeoutsMS {
+		if ms < 1 {
+			errs = append(errs, fmt.Errorf("collect_timeouts_ms[%s] must be at least 1", name))
+		}
+	}
+
+	if _, err := monitor.ParseSmoothingSpecs(m.Smoothing.Specs()); err != nil {
+		errs = append(errs, fmt.Errorf("smoothing.%w", err))
+	}
+
+	if m.History.RetentionSec < 0 {
+		errs = append(errs, fmt.Errorf("history.retention_sec must be non-negative"))
+	}
+
+	if m.Push.DefaultTTLSec < 1 {
+		errs = append(errs, fmt.Errorf("push.default_ttl_sec must be at least 1, got %d", m.Push.DefaultTTLSec))
+	}
+
+	pluginNames := make(map[string]bool, len(m.Plugins))
+	for i, p := range m.Plugins {
+		switch {
+		case p.Name == "":
+			errs = append(errs, fmt.Errorf("plugins[%d].name cannot be empty", i))
+		case pluginNames[p.Name]:
+			errs = append(errs, fmt.Errorf("duplicate plugin name: %s", p.Name))
+		}
+		pluginNames[p.Name] = true
+
+		if len(p.Command) == 0 || p.Command[0] == "" {
+			errs = append(errs, fmt.Errorf("plugins[%d].command cannot be empty", i))
+		}
+		if p.IntervalMS < 0 {
+			errs = append(errs, fmt.Errorf("plugins[%d].interval_ms must be non-negative", i))
+		}
+		if p.TimeoutMS < 0 {
+			errs = append(errs, fmt.Errorf("plugins[%d].timeout_ms must be non-negative", i))
+		}
+	}
+
+	for iface, mbps := range m.Network.CapacityMbps {
+		if mbps <= 0 {
+			errs = append(errs, fmt.Errorf("network.capacity_mbps[%s] must be positive", iface))
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
 func (p *PersistenceConfig) Validate() error {
@@ -116,7 +328,9 @@ func (l *LoggingConfig) Validate() error {
 }
 
 func (l *LearningConfig) Validate() error {
+	// An empty model leaves the choice to decision.model
 	validModels := map[string]bool{
+		"":                  true,
 		"moving_average":    true,
 		"linear_regression": true,
 	}
@@ -129,6 +343,55 @@ func (l *LearningConfig) Validate() error {
 	return nil
 }
 
+func (d *DecisionConfig) Validate() error {
+	var errs []error
+
+	validStrategies := map[string]bool{
+		"threshold":    true,
+		"predictive":   true,
+		"conservative": true,
+		"queue_aware":  true,
+	}
+
+	if !validStrategies[d.Strategy] {
+		errs = append(errs, fmt.Errorf("invalid strategy: %s (valid: threshold, predictive, conservative, queue_aware)", d.Strategy))
+	}
+
+	if d.FallbackStrategy != "" && !validStrategies[d.FallbackStrategy] {
+		errs = append(errs, fmt.Errorf("invalid fallback_strategy: %s (valid: threshold, predictive, conservative, queue_aware)", d.FallbackStrategy))
+	}
+
+	// An empty model falls back to learning.model
+	validModels := map[string]bool{
+		"":               true,
+		"none":           true,
+		"moving_average": true,
+		"linear":         true,
+	}
+
+	if !validModels[d.Model] {
+		errs = append(errs, fmt.Errorf("invalid model: %s (valid: none, moving_average, linear)", d.Model))
+	}
+
+	if d.MinObservations < 0 {
+		errs = append(errs, fmt.Errorf("min_observations must be non-negative"))
+	}
+
+	if d.SafetyBufferPercent < 0 || d.SafetyBufferPercent > 100 {
+		errs = append(errs, fmt.Errorf("safety_buffer_percent must be between 0 and 100"))
+	}
+
+	if d.ModelParams.Alpha < 0 || d.ModelParams.Alpha > 1 {
+		errs = append(errs, fmt.Errorf("model_params.alpha must be between 0 and 1"))
+	}
+
+	if d.LeaseTTLSec < 1 || d.LeaseTTLSec > MaxLeaseTTLSec {
+		errs = append(errs, fmt.Errorf("lease_ttl_sec must be between 1 and %d", MaxLeaseTTLSec))
+	}
+
+	return errors.Join(errs...)
+}
+
 func (a *AuthConfig) Validate() error {
 	if a.Enabled {
 		if a.User == "" {
@@ -173,3 +436,73 @@ func (c *Config) validateDebugSecurity() error {
 
 	return nil
 }
+
+// validateModel rejects a learning.model that disagrees with decision.model,
+// since both engines share one prediction model.
+func (c *Config) validateModel() error {
+	if c.Decision.Model == "" || c.Learning.Model == "" {
+		return nil
+	}
+	if learningModelType(c.Learning.Model) != c.Decision.Model {
+		return fmt.Errorf("learning.model %s conflicts with decision.model %s, set only decision.model", c.Learning.Model, c.Decision.Model)
+	}
+	return nil
+}
+
+// validateIOPaths checks that per-path I/O limits refer to monitored paths.
+func (c *Config) validateIOPaths() error {
+	monitored := make(map[string]bool, len(c.Monitoring.Paths))
+	for _, path := range c.Monitoring.Paths {
+		monitored[path] = true
+	}
+
+	var errs []error
+	for path := range c.Thresholds.IO.Paths {
+		if !monitored[path] {
+			errs = append(errs, fmt.Errorf("io.paths: %s is not in monitoring.paths", path))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateTasks checks task patterns and the thresholds they resolve to.
+func (c *Config) validateTasks() error {
+	var errs []error
+	for pattern, task := range c.Tasks {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			errs = append(errs, fmt.Errorf("invalid pattern %q", pattern))
+			continue
+		}
+		thresholds, err := task.Thresholds.Apply(c.Thresholds)
+		if err == nil {
+			err = thresholds.Validate()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s.thresholds: %w", pattern, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validatePriorities checks priority classes and the thresholds they
+// resolve to.
+func (c *Config) validatePriorities() error {
+	var errs []error
+	for priority, class := range c.Priorities {
+		if !slices.Contains(Priorities, priority) {
+			errs = append(errs, fmt.Errorf("invalid priority %q (valid: low, normal, high)", priority))
+			continue
+		}
+		thresholds, err := class.Thresholds.Apply(c.Thresholds)
+		if err == nil {
+			err = thresholds.Validate()
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s.thresholds: %w", priority, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
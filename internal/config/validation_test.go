@@ -2,6 +2,7 @@ package config
 
 import (
 	"testing"
+	"time"
 )
 
 func TestValidateDefault(t *testing.T) {
@@ -66,6 +67,20 @@ func TestValidateThresholds(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "memory available mode",
+			modify: func(t *ThresholdsConfig) {
+				t.Memory.Mode = "available"
+			},
+			wantErr: false,
+		},
+		{
+			name: "memory invalid mode",
+			modify: func(t *ThresholdsConfig) {
+				t.Memory.Mode = "free"
+			},
+			wantErr: true,
+		},
 		{
 			name: "storage negative",
 			modify: func(t *ThresholdsConfig) {
@@ -73,6 +88,163 @@ func TestValidateThresholds(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "storage inodes over 100",
+			modify: func(t *ThresholdsConfig) {
+				t.Storage.MinFreeInodesPercent = 110
+			},
+			wantErr: true,
+		},
+		{
+			name: "swap over 100",
+			modify: func(t *ThresholdsConfig) {
+				t.Swap.MaxPercent = 120
+			},
+			wantErr: true,
+		},
+		{
+			name: "swap pages negative",
+			modify: func(t *ThresholdsConfig) {
+				t.Swap.MaxPagesPerSec = -1
+			},
+			wantErr: true,
+		},
+		{
+			name: "process max_running negative",
+			modify: func(t *ThresholdsConfig) {
+				t.Process.MaxRunning = -1
+			},
+			wantErr: true,
+		},
+		{
+			name: "process max_blocked negative",
+			modify: func(t *ThresholdsConfig) {
+				t.Process.MaxBlocked = -1
+			},
+			wantErr: true,
+		},
+		{
+			name: "pressure window invalid",
+			modify: func(t *ThresholdsConfig) {
+				t.Pressure.Window = "avg300"
+			},
+			wantErr: true,
+		},
+		{
+			name: "pressure over 100",
+			modify: func(t *ThresholdsConfig) {
+				t.Pressure.IO.MaxSome = 101
+			},
+			wantErr: true,
+		},
+		{
+			name: "io over 100",
+			modify: func(t *ThresholdsConfig) {
+				t.IO.MaxUtilPercent = 150
+			},
+			wantErr: true,
+		},
+		{
+			name: "io path override negative",
+			modify: func(t *ThresholdsConfig) {
+				t.IO.Paths = map[string]float64{"/": -5}
+			},
+			wantErr: true,
+		},
+		{
+			name: "thermal negative",
+			modify: func(t *ThresholdsConfig) {
+				t.Thermal.MaxCelsius = -1
+			},
+			wantErr: true,
+		},
+		{
+			name: "gpu max_celsius negative",
+			modify: func(t *ThresholdsConfig) {
+				t.GPU.MaxCelsius = -10
+			},
+			wantErr: true,
+		},
+		{
+			name: "gpu device override",
+			modify: func(t *ThresholdsConfig) {
+				t.GPU.Devices = map[int]GPUDeviceThreshold{1: {MaxPercent: 50, MaxVRAMPercent: 70, MaxCelsius: 80}}
+			},
+			wantErr: false,
+		},
+		{
+			name: "gpu device vram over 100",
+			modify: func(t *ThresholdsConfig) {
+				t.GPU.Devices = map[int]GPUDeviceThreshold{0: {MaxVRAMPercent: 110}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "gpu device negative index",
+			modify: func(t *ThresholdsConfig) {
+				t.GPU.Devices = map[int]GPUDeviceThreshold{-1: {MaxPercent: 50}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "numa memory over 100",
+			modify: func(t *ThresholdsConfig) {
+				t.NUMA.MaxMemoryPercent = 120
+			},
+			wantErr: true,
+		},
+		{
+			name: "numa cpu valid",
+			modify: func(t *ThresholdsConfig) {
+				t.NUMA.MaxCPUPercent = 90
+			},
+			wantErr: false,
+		},
+		{
+			name: "health max_failures negative",
+			modify: func(t *ThresholdsConfig) {
+				t.Health.MaxFailures = -1
+			},
+			wantErr: true,
+		},
+		{
+			name: "custom min over max",
+			modify: func(t *ThresholdsConfig) {
+				minSeats, maxSeats := 5.0, 2.0
+				t.Custom = map[string]CustomThreshold{"seats": {Min: &minSeats, Max: &maxSeats}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "custom without bounds",
+			modify: func(t *ThresholdsConfig) {
+				t.Custom = map[string]CustomThreshold{"seats": {}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "custom zero max valid",
+			modify: func(t *ThresholdsConfig) {
+				maxBacklog := 0.0
+				t.Custom = map[string]CustomThreshold{"render_backlog": {Max: &maxBacklog}}
+			},
+			wantErr: false,
+		},
+		{
+			name: "network over 100",
+			modify: func(t *ThresholdsConfig) {
+				t.Network.MaxPercent = 101
+			},
+			wantErr: true,
+		},
+		{
+			name: "pressure valid",
+			modify: func(t *ThresholdsConfig) {
+				t.Pressure.Window = "avg60"
+				t.Pressure.Memory.MaxFull = 10
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -87,6 +259,45 @@ func TestValidateThresholds(t *testing.T) {
 	}
 }
 
+func TestValidateIOPaths(t *testing.T) {
+	cfg := Default()
+	cfg.Monitoring.Paths = []string{"/", "/data"}
+	cfg.Thresholds.IO.Paths = map[string]float64{"/data": 95}
+
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected monitored override to be valid, got %v", err)
+	}
+
+	cfg.Thresholds.IO.Paths["/scratch"] = 90
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for override of unmonitored path")
+	}
+}
+
+func TestValidateModel(t *testing.T) {
+	cfg := Default()
+	cfg.Learning.Model = "moving_average"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected learning.model alone to be valid, got %v", err)
+	}
+
+	cfg.Decision.Model = "moving_average"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected matching models to be valid, got %v", err)
+	}
+
+	cfg.Learning.Model = "linear_regression"
+	cfg.Decision.Model = "linear"
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("expected linear_regression to match linear, got %v", err)
+	}
+
+	cfg.Decision.Model = "moving_average"
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for conflicting models")
+	}
+}
+
 func TestValidateLogging(t *testing.T) {
 	tests := []struct {
 		level   string
@@ -149,6 +360,7 @@ func TestValidateLearning(t *testing.T) {
 	}{
 		{"moving_average", 5, false},
 		{"linear_regression", 5, false},
+		{"", 5, false},
 		{"invalid_model", 5, true},
 		{"moving_average", 0, true},
 	}
@@ -185,6 +397,166 @@ func TestValidateMonitoring(t *testing.T) {
 	}
 }
 
+func TestValidateMonitoringGPUTimeout(t *testing.T) {
+	cfg := Default()
+	cfg.Monitoring.GPU.TimeoutMS = 0
+
+	if err := cfg.Monitoring.Validate(); err == nil {
+		t.Error("expected error for zero gpu.timeout_ms")
+	}
+}
+
+func TestValidateMonitoringCgroup(t *testing.T) {
+	cfg := Default()
+	cfg.Monitoring.Cgroup.Enabled = true
+	cfg.Monitoring.Cgroup.Path = ""
+
+	if err := cfg.Monitoring.Validate(); err == nil {
+		t.Error("expected error for enabled cgroup without path")
+	}
+
+	cfg.Monitoring.Cgroup.Path = "/sys/fs/cgroup"
+	if err := cfg.Monitoring.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateMonitoringNetworkCapacity(t *testing.T) {
+	cfg := Default()
+	cfg.Monitoring.Network.CapacityMbps = map[string]float64{"eth0": 1000}
+
+	if err := cfg.Monitoring.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Monitoring.Network.CapacityMbps["eth1"] = 0
+	if err := cfg.Monitoring.Validate(); err == nil {
+		t.Error("expected error for zero capacity")
+	}
+}
+
+func TestValidateMonitoringHistory(t *testing.T) {
+	cfg := Default()
+	if got := cfg.HistorySamples(); got != 3600 {
+		t.Errorf("expected 3600 samples for 1h at 1s, got %d", got)
+	}
+
+	cfg.Monitoring.History.RetentionSec = 0
+	if got := cfg.HistorySamples(); got != 0 {
+		t.Errorf("expected history disabled, got %d samples", got)
+	}
+
+	cfg.Monitoring.History.RetentionSec = -1
+	if err := cfg.Monitoring.Validate(); err == nil {
+		t.Error("expected error for negative history.retention_sec")
+	}
+}
+
+func TestValidateMonitoringCollectTimeouts(t *testing.T) {
+	cfg := Default()
+	cfg.Monitoring.CollectTimeoutsMS = map[string]int{"storage": 15000}
+	if err := cfg.Monitoring.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	def, overrides := cfg.CollectTimeouts()
+	if def != 5*time.Second || overrides["storage"] != 15*time.Second {
+		t.Errorf("expected 5s default and 15s for storage, got %v and %v", def, overrides)
+	}
+
+	cfg.Monitoring.CollectTimeoutsMS["gpu"] = 0
+	if err := cfg.Monitoring.Validate(); err == nil {
+		t.Error("expected error for zero per-monitor timeout")
+	}
+
+	cfg = Default()
+	cfg.Monitoring.CollectTimeoutMS = 0
+	if err := cfg.Monitoring.Validate(); err == nil {
+		t.Error("expected error for zero collect_timeout_ms")
+	}
+}
+
+func TestValidateMonitoringPlugins(t *testing.T) {
+	cfg := Default()
+	cfg.Monitoring.Plugins = []PluginMonitoringConfig{
+		{Name: "render", Command: []string{"/usr/local/bin/render-queue", "--json"}, IntervalMS: 10000},
+	}
+	if err := cfg.Monitoring.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		plugin PluginMonitoringConfig
+	}{
+		{"empty name", PluginMonitoringConfig{Command: []string{"true"}}},
+		{"duplicate name", PluginMonitoringConfig{Name: "render", Command: []string{"true"}}},
+		{"empty command", PluginMonitoringConfig{Name: "seats"}},
+		{"negative interval", PluginMonitoringConfig{Name: "seats", Command: []string{"true"}, IntervalMS: -1}},
+	}
+
+	for _, tt := range tests {
+		cfg := Default()
+		cfg.Monitoring.Plugins = []PluginMonitoringConfig{
+			{Name: "render", Command: []string{"true"}},
+			tt.plugin,
+		}
+		if err := cfg.Monitoring.Validate(); err == nil {
+			t.Errorf("%s: expected error", tt.name)
+		}
+	}
+}
+
+func TestValidateMonitoringPush(t *testing.T) {
+	cfg := Default()
+	if got := cfg.Monitoring.Push.DefaultTTL(); got != time.Minute {
+		t.Errorf("expected default ttl of 1m, got %s", got)
+	}
+
+	cfg.Monitoring.Push.DefaultTTLSec = 0
+	if err := cfg.Monitoring.Validate(); err == nil {
+		t.Error("expected error for zero push.default_ttl_sec")
+	}
+}
+
+func TestValidateMonitoringSmoothing(t *testing.T) {
+	cfg := Default()
+	cfg.Monitoring.Smoothing.CPU = "ewma(0.3)"
+	cfg.Monitoring.Smoothing.IO = "p95(1m)"
+
+	if err := cfg.Monitoring.Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	cfg.Monitoring.Smoothing.Memory = "avg(1m)"
+	if err := cfg.Monitoring.Validate(); err == nil {
+		t.Error("expected error for unknown smoothing")
+	}
+}
+
+func TestValidateMonitoringGPUBackend(t *testing.T) {
+	tests := []struct {
+		backend string
+		wantErr bool
+	}{
+		{"auto", false},
+		{"nvidia", false},
+		{"amd", false},
+		{"none", false},
+		{"intel", true},
+		{"", true},
+	}
+
+	for _, tt := range tests {
+		cfg := Default()
+		cfg.Monitoring.GPU.Backend = tt.backend
+		err := cfg.Monitoring.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("backend=%q: wantErr=%v, got %v", tt.backend, tt.wantErr, err)
+		}
+	}
+}
+
 func TestValidateDebugSecurity(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -271,3 +643,78 @@ func TestValidateDebugSecurity(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateDecision(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*DecisionConfig)
+		wantErr bool
+	}{
+		{
+			name:    "valid defaults",
+			modify:  func(d *DecisionConfig) {},
+			wantErr: false,
+		},
+		{
+			name: "queue_aware strategy",
+			modify: func(d *DecisionConfig) {
+				d.Strategy = "queue_aware"
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty model falls back to learning.model",
+			modify: func(d *DecisionConfig) {
+				d.Model = ""
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid strategy",
+			modify: func(d *DecisionConfig) {
+				d.Strategy = "random"
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid fallback",
+			modify: func(d *DecisionConfig) {
+				d.FallbackStrategy = "random"
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid model",
+			modify: func(d *DecisionConfig) {
+				d.Model = "neural"
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative min observations",
+			modify: func(d *DecisionConfig) {
+				d.MinObservations = -1
+			},
+			wantErr: true,
+		},
+		{
+			name: "safety buffer over 100",
+			modify: func(d *DecisionConfig) {
+				d.SafetyBufferPercent = 150
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.modify(&cfg.Decision)
+
+			err := cfg.Decision.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("wantErr=%v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -1,8 +1,10 @@
 package decision
 
 import (
+	"sort"
 	"time"
 
+	"github.com/haskel/capfox/internal/config"
 	"github.com/haskel/capfox/internal/monitor"
 )
 
@@ -10,12 +12,32 @@ import (
 type Reason string
 
 const (
-	ReasonCPUOverload      Reason = "cpu_overload"
-	ReasonMemoryOverload   Reason = "memory_overload"
-	ReasonGPUOverload      Reason = "gpu_overload"
-	ReasonVRAMOverload     Reason = "vram_overload"
-	ReasonStorageLow       Reason = "storage_low"
-	ReasonInsufficientData Reason = "insufficient_data"
+	ReasonCPUOverload        Reason = "cpu_overload"
+	ReasonMemoryOverload     Reason = "memory_overload"
+	ReasonGPUOverload        Reason = "gpu_overload"
+	ReasonVRAMOverload       Reason = "vram_overload"
+	ReasonStorageLow         Reason = "storage_low"
+	ReasonStorageInodesLow   Reason = "storage_inodes_low"
+	ReasonStorageReadOnly    Reason = "storage_readonly"
+	ReasonStorageUnavailable Reason = "storage_unavailable"
+	ReasonSwapOverload       Reason = "swap_overload"
+	ReasonSwapThrashing      Reason = "swap_thrashing"
+	ReasonCPUPressure        Reason = "cpu_pressure"
+	ReasonMemoryPressure     Reason = "memory_pressure"
+	ReasonIOPressure         Reason = "io_pressure"
+	ReasonIOSaturated        Reason = "io_saturated"
+	ReasonNetworkSaturated   Reason = "network_saturated"
+	ReasonThermalThrottling  Reason = "thermal_throttling"
+	ReasonNUMAOverload       Reason = "numa_overload"
+	ReasonMetricsStale       Reason = "metrics_stale"
+	ReasonInsufficientData   Reason = "insufficient_data"
+
+	// ReasonCustomPrefix starts the reason of a plugin metric over its
+	// limit, e.g. "custom:render_backlog"
+	ReasonCustomPrefix = "custom:"
+	// ReasonPriorityPrefix starts a reason only the limits of the priority
+	// class raised, e.g. "priority:cpu_overload"
+	ReasonPriorityPrefix = "priority:"
 )
 
 // ResourceEstimate represents client's estimate of resource requirements.
@@ -31,10 +53,66 @@ type ResourceImpact struct {
 	MemoryDelta float64 `json:"memory_delta"`
 	GPUDelta    float64 `json:"gpu_delta,omitempty"`
 	VRAMDelta   float64 `json:"vram_delta,omitempty"`
+	// SwapDelta is the change in swap usage percent
+	SwapDelta float64 `json:"swap_delta,omitempty"`
+	// SwapPagesDelta is the change in swap-in + swap-out pages per second
+	SwapPagesDelta float64 `json:"swap_pages_delta,omitempty"`
+	// IOReadDelta and IOWriteDelta are the change in bytes/s on the monitored devices
+	IOReadDelta  float64 `json:"io_read_delta,omitempty"`
+	IOWriteDelta float64 `json:"io_write_delta,omitempty"`
+	// IOUtilDelta is the change in utilization percent of the busiest monitored device
+	IOUtilDelta float64 `json:"io_util_delta,omitempty"`
+	// NetRxDelta and NetTxDelta are the change in bytes/s over all interfaces
+	NetRxDelta float64 `json:"net_rx_delta,omitempty"`
+	NetTxDelta float64 `json:"net_tx_delta,omitempty"`
+	// NetUtilDelta is the change in usage percent of the busiest interface
+	NetUtilDelta float64 `json:"net_util_delta,omitempty"`
+}
+
+// AddTo adds the impact to state, as if the tasks it was predicted for
+// were running. GPU, I/O and network load is added to every GPU, device
+// and interface, since where the tasks run is unknown.
+func (i *ResourceImpact) AddTo(state *monitor.SystemState) {
+	state.CPU.UsagePercent = min(max(state.CPU.UsagePercent+i.CPUDelta, 0), 100)
+
+	mem := &state.Memory
+	if mem.TotalBytes > 0 && mem.AvailableKnown {
+		used := min(max(mem.UsedPercentFromAvailable()+i.MemoryDelta, 0), 100)
+		mem.AvailableBytes = uint64(float64(mem.TotalBytes) * (100 - used) / 100)
+	}
+	mem.UsagePercent = min(max(mem.UsagePercent+i.MemoryDelta, 0), 100)
+
+	for j := range state.GPUs {
+		gpu := &state.GPUs[j]
+		gpu.UsagePercent = min(max(gpu.UsagePercent+i.GPUDelta, 0), 100)
+		if gpu.VRAMTotalBytes > 0 {
+			vram := min(max(gpu.VRAMPercent()+i.VRAMDelta, 0), 100)
+			gpu.VRAMUsedBytes = uint64(float64(gpu.VRAMTotalBytes) * vram / 100)
+		}
+	}
+
+	state.Swap.UsagePercent = min(max(state.Swap.UsagePercent+i.SwapDelta, 0), 100)
+	// Paging is checked as swap-in plus swap-out
+	state.Swap.PagesInPerSec = max(state.Swap.PagesInPerSec+i.SwapPagesDelta, 0)
+
+	for name, dev := range state.IO.Devices {
+		dev.UtilPercent = min(max(dev.UtilPercent+i.IOUtilDelta, 0), 100)
+		state.IO.Devices[name] = dev
+	}
+
+	for name, iface := range state.Network {
+		if iface.CapacityMbps > 0 {
+			iface.RxPercent = min(max(iface.RxPercent+i.NetUtilDelta, 0), 100)
+			iface.TxPercent = min(max(iface.TxPercent+i.NetUtilDelta, 0), 100)
+			state.Network[name] = iface
+		}
+	}
 }
 
 // PendingTask represents a task awaiting observation.
 type PendingTask struct {
+	// ID is the observation ID from POST /task/notify
+	ID         string
 	Task       string
 	Complexity int
 	StartedAt  time.Time
@@ -47,6 +125,7 @@ type Context struct {
 	Task       string
 	Complexity int
 	Resources  *ResourceEstimate // optional, from client
+	Priority   config.Priority   // class the thresholds were resolved for
 
 	// Current system state
 	CurrentState *monitor.SystemState
@@ -62,38 +141,498 @@ type Context struct {
 	PendingTasks []PendingTask
 }
 
-// ThresholdsConfig holds threshold configuration for decisions.
+// ThresholdsConfig holds threshold configuration for decisions. JSON names
+// follow the thresholds section of the config file.
 type ThresholdsConfig struct {
-	CPU     CPUThreshold
-	Memory  MemoryThreshold
-	GPU     GPUThreshold
-	VRAM    VRAMThreshold
-	Storage StorageThreshold
+	CPU      CPUThreshold               `json:"cpu"`
+	Memory   MemoryThreshold            `json:"memory"`
+	GPU      GPUThreshold               `json:"gpu"`
+	VRAM     VRAMThreshold              `json:"vram"`
+	Storage  StorageThreshold           `json:"storage"`
+	Swap     SwapThreshold              `json:"swap"`
+	Pressure PressureThreshold          `json:"pressure"`
+	IO       IOThreshold                `json:"io"`
+	Network  NetworkThreshold           `json:"network"`
+	Thermal  ThermalThreshold           `json:"thermal"`
+	NUMA     NUMAThreshold              `json:"numa"`
+	Process  ProcessThreshold           `json:"process"`
+	Health   HealthThreshold            `json:"health"`
+	Custom   map[string]CustomThreshold `json:"custom,omitempty"`
 }
 
 // CPUThreshold defines CPU threshold.
 type CPUThreshold struct {
-	MaxPercent float64
+	MaxPercent float64 `json:"max_percent"`
 }
 
-// MemoryThreshold defines memory threshold.
+// MemoryThreshold defines memory threshold. Mode selects the accounting
+// basis passed to monitor.MemoryState.Percent.
 type MemoryThreshold struct {
-	MaxPercent float64
+	MaxPercent float64 `json:"max_percent"`
+	Mode       string  `json:"mode"`
 }
 
-// GPUThreshold defines GPU threshold.
+// GPUThreshold defines GPU threshold. Each GPU is checked on its own
+// limits, see PlaceGPUs. A zero MaxCelsius disables the temperature check.
 type GPUThreshold struct {
-	MaxPercent float64
+	MaxPercent float64                    `json:"max_percent"`
+	MaxCelsius float64                    `json:"max_celsius"`
+	Devices    map[int]GPUDeviceThreshold `json:"devices,omitempty"` // per-index overrides
+}
+
+// GPUDeviceThreshold holds the limits of one GPU. Zero keeps the GPU and
+// VRAM defaults.
+type GPUDeviceThreshold struct {
+	MaxPercent     float64 `json:"max_percent"`
+	MaxVRAMPercent float64 `json:"max_vram_percent"`
+	MaxCelsius     float64 `json:"max_celsius"`
+}
+
+// GPULimits returns the limits of the GPU at index.
+func (t *ThresholdsConfig) GPULimits(index int) GPUDeviceThreshold {
+	override := t.GPU.Devices[index]
+	return GPUDeviceThreshold{
+		MaxPercent:     firstPositive(override.MaxPercent, t.GPU.MaxPercent),
+		MaxVRAMPercent: firstPositive(override.MaxVRAMPercent, t.VRAM.MaxPercent),
+		MaxCelsius:     firstPositive(override.MaxCelsius, t.GPU.MaxCelsius),
+	}
+}
+
+// GPUPlacement is the outcome of fitting a task onto the GPUs.
+type GPUPlacement struct {
+	// Fitting are the indices of GPUs within their limits after the
+	// impact, most headroom first
+	Fitting []int
+	// UsagePercent and VRAMPercent are projected for the first fitting
+	// GPU, or for the GPU with the most headroom if none fits
+	UsagePercent float64
+	VRAMPercent  float64
+	// Busy, Full and Hot report whether any GPU is over its usage, VRAM
+	// or temperature limit
+	Busy, Full, Hot bool
+}
+
+// PlaceGPUs adds the usage and VRAM deltas to each GPU and checks it
+// against the limits for its index. Headroom is the smaller of the usage
+// and VRAM distance to the limits; ties keep the monitor's order.
+func (t *ThresholdsConfig) PlaceGPUs(gpus []monitor.GPUState, usageDelta, vramDelta float64) GPUPlacement {
+	type candidate struct {
+		index       int
+		usage, vram float64
+		headroom    float64
+		fits        bool
+	}
+
+	var placement GPUPlacement
+	candidates := make([]candidate, 0, len(gpus))
+	for _, gpu := range gpus {
+		limits := t.GPULimits(gpu.Index)
+		c := candidate{
+			index: gpu.Index,
+			usage: min(max(gpu.UsagePercent+usageDelta, 0), 100),
+		}
+		c.headroom = limits.MaxPercent - c.usage
+		busy := c.usage > limits.MaxPercent
+
+		var full bool
+		if gpu.VRAMTotalBytes > 0 {
+			c.vram = min(max(gpu.VRAMPercent()+vramDelta, 0), 100)
+			c.headroom = min(c.headroom, limits.MaxVRAMPercent-c.vram)
+			full = c.vram > limits.MaxVRAMPercent
+		}
+
+		hot := limits.MaxCelsius > 0 && float64(gpu.Temperature) > limits.MaxCelsius
+		c.fits = !busy && !full && !hot
+
+		placement.Busy = placement.Busy || busy
+		placement.Full = placement.Full || full
+		placement.Hot = placement.Hot || hot
+		candidates = append(candidates, c)
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].fits != candidates[j].fits {
+			return candidates[i].fits
+		}
+		return candidates[i].headroom > candidates[j].headroom
+	})
+	for _, c := range candidates {
+		if c.fits {
+			placement.Fitting = append(placement.Fitting, c.index)
+		}
+	}
+	if len(candidates) > 0 {
+		placement.UsagePercent = candidates[0].usage
+		placement.VRAMPercent = candidates[0].vram
+	}
+	return placement
+}
+
+// Reasons returns why no GPU fits. Empty if one does or there are no GPUs.
+func (p GPUPlacement) Reasons() []Reason {
+	if len(p.Fitting) > 0 {
+		return nil
+	}
+	var reasons []Reason
+	if p.Busy {
+		reasons = append(reasons, ReasonGPUOverload)
+	}
+	if p.Full {
+		reasons = append(reasons, ReasonVRAMOverload)
+	}
+	if p.Hot {
+		reasons = append(reasons, ReasonThermalThrottling)
+	}
+	return reasons
 }
 
 // VRAMThreshold defines VRAM threshold.
 type VRAMThreshold struct {
-	MaxPercent float64
+	MaxPercent float64 `json:"max_percent"`
 }
 
 // StorageThreshold defines storage threshold.
 type StorageThreshold struct {
-	MinFreeGB float64
+	MinFreeGB            float64 `json:"min_free_gb"`
+	MinFreeInodesPercent float64 `json:"min_free_inodes_percent"`
+}
+
+// Exceeded returns violations for the monitored paths. Unavailable and
+// read-only paths always deny; the inode check is disabled at zero.
+func (t StorageThreshold) Exceeded(storage monitor.StorageState) []Reason {
+	var low, inodesLow, readOnly, unavailable bool
+	for _, disk := range storage {
+		if disk.Error != "" {
+			unavailable = true
+			continue
+		}
+		freeGB := float64(disk.TotalBytes-disk.UsedBytes) / (1024 * 1024 * 1024)
+		if freeGB < t.MinFreeGB {
+			low = true
+		}
+		if t.MinFreeInodesPercent > 0 && disk.FreeInodesPercent() < t.MinFreeInodesPercent {
+			inodesLow = true
+		}
+		if disk.ReadOnly {
+			readOnly = true
+		}
+	}
+
+	var reasons []Reason
+	if low {
+		reasons = append(reasons, ReasonStorageLow)
+	}
+	if inodesLow {
+		reasons = append(reasons, ReasonStorageInodesLow)
+	}
+	if readOnly {
+		reasons = append(reasons, ReasonStorageReadOnly)
+	}
+	if unavailable {
+		reasons = append(reasons, ReasonStorageUnavailable)
+	}
+	return reasons
+}
+
+// SwapThreshold defines swap limits. Zero disables a check.
+type SwapThreshold struct {
+	MaxPercent     float64 `json:"max_percent"`
+	MaxPagesPerSec float64 `json:"max_pages_per_sec"`
+}
+
+// Exceeded returns the swap reasons for the given usage and paging rate.
+func (t SwapThreshold) Exceeded(percent, pagesPerSec float64) []Reason {
+	var reasons []Reason
+	if t.MaxPercent > 0 && percent > t.MaxPercent {
+		reasons = append(reasons, ReasonSwapOverload)
+	}
+	if t.MaxPagesPerSec > 0 && pagesPerSec > t.MaxPagesPerSec {
+		reasons = append(reasons, ReasonSwapThrashing)
+	}
+	return reasons
+}
+
+// PressureThreshold defines PSI limits checked against the chosen window.
+type PressureThreshold struct {
+	Window string        `json:"window"` // avg10 or avg60
+	CPU    PressureLimit `json:"cpu"`
+	Memory PressureLimit `json:"memory"`
+	IO     PressureLimit `json:"io"`
+}
+
+// PressureLimit defines some/full PSI limits in percent. Zero disables a check.
+type PressureLimit struct {
+	MaxSome float64 `json:"max_some"`
+	MaxFull float64 `json:"max_full"`
+}
+
+// Exceeded reports whether PSI values violate the limit.
+func (l PressureLimit) Exceeded(some, full float64) bool {
+	if l.MaxSome > 0 && some > l.MaxSome {
+		return true
+	}
+	return l.MaxFull > 0 && full > l.MaxFull
+}
+
+// IOThreshold defines per-path device utilization limits. Zero disables the check.
+type IOThreshold struct {
+	MaxUtilPercent float64            `json:"max_util_percent"`
+	Paths          map[string]float64 `json:"paths,omitempty"` // per-path overrides of MaxUtilPercent
+}
+
+// Limit returns the utilization limit for a monitored path.
+func (t IOThreshold) Limit(path string) float64 {
+	if limit, ok := t.Paths[path]; ok {
+		return limit
+	}
+	return t.MaxUtilPercent
+}
+
+// Exceeded reports whether the utilization of any monitored path
+// violates its limit.
+func (t IOThreshold) Exceeded(utilByPath map[string]float64) bool {
+	for path, util := range utilByPath {
+		if limit := t.Limit(path); limit > 0 && util > limit {
+			return true
+		}
+	}
+	return false
+}
+
+// NetworkThreshold defines the interface utilization limit. Zero disables the check.
+type NetworkThreshold struct {
+	MaxPercent float64 `json:"max_percent"`
+}
+
+// Exceeded reports whether interface usage violates the limit.
+func (t NetworkThreshold) Exceeded(percent float64) bool {
+	return t.MaxPercent > 0 && percent > t.MaxPercent
+}
+
+// ThermalThreshold defines the temperature limit. Zero disables the check.
+type ThermalThreshold struct {
+	MaxCelsius float64 `json:"max_celsius"`
+}
+
+// Exceeded reports whether the hottest sensor is over the limit or the CPU
+// is being throttled.
+func (t ThermalThreshold) Exceeded(thermal monitor.ThermalState) bool {
+	if t.MaxCelsius <= 0 {
+		return false
+	}
+	return thermal.MaxCelsius() > t.MaxCelsius || thermal.ThrottleEventsPerSec > 0
+}
+
+// NUMAThreshold defines per-node limits. Zero disables a check.
+type NUMAThreshold struct {
+	MaxMemoryPercent float64 `json:"max_memory_percent"`
+	MaxCPUPercent    float64 `json:"max_cpu_percent"`
+}
+
+// Enabled reports whether any per-node limit is set.
+func (t NUMAThreshold) Enabled() bool {
+	return t.MaxMemoryPercent > 0 || t.MaxCPUPercent > 0
+}
+
+// Fits reports whether a node with the given usage is within the limits.
+func (t NUMAThreshold) Fits(cpuPercent, memoryPercent float64) bool {
+	if t.MaxCPUPercent > 0 && cpuPercent > t.MaxCPUPercent {
+		return false
+	}
+	return t.MaxMemoryPercent <= 0 || memoryPercent <= t.MaxMemoryPercent
+}
+
+// BestNUMANode returns the node with the most headroom that fits the
+// per-node limits. Headroom is the smaller of the CPU and memory distance
+// to the node limits, or to the system-wide limits where no node limit is
+// set. Ties go to the lowest node ID. Returns false if no node fits.
+func (t *ThresholdsConfig) BestNUMANode(state *monitor.SystemState) (int, bool) {
+	cpuLimit := firstPositive(t.NUMA.MaxCPUPercent, t.CPU.MaxPercent, 100)
+	memLimit := firstPositive(t.NUMA.MaxMemoryPercent, t.Memory.MaxPercent, 100)
+
+	best, found := 0, false
+	var bestHeadroom float64
+	for _, node := range state.NUMA.Nodes {
+		if node.TotalBytes == 0 || len(node.CPUs) == 0 {
+			// Memory-only and CPU-only nodes cannot host a task alone
+			continue
+		}
+		cpu := state.NodeCPUPercent(node)
+		mem := node.MemoryPercent(t.Memory.Mode)
+		if !t.NUMA.Fits(cpu, mem) {
+			continue
+		}
+		headroom := min(cpuLimit-cpu, memLimit-mem)
+		if !found || headroom > bestHeadroom {
+			best, bestHeadroom, found = node.ID, headroom, true
+		}
+	}
+	return best, found
+}
+
+// NUMAExceeded reports whether per-node limits are set and no node fits.
+// Without NUMA information the check is skipped.
+func (t *ThresholdsConfig) NUMAExceeded(state *monitor.SystemState) bool {
+	if !t.NUMA.Enabled() || len(state.NUMA.Nodes) == 0 {
+		return false
+	}
+	_, ok := t.BestNUMANode(state)
+	return !ok
+}
+
+// ProcessThreshold defines run queue limits. Zero disables a check.
+type ProcessThreshold struct {
+	MaxRunning int `json:"max_running"`
+	MaxBlocked int `json:"max_blocked"`
+}
+
+// Limits returns the limits the thresholds contributed by monitors are
+// checked against. Nil thresholds disable them.
+func (t *ThresholdsConfig) Limits() monitor.Limits {
+	if t == nil {
+		return monitor.Limits{}
+	}
+	return monitor.Limits{Process: monitor.ProcessLimits(t.Process)}
+}
+
+// HealthThreshold defines how many consecutive collection failures of a
+// monitor are tolerated. Zero disables the check.
+type HealthThreshold struct {
+	MaxFailures int `json:"max_failures"`
+}
+
+// Exceeded reports whether any monitor has failed too many times in a row.
+func (t HealthThreshold) Exceeded(health monitor.HealthState) bool {
+	return t.MaxFailures > 0 && len(health.Failing(t.MaxFailures)) > 0
+}
+
+// CustomThreshold limits a plugin metric. A nil bound is not checked.
+type CustomThreshold struct {
+	Max *float64 `json:"max,omitempty"`
+	Min *float64 `json:"min,omitempty"`
+}
+
+// Exceeded reports whether the value is outside the bounds.
+func (t CustomThreshold) Exceeded(value float64) bool {
+	return (t.Max != nil && value > *t.Max) || (t.Min != nil && value < *t.Min)
+}
+
+// CustomExceeded returns a reason for each limited plugin metric that is out
+// of bounds, in name order. A limited metric that no plugin reported fails
+// closed.
+func (t *ThresholdsConfig) CustomExceeded(custom monitor.CustomState) []Reason {
+	names := make([]string, 0, len(t.Custom))
+	for name := range t.Custom {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var reasons []Reason
+	for _, name := range names {
+		value, ok := custom[name]
+		if !ok || t.Custom[name].Exceeded(value) {
+			reasons = append(reasons, Reason(ReasonCustomPrefix+name))
+		}
+	}
+	return reasons
+}
+
+// StateExceeded returns the violations of the limits that no prediction
+// applies to, checked on the current state whatever the strategy: storage,
+// CPU temperature, NUMA nodes, pressure stalls, monitor health and plugin
+// metrics.
+func (t *ThresholdsConfig) StateExceeded(state *monitor.SystemState) []Reason {
+	// Check storage space, inodes and mount health
+	reasons := t.Storage.Exceeded(state.Storage)
+
+	// Check CPU temperatures and active throttling, hot GPUs are part of
+	// the GPU placement
+	if t.Thermal.Exceeded(state.Thermal) {
+		reasons = append(reasons, ReasonThermalThrottling)
+	}
+
+	// Check that at least one NUMA node is within the per-node limits
+	if t.NUMAExceeded(state) {
+		reasons = append(reasons, ReasonNUMAOverload)
+	}
+
+	// Check pressure stall thresholds
+	if t.Pressure.CPU.Exceeded(state.Pressure.CPU.Window(t.Pressure.Window)) {
+		reasons = append(reasons, ReasonCPUPressure)
+	}
+	if t.Pressure.Memory.Exceeded(state.Pressure.Memory.Window(t.Pressure.Window)) {
+		reasons = append(reasons, ReasonMemoryPressure)
+	}
+	if t.Pressure.IO.Exceeded(state.Pressure.IO.Window(t.Pressure.Window)) {
+		reasons = append(reasons, ReasonIOPressure)
+	}
+
+	// Fail closed on stale metrics and on plugin metrics out of bounds
+	if t.Health.Exceeded(state.Health) {
+		reasons = append(reasons, ReasonMetricsStale)
+	}
+	reasons = append(reasons, t.CustomExceeded(state.Custom)...)
+
+	return reasons
+}
+
+// firstPositive returns the first positive value.
+func firstPositive(values ...float64) float64 {
+	for _, v := range values {
+		if v > 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+// ThresholdsFromConfig converts file configuration into decision thresholds.
+func ThresholdsFromConfig(cfg config.ThresholdsConfig) *ThresholdsConfig {
+	return &ThresholdsConfig{
+		CPU:     CPUThreshold{MaxPercent: cfg.CPU.MaxPercent},
+		Memory:  MemoryThreshold(cfg.Memory),
+		GPU:     gpuThreshold(cfg.GPU),
+		VRAM:    VRAMThreshold{MaxPercent: cfg.VRAM.MaxPercent},
+		Storage: StorageThreshold(cfg.Storage),
+		Swap:    SwapThreshold(cfg.Swap),
+		Pressure: PressureThreshold{
+			Window: cfg.Pressure.Window,
+			CPU:    PressureLimit(cfg.Pressure.CPU),
+			Memory: PressureLimit(cfg.Pressure.Memory),
+			IO:     PressureLimit(cfg.Pressure.IO),
+		},
+		IO:      IOThreshold(cfg.IO),
+		Network: NetworkThreshold(cfg.Network),
+		Thermal: ThermalThreshold(cfg.Thermal),
+		NUMA:    NUMAThreshold(cfg.NUMA),
+		Process: ProcessThreshold(cfg.Process),
+		Health:  HealthThreshold(cfg.Health),
+		Custom:  customThresholds(cfg.Custom),
+	}
+}
+
+// gpuThreshold converts GPU limits and their per-index overrides.
+func gpuThreshold(cfg config.GPUThreshold) GPUThreshold {
+	t := GPUThreshold{MaxPercent: cfg.MaxPercent, MaxCelsius: cfg.MaxCelsius}
+	if len(cfg.Devices) > 0 {
+		t.Devices = make(map[int]GPUDeviceThreshold, len(cfg.Devices))
+		for index, device := range cfg.Devices {
+			t.Devices[index] = GPUDeviceThreshold(device)
+		}
+	}
+	return t
+}
+
+// customThresholds converts plugin metric limits.
+func customThresholds(cfg map[string]config.CustomThreshold) map[string]CustomThreshold {
+	if len(cfg) == 0 {
+		return nil
+	}
+	custom := make(map[string]CustomThreshold, len(cfg))
+	for name, limit := range cfg {
+		custom[name] = CustomThreshold(limit)
+	}
+	return custom
 }
 
 // FutureState represents predicted system state after task execution.
@@ -102,6 +641,13 @@ type FutureState struct {
 	MemoryPercent float64 `json:"memory_percent"`
 	GPUPercent    float64 `json:"gpu_percent,omitempty"`
 	VRAMPercent   float64 `json:"vram_percent,omitempty"`
+	SwapPercent   float64 `json:"swap_percent,omitempty"`
+	// SwapPagesPerSec is predicted swap-in + swap-out paging
+	SwapPagesPerSec float64 `json:"swap_pages_per_sec,omitempty"`
+	// IOUtilPercent is predicted device utilization per monitored path
+	IOUtilPercent map[string]float64 `json:"io_util_percent,omitempty"`
+	// NetworkPercent is predicted usage of the busiest interface
+	NetworkPercent float64 `json:"network_percent,omitempty"`
 }
 
 // Result contains the decision outcome.
@@ -116,6 +662,28 @@ type Result struct {
 	// Confidence in the decision (0-1)
 	Confidence float64 `json:"confidence"`
 
+	// NUMANode is the node with the most headroom, for CPU and memory
+	// binding. Nil if NUMA topology is unknown or no node fits.
+	NUMANode *int `json:"numa_node,omitempty"`
+
+	// GPUs are the indices of GPUs that fit the predicted impact, most
+	// headroom first
+	GPUs []int `json:"gpus,omitempty"`
+
+	// TaskPattern is the tasks pattern whose thresholds applied, empty for
+	// the global thresholds
+	TaskPattern string `json:"task_pattern,omitempty"`
+
+	// Priority is the class of the task
+	Priority config.Priority `json:"priority,omitempty"`
+
+	// Thresholds are the limits the task was checked against
+	Thresholds *ThresholdsConfig `json:"thresholds,omitempty"`
+
+	// Aggregation is the smoothing applied to each resource before the
+	// thresholds were checked, e.g. "cpu": "ewma(0.3)"
+	Aggregation map[string]string `json:"aggregation,omitempty"`
+
 	// Metadata
 	Strategy string `json:"strategy"`
 	Model    string `json:"model"`
@@ -159,6 +727,12 @@ func (c *Context) WithPendingTasks(tasks []PendingTask) *Context {
 	return c
 }
 
+// WithPriority sets the priority class of the task.
+func (c *Context) WithPriority(priority config.Priority) *Context {
+	c.Priority = priority
+	return c
+}
+
 // WithResources sets the client's resource estimate.
 func (c *Context) WithResources(resources *ResourceEstimate) *Context {
 	c.Resources = resources
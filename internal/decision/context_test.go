@@ -1,9 +1,11 @@
 package decision
 
 import (
+	"slices"
 	"testing"
 	"time"
 
+	"github.com/haskel/capfox/internal/config"
 	"github.com/haskel/capfox/internal/monitor"
 )
 
@@ -108,3 +110,367 @@ func TestResultStructure(t *testing.T) {
 		t.Errorf("expected CPUPercent 75.0, got %f", result.PredictedState.CPUPercent)
 	}
 }
+
+func TestThresholdsFromConfig(t *testing.T) {
+	thresholds := ThresholdsFromConfig(config.Default().Thresholds)
+
+	if thresholds.CPU.MaxPercent != 80.0 {
+		t.Errorf("expected CPU threshold 80.0, got %f", thresholds.CPU.MaxPercent)
+	}
+	if thresholds.Memory.MaxPercent != 85.0 {
+		t.Errorf("expected memory threshold 85.0, got %f", thresholds.Memory.MaxPercent)
+	}
+	if thresholds.GPU.MaxPercent != 90.0 {
+		t.Errorf("expected GPU threshold 90.0, got %f", thresholds.GPU.MaxPercent)
+	}
+	if thresholds.VRAM.MaxPercent != 85.0 {
+		t.Errorf("expected VRAM threshold 85.0, got %f", thresholds.VRAM.MaxPercent)
+	}
+	if thresholds.Storage.MinFreeGB != 10.0 {
+		t.Errorf("expected storage threshold 10.0, got %f", thresholds.Storage.MinFreeGB)
+	}
+	if thresholds.Pressure.Window != "avg10" {
+		t.Errorf("expected pressure window avg10, got %s", thresholds.Pressure.Window)
+	}
+}
+
+func TestHealthThreshold_Exceeded(t *testing.T) {
+	cfg := config.Default().Thresholds
+	cfg.Health.MaxFailures = 2
+	thresholds := ThresholdsFromConfig(cfg)
+
+	health := monitor.HealthState{"gpu": {Stale: true, ConsecutiveFailures: 1}}
+	if thresholds.Health.Exceeded(health) {
+		t.Error("expected one failure to be tolerated")
+	}
+
+	health["gpu"] = monitor.MonitorHealth{Stale: true, ConsecutiveFailures: 2}
+	if !thresholds.Health.Exceeded(health) {
+		t.Error("expected two failures to exceed max_failures 2")
+	}
+
+	if (HealthThreshold{}).Exceeded(health) {
+		t.Error("expected zero max_failures to disable the check")
+	}
+}
+
+func TestThresholdsConfig_Limits(t *testing.T) {
+	cfg := config.Default().Thresholds
+	cfg.Process.MaxRunning = 8
+	cfg.Process.MaxBlocked = 2
+	thresholds := ThresholdsFromConfig(cfg)
+
+	limits := thresholds.Limits()
+	if limits.Process.MaxRunning != 8 || limits.Process.MaxBlocked != 2 {
+		t.Errorf("expected process limits 8/2, got %+v", limits.Process)
+	}
+
+	var unset *ThresholdsConfig
+	if limits := unset.Limits(); limits != (monitor.Limits{}) {
+		t.Errorf("expected no limits without thresholds, got %+v", limits)
+	}
+}
+
+func TestThresholdsConfig_CustomExceeded(t *testing.T) {
+	maxBacklog, minSeats := 0.0, 2.0
+	cfg := config.Default().Thresholds
+	cfg.Custom = map[string]config.CustomThreshold{
+		"render_backlog":     {Max: &maxBacklog},
+		"license_seats_free": {Min: &minSeats},
+	}
+	thresholds := ThresholdsFromConfig(cfg)
+
+	if reasons := thresholds.CustomExceeded(monitor.CustomState{"render_backlog": 0, "license_seats_free": 5}); len(reasons) != 0 {
+		t.Errorf("expected no reasons, got %v", reasons)
+	}
+
+	// Zero is a valid limit, and a missing metric fails closed
+	reasons := thresholds.CustomExceeded(monitor.CustomState{"render_backlog": 1})
+	if len(reasons) != 2 || reasons[0] != "custom:license_seats_free" || reasons[1] != "custom:render_backlog" {
+		t.Errorf("expected [custom:license_seats_free custom:render_backlog], got %v", reasons)
+	}
+}
+
+func TestThresholdsFromConfig_Pressure(t *testing.T) {
+	cfg := config.Default().Thresholds
+	cfg.Pressure.Window = "avg60"
+	cfg.Pressure.IO = config.PressureLimit{MaxSome: 40, MaxFull: 20}
+
+	thresholds := ThresholdsFromConfig(cfg)
+
+	if thresholds.Pressure.Window != "avg60" {
+		t.Errorf("expected pressure window avg60, got %s", thresholds.Pressure.Window)
+	}
+	if thresholds.Pressure.IO.MaxSome != 40 || thresholds.Pressure.IO.MaxFull != 20 {
+		t.Errorf("expected io limits 40/20, got %+v", thresholds.Pressure.IO)
+	}
+}
+
+func TestPressureLimit_Exceeded(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit PressureLimit
+		some  float64
+		full  float64
+		want  bool
+	}{
+		{"disabled", PressureLimit{}, 100, 100, false},
+		{"some below", PressureLimit{MaxSome: 20}, 20, 0, false},
+		{"some above", PressureLimit{MaxSome: 20}, 21, 0, true},
+		{"full above", PressureLimit{MaxFull: 5}, 0, 6, true},
+		{"full ignored when only some set", PressureLimit{MaxSome: 20}, 10, 50, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limit.Exceeded(tt.some, tt.full); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestResourceImpact_AddTo(t *testing.T) {
+	state := &monitor.SystemState{
+		CPU:    monitor.CPUState{UsagePercent: 50},
+		Memory: monitor.MemoryState{UsagePercent: 40, TotalBytes: 1000, AvailableBytes: 700, AvailableKnown: true},
+		GPUs:   []monitor.GPUState{{Index: 0, UsagePercent: 20, VRAMUsedBytes: 10, VRAMTotalBytes: 100}},
+		Swap:   monitor.SwapState{UsagePercent: 5, PagesInPerSec: 10},
+		IO: monitor.IOState{
+			Devices: map[string]monitor.DeviceIOState{"sda": {UtilPercent: 30}},
+		},
+		Network: monitor.NetworkState{
+			"eth0": {CapacityMbps: 1000, RxPercent: 10, TxPercent: 20},
+			"tun0": {},
+		},
+	}
+
+	impact := &ResourceImpact{
+		CPUDelta:       60,
+		MemoryDelta:    10,
+		GPUDelta:       30,
+		VRAMDelta:      40,
+		SwapDelta:      5,
+		SwapPagesDelta: 100,
+		IOUtilDelta:    20,
+		NetUtilDelta:   15,
+	}
+	impact.AddTo(state)
+
+	if state.CPU.UsagePercent != 100 {
+		t.Errorf("expected cpu clamped to 100, got %v", state.CPU.UsagePercent)
+	}
+	if state.Memory.Percent(monitor.MemoryModeUsed) != 50 || state.Memory.Percent(monitor.MemoryModeAvailable) != 40 {
+		t.Errorf("expected memory 50%% used and 40%% per MemAvailable, got %+v", state.Memory)
+	}
+
+	// Filling the memory leaves it full, not unknown
+	(&ResourceImpact{MemoryDelta: 70}).AddTo(state)
+	(&ResourceImpact{}).AddTo(state)
+	if got := state.Memory.Percent(monitor.MemoryModeAvailable); got != 100 {
+		t.Errorf("expected memory full per MemAvailable, got %v", got)
+	}
+	if gpu := state.GPUs[0]; gpu.UsagePercent != 50 || gpu.VRAMPercent() != 50 {
+		t.Errorf("expected gpu 50%% with 50%% vram, got %+v", gpu)
+	}
+	if state.Swap.UsagePercent != 10 || state.Swap.PagesPerSec() != 110 {
+		t.Errorf("expected swap 10%% at 110 pages/s, got %+v", state.Swap)
+	}
+	if util := state.IO.Devices["sda"].UtilPercent; util != 50 {
+		t.Errorf("expected io util 50, got %v", util)
+	}
+	if eth0 := state.Network["eth0"]; eth0.RxPercent != 25 || eth0.TxPercent != 35 {
+		t.Errorf("expected eth0 at 25/35%%, got %+v", eth0)
+	}
+	// Without a capacity there is no percent to add to
+	if tun0 := state.Network["tun0"]; tun0.UsagePercent() != 0 {
+		t.Errorf("expected tun0 unchanged, got %+v", tun0)
+	}
+}
+
+func TestThresholdsConfig_StateExceeded(t *testing.T) {
+	const gb = 1024 * 1024 * 1024
+
+	tests := []struct {
+		name       string
+		state      *monitor.SystemState
+		thresholds ThresholdsConfig
+		want       []Reason
+	}{
+		{
+			name:       "storage low",
+			state:      &monitor.SystemState{Storage: monitor.StorageState{"/": {UsedBytes: 195 * gb, TotalBytes: 200 * gb}}},
+			thresholds: ThresholdsConfig{Storage: StorageThreshold{MinFreeGB: 10}},
+			want:       []Reason{ReasonStorageLow},
+		},
+		{
+			name: "storage unhealthy",
+			state: &monitor.SystemState{Storage: monitor.StorageState{
+				"/":     {UsedBytes: 50 * gb, TotalBytes: 200 * gb, InodesUsed: 990, InodesTotal: 1000, ReadOnly: true},
+				"/data": {Error: "no such file or directory"},
+			}},
+			thresholds: ThresholdsConfig{Storage: StorageThreshold{MinFreeGB: 10, MinFreeInodesPercent: 5}},
+			want:       []Reason{ReasonStorageInodesLow, ReasonStorageReadOnly, ReasonStorageUnavailable},
+		},
+		{
+			name:       "cpu throttled",
+			state:      &monitor.SystemState{Thermal: monitor.ThermalState{ThrottleEventsPerSec: 4}},
+			thresholds: ThresholdsConfig{Thermal: ThermalThreshold{MaxCelsius: 90}},
+			want:       []Reason{ReasonThermalThrottling},
+		},
+		{
+			name: "every numa node over its limits",
+			state: &monitor.SystemState{
+				CPU: monitor.CPUState{Cores: []float64{95, 5}},
+				NUMA: monitor.NUMAState{Nodes: []monitor.NUMANodeState{
+					{ID: 0, CPUs: []int{0}, TotalBytes: 100, UsedBytes: 10},
+					{ID: 1, CPUs: []int{1}, TotalBytes: 100, UsedBytes: 90},
+				}},
+			},
+			thresholds: ThresholdsConfig{NUMA: NUMAThreshold{MaxMemoryPercent: 85, MaxCPUPercent: 90}},
+			want:       []Reason{ReasonNUMAOverload},
+		},
+		{
+			name: "pressure",
+			state: &monitor.SystemState{Pressure: monitor.PressureState{
+				CPU:    monitor.PressureResource{Some: monitor.PressureStats{Avg10: 10, Avg60: 35}},
+				Memory: monitor.PressureResource{Full: monitor.PressureStats{Avg10: 2, Avg60: 15}},
+				IO:     monitor.PressureResource{Some: monitor.PressureStats{Avg10: 80, Avg60: 45}},
+			}},
+			thresholds: ThresholdsConfig{Pressure: PressureThreshold{
+				Window: "avg60",
+				CPU:    PressureLimit{MaxSome: 30},
+				Memory: PressureLimit{MaxFull: 10},
+				IO:     PressureLimit{MaxSome: 50},
+			}},
+			want: []Reason{ReasonCPUPressure, ReasonMemoryPressure},
+		},
+		{
+			name: "pressure disabled",
+			state: &monitor.SystemState{Pressure: monitor.PressureState{
+				IO: monitor.PressureResource{Some: monitor.PressureStats{Avg10: 99}},
+			}},
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.thresholds.StateExceeded(tt.state)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// numaState returns a two-node system: node0 (CPUs 0-1) is busy on CPU,
+// node1 (CPUs 2-3) is busy on memory.
+func numaState() *monitor.SystemState {
+	return &monitor.SystemState{
+		CPU: monitor.CPUState{UsagePercent: 50, Cores: []float64{80, 80, 20, 20}},
+		NUMA: monitor.NUMAState{
+			Nodes: []monitor.NUMANodeState{
+				{ID: 0, CPUs: []int{0, 1}, TotalBytes: 100, UsedBytes: 30},
+				{ID: 1, CPUs: []int{2, 3}, TotalBytes: 100, UsedBytes: 70},
+			},
+		},
+	}
+}
+
+func TestThresholdsConfig_BestNUMANode(t *testing.T) {
+	tests := []struct {
+		name     string
+		numa     NUMAThreshold
+		wantNode int
+		wantOK   bool
+	}{
+		// Headroom against global limits: node0 min(90-80, 90-30) = 10,
+		// node1 min(90-20, 90-70) = 20
+		{"global limits", NUMAThreshold{}, 1, true},
+		// Headroom against node limits: node0 min(85-80, 95-30) = 5, node1 excluded
+		{"node1 over memory", NUMAThreshold{MaxMemoryPercent: 60, MaxCPUPercent: 85}, 0, true},
+		{"no node fits", NUMAThreshold{MaxMemoryPercent: 60, MaxCPUPercent: 50}, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			thresholds := &ThresholdsConfig{
+				CPU:    CPUThreshold{MaxPercent: 90},
+				Memory: MemoryThreshold{MaxPercent: 90},
+				NUMA:   tt.numa,
+			}
+
+			node, ok := thresholds.BestNUMANode(numaState())
+			if ok != tt.wantOK || (ok && node != tt.wantNode) {
+				t.Errorf("expected node %d (ok=%v), got %d (ok=%v)", tt.wantNode, tt.wantOK, node, ok)
+			}
+			if got := thresholds.NUMAExceeded(numaState()); got != (tt.numa.Enabled() && !tt.wantOK) {
+				t.Errorf("unexpected NUMAExceeded %v", got)
+			}
+		})
+	}
+}
+
+func TestThresholdsConfig_BestNUMANode_NoTopology(t *testing.T) {
+	thresholds := &ThresholdsConfig{NUMA: NUMAThreshold{MaxMemoryPercent: 10}}
+	state := &monitor.SystemState{}
+
+	if _, ok := thresholds.BestNUMANode(state); ok {
+		t.Error("expected no node without NUMA information")
+	}
+	if thresholds.NUMAExceeded(state) {
+		t.Error("expected NUMA check to be skipped without NUMA information")
+	}
+}
+
+func TestThresholdsConfig_GPULimits(t *testing.T) {
+	cfg := config.Default().Thresholds
+	cfg.GPU.MaxCelsius = 85
+	cfg.GPU.Devices = map[int]config.GPUDeviceThreshold{1: {MaxPercent: 50, MaxVRAMPercent: 60}}
+	thresholds := ThresholdsFromConfig(cfg)
+
+	if got := thresholds.GPULimits(0); got != (GPUDeviceThreshold{MaxPercent: 90, MaxVRAMPercent: 85, MaxCelsius: 85}) {
+		t.Errorf("expected defaults for GPU 0, got %+v", got)
+	}
+	if got := thresholds.GPULimits(1); got != (GPUDeviceThreshold{MaxPercent: 50, MaxVRAMPercent: 60, MaxCelsius: 85}) {
+		t.Errorf("expected overrides for GPU 1, got %+v", got)
+	}
+}
+
+func TestThresholdsConfig_PlaceGPUs(t *testing.T) {
+	thresholds := &ThresholdsConfig{
+		GPU:  GPUThreshold{MaxPercent: 90, Devices: map[int]GPUDeviceThreshold{1: {MaxVRAMPercent: 50}}},
+		VRAM: VRAMThreshold{MaxPercent: 80},
+	}
+	gpus := []monitor.GPUState{
+		{Index: 0, UsagePercent: 60, VRAMUsedBytes: 20, VRAMTotalBytes: 100},
+		{Index: 1, UsagePercent: 10, VRAMUsedBytes: 30, VRAMTotalBytes: 100},
+		{Index: 2, UsagePercent: 30, VRAMUsedBytes: 40, VRAMTotalBytes: 100},
+	}
+
+	// Headroom after +20% usage and +10% VRAM: gpu0 min(90-80, 80-30) = 10,
+	// gpu1 min(90-30, 50-40) = 10, gpu2 min(90-50, 80-50) = 30
+	placement := thresholds.PlaceGPUs(gpus, 20, 10)
+	if !slices.Equal(placement.Fitting, []int{2, 0, 1}) {
+		t.Errorf("expected GPUs [2 0 1], got %v", placement.Fitting)
+	}
+
+	// gpu0 is over 90%, gpu1 over its own VRAM limit of 50%
+	placement = thresholds.PlaceGPUs(gpus, 35, 25)
+	if !slices.Equal(placement.Fitting, []int{2}) {
+		t.Errorf("expected GPUs [2], got %v", placement.Fitting)
+	}
+	if placement.UsagePercent != 65 || placement.VRAMPercent != 65 {
+		t.Errorf("expected projection of GPU 2 (65%%, 65%%), got (%v%%, %v%%)", placement.UsagePercent, placement.VRAMPercent)
+	}
+	if reasons := placement.Reasons(); len(reasons) != 0 {
+		t.Errorf("expected no reasons while a GPU fits, got %v", reasons)
+	}
+
+	placement = thresholds.PlaceGPUs(gpus, 85, 0)
+	reasons := placement.Reasons()
+	if len(placement.Fitting) != 0 || len(reasons) != 1 || reasons[0] != ReasonGPUOverload {
+		t.Errorf("expected no GPU and [gpu_overload], got %v %v", placement.Fitting, reasons)
+	}
+}
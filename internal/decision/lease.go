@@ -0,0 +1,162 @@
+package decision
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/haskel/capfox/internal/config"
+)
+
+// Lease reserves the predicted impact of an admitted task until it is
+// released or expires, so tasks admitted at the same moment do not
+// overcommit the host.
+type Lease struct {
+	ID         string          `json:"id"`
+	Task       string          `json:"task"`
+	Complexity int             `json:"complexity,omitempty"`
+	Priority   config.Priority `json:"priority,omitempty"`
+	// Predicted is the impact held on the state decisions are made on, nil
+	// if unknown
+	Predicted *ResourceImpact `json:"predicted,omitempty"`
+	// TTLSec is how far a heartbeat without ttl_sec extends the lease
+	TTLSec    int       `json:"ttl_sec"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Leases holds the active leases. Expired leases are dropped lazily.
+type Leases struct {
+	mu     sync.RWMutex
+	leases map[string]Lease
+}
+
+// NewLeases creates an empty lease table.
+func NewLeases() *Leases {
+	return &Leases{leases: make(map[string]Lease)}
+}
+
+// add stores a new lease of lease.TTLSec from now and returns it with its
+// ID set.
+func (l *Leases) add(lease Lease, now time.Time) Lease {
+	lease.ID = rand.Text()
+	lease.CreatedAt = now
+	lease.ExpiresAt = now.Add(time.Duration(lease.TTLSec) * time.Second)
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.prune(now)
+	l.leases[lease.ID] = lease
+	return lease
+}
+
+// Release removes a lease. Returns false if it does not exist or has
+// already expired.
+func (l *Leases) Release(id string, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lease, ok := l.leases[id]
+	delete(l.leases, id)
+	return ok && lease.ExpiresAt.After(now)
+}
+
+// Heartbeat extends a lease to ttl from now, or to its own TTL if ttl is
+// zero. Returns false if it does not exist or has already expired.
+func (l *Leases) Heartbeat(id string, ttl time.Duration, now time.Time) (Lease, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	lease, ok := l.leases[id]
+	if !ok || !lease.ExpiresAt.After(now) {
+		delete(l.leases, id)
+		return Lease{}, false
+	}
+
+	if ttl > 0 {
+		lease.TTLSec = int(ttl / time.Second)
+	}
+	lease.ExpiresAt = now.Add(time.Duration(lease.TTLSec) * time.Second)
+	l.leases[id] = lease
+	return lease, true
+}
+
+// Active returns the leases not expired at now, oldest first.
+func (l *Leases) Active(now time.Time) []Lease {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	active := make([]Lease, 0, len(l.leases))
+	for _, lease := range l.leases {
+		if lease.ExpiresAt.After(now) {
+			active = append(active, lease)
+		}
+	}
+	sort.Slice(active, func(i, j int) bool {
+		if !active[i].CreatedAt.Equal(active[j].CreatedAt) {
+			return active[i].CreatedAt.Before(active[j].CreatedAt)
+		}
+		return active[i].ID < active[j].ID
+	})
+	return active
+}
+
+// Impact returns the summed predicted impact of the leases active at now,
+// nil if none has a prediction.
+func (l *Leases) Impact(now time.Time) *ResourceImpact {
+	var impact *ResourceImpact
+	for _, lease := range l.Active(now) {
+		p := lease.Predicted
+		if p == nil {
+			continue
+		}
+		if impact == nil {
+			impact = &ResourceImpact{}
+		}
+		impact.CPUDelta += p.CPUDelta
+		impact.MemoryDelta += p.MemoryDelta
+		impact.GPUDelta += p.GPUDelta
+		impact.VRAMDelta += p.VRAMDelta
+		impact.SwapDelta += p.SwapDelta
+		impact.SwapPagesDelta += p.SwapPagesDelta
+		impact.IOUtilDelta += p.IOUtilDelta
+		impact.NetUtilDelta += p.NetUtilDelta
+	}
+	return impact
+}
+
+// prune drops expired leases. Caller must hold the write lock.
+func (l *Leases) prune(now time.Time) {
+	for id, lease := range l.leases {
+		if !lease.ExpiresAt.After(now) {
+			delete(l.leases, id)
+		}
+	}
+}
+
+// Save writes the active leases as JSON.
+func (l *Leases) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(l.Active(time.Now()))
+}
+
+// Load replaces the leases with those read from r, dropping expired ones.
+func (l *Leases) Load(r io.Reader) error {
+	var loaded []Lease
+	if err := json.NewDecoder(r).Decode(&loaded); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.leases = make(map[string]Lease, len(loaded))
+	for _, lease := range loaded {
+		if lease.ID != "" && lease.ExpiresAt.After(now) {
+			l.leases[lease.ID] = lease
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package decision
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestLeases_Lifecycle(t *testing.T) {
+	now := time.Now()
+	leases := NewLeases()
+
+	lease := leases.add(Lease{Task: "encode", TTLSec: 60}, now)
+	if lease.ID == "" || !lease.ExpiresAt.Equal(now.Add(time.Minute)) {
+		t.Fatalf("unexpected lease: %+v", lease)
+	}
+	if active := leases.Active(now); len(active) != 1 || active[0].ID != lease.ID {
+		t.Fatalf("expected the lease active, got %+v", active)
+	}
+
+	// A heartbeat without ttl extends by the lease's own TTL
+	extended, ok := leases.Heartbeat(lease.ID, 0, now.Add(50*time.Second))
+	if !ok || !extended.ExpiresAt.Equal(now.Add(110*time.Second)) {
+		t.Errorf("expected lease extended to 110s, got %+v", extended)
+	}
+	extended, _ = leases.Heartbeat(lease.ID, 10*time.Second, now.Add(100*time.Second))
+	if extended.TTLSec != 10 || !extended.ExpiresAt.Equal(now.Add(110*time.Second)) {
+		t.Errorf("expected ttl 10s from the heartbeat, got %+v", extended)
+	}
+
+	if !leases.Release(lease.ID, now) {
+		t.Error("expected release of an active lease")
+	}
+	if leases.Release(lease.ID, now) {
+		t.Error("expected a second release to fail")
+	}
+}
+
+func TestLeases_Expiry(t *testing.T) {
+	now := time.Now()
+	leases := NewLeases()
+	lease := leases.add(Lease{Task: "encode", TTLSec: 60}, now)
+
+	later := now.Add(61 * time.Second)
+	if active := leases.Active(later); len(active) != 0 {
+		t.Errorf("expected no active leases after expiry, got %+v", active)
+	}
+	if _, ok := leases.Heartbeat(lease.ID, 0, later); ok {
+		t.Error("expected heartbeat of an expired lease to fail")
+	}
+	if leases.Release(lease.ID, later) {
+		t.Error("expected release of an expired lease to fail")
+	}
+}
+
+func TestLeases_Impact(t *testing.T) {
+	now := time.Now()
+	leases := NewLeases()
+
+	if leases.Impact(now) != nil {
+		t.Error("expected no impact without leases")
+	}
+
+	leases.add(Lease{Task: "encode", TTLSec: 60, Predicted: &ResourceImpact{CPUDelta: 20, GPUDelta: 30}}, now)
+	leases.add(Lease{Task: "encode", TTLSec: 60, Predicted: &ResourceImpact{CPUDelta: 15, MemoryDelta: 5}}, now)
+	leases.add(Lease{Task: "unknown", TTLSec: 60}, now)
+	leases.add(Lease{Task: "short", TTLSec: 1, Predicted: &ResourceImpact{CPUDelta: 50}}, now)
+
+	impact := leases.Impact(now.Add(2 * time.Second))
+	if impact == nil || impact.CPUDelta != 35 || impact.MemoryDelta != 5 || impact.GPUDelta != 30 {
+		t.Errorf("expected the summed impact of the active leases, got %+v", impact)
+	}
+}
+
+func TestLeases_SaveLoad(t *testing.T) {
+	now := time.Now()
+	leases := NewLeases()
+	kept := leases.add(Lease{Task: "encode", Complexity: 5, TTLSec: 600, Predicted: &ResourceImpact{CPUDelta: 20}}, now)
+	leases.add(Lease{Task: "backup", TTLSec: 600}, now.Add(-time.Hour)) // already expired
+
+	var buf bytes.Buffer
+	if err := leases.Save(&buf); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+
+	loaded := NewLeases()
+	if err := loaded.Load(&buf); err != nil {
+		t.Fatalf("Load error: %v", err)
+	}
+
+	active := loaded.Active(now)
+	if len(active) != 1 || active[0].ID != kept.ID || active[0].Predicted == nil || active[0].Predicted.CPUDelta != 20 {
+		t.Errorf("expected only the unexpired lease restored, got %+v", active)
+	}
+}
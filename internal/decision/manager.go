@@ -1,8 +1,11 @@
 package decision
 
 import (
+	"slices"
 	"sync"
+	"time"
 
+	"github.com/haskel/capfox/internal/config"
 	"github.com/haskel/capfox/internal/monitor"
 )
 
@@ -30,10 +33,17 @@ type Manager struct {
 	model      PredictionModel
 	aggregator *monitor.Aggregator
 	thresholds *ThresholdsConfig
+	tasks      *config.TaskThresholds
 
 	// For queue-aware strategy
 	mu           sync.RWMutex
 	pendingTasks []PendingTask
+
+	// leases count as pending tasks until released or expired
+	leases *Leases
+	// reserveMu serializes Reserve, so concurrent callers see each other's
+	// leases
+	reserveMu sync.Mutex
 }
 
 // ManagerConfig holds manager configuration.
@@ -55,23 +65,47 @@ func NewManager(
 		aggregator:   aggregator,
 		thresholds:   cfg.Thresholds,
 		pendingTasks: make([]PendingTask, 0),
+		leases:       NewLeases(),
 	}
 }
 
-// Decide makes a decision about whether a task can run.
-func (m *Manager) Decide(task string, complexity int, resources *ResourceEstimate) *Result {
+// Decide makes a decision about whether a task can run at priority.
+// Empty priority is normal.
+func (m *Manager) Decide(task string, complexity int, priority config.Priority, resources *ResourceEstimate) *Result {
 	// Acquire read lock for thresholds and pending tasks
 	m.mu.RLock()
 	thresholds := m.thresholds
+	tasks := m.tasks
 	// Copy slice contents to avoid race condition after unlock
 	pendingTasks := make([]PendingTask, len(m.pendingTasks))
 	copy(pendingTasks, m.pendingTasks)
 	m.mu.RUnlock()
 
+	if priority == "" {
+		priority = config.PriorityNormal
+	}
+
+	// Overrides for the task, then for its priority class, replace the
+	// global thresholds
+	base := thresholds
+	var pattern string
+	var reserved bool
+	if tasks != nil {
+		var resolved config.ThresholdsConfig
+		if resolved, pattern = tasks.Resolve(task); pattern != "" {
+			base = ThresholdsFromConfig(resolved)
+		}
+		thresholds = base
+		if resolved, reserved = tasks.ResolvePriority(task, priority); reserved {
+			thresholds = ThresholdsFromConfig(resolved)
+		}
+	}
+
 	// Build context
 	ctx := NewContext(task, complexity).
 		WithResources(resources).
-		WithCurrentState(m.aggregator.GetState()).
+		WithPriority(priority).
+		WithCurrentState(m.ReservedState()).
 		WithThresholds(thresholds).
 		WithPendingTasks(pendingTasks)
 
@@ -81,8 +115,126 @@ func (m *Manager) Decide(task string, complexity int, resources *ResourceEstimat
 		ctx.WithPrediction(prediction)
 	}
 
+	result := m.decide(ctx)
+
+	// A denial the task thresholds alone would not give comes from the
+	// headroom reserved by the priority class
+	if !result.Allowed && reserved {
+		baseCtx := *ctx
+		baseCtx.Thresholds = base
+		result.Reasons = PriorityReasons(result.Reasons, m.decide(&baseCtx).Reasons)
+	}
+
+	result.Aggregation = m.aggregator.Smoothing()
+	result.TaskPattern = pattern
+	result.Priority = priority
+	result.Thresholds = thresholds
+
+	// Placement is independent of the strategy
+	if thresholds != nil && ctx.CurrentState != nil {
+		if node, ok := thresholds.BestNUMANode(ctx.CurrentState); ok {
+			result.NUMANode = &node
+		}
+	}
+
+	return result
+}
+
+// Reserve decides like Decide and, if the task is allowed, leases its
+// predicted impact for ttl. The lease is nil on denial. A task with
+// neither a prediction nor a client estimate is denied, as its lease
+// would hold nothing.
+func (m *Manager) Reserve(task string, complexity int, priority config.Priority, resources *ResourceEstimate, ttl time.Duration) (*Result, *Lease) {
+	m.reserveMu.Lock()
+	defer m.reserveMu.Unlock()
+
+	result := m.Decide(task, complexity, priority, resources)
+	if !result.Allowed {
+		return result, nil
+	}
+
+	// Without a prediction yet, the client estimate is the best guess
+	var predicted *ResourceImpact
+	if m.model != nil {
+		predicted = m.model.Predict(task, complexity)
+	}
+	if predicted == nil && resources != nil {
+		predicted = &ResourceImpact{
+			CPUDelta:    float64(resources.CPU),
+			MemoryDelta: float64(resources.Memory),
+			GPUDelta:    float64(resources.GPU),
+		}
+	}
+	if predicted == nil {
+		result.Allowed = false
+		result.Reasons = append(result.Reasons, ReasonInsufficientData)
+		return result, nil
+	}
+
+	lease := m.leases.add(Lease{
+		Task:       task,
+		Complexity: complexity,
+		Priority:   result.Priority,
+		Predicted:  predicted,
+		TTLSec:     int(ttl / time.Second),
+	}, time.Now())
+	return result, &lease
+}
+
+// Leases returns the leases holding capacity.
+func (m *Manager) Leases() *Leases {
+	return m.leases
+}
+
+// ReservedState returns the smoothed state with the impact of the active
+// leases added, the state every strategy and its fallback decide on.
+func (m *Manager) ReservedState() *monitor.SystemState {
+	state := m.aggregator.GetSmoothedState()
+	if impact := m.leases.Impact(time.Now()); impact != nil {
+		impact.AddTo(state)
+	}
+	return state
+}
+
+// decide runs the strategy and the checks on the current state.
+func (m *Manager) decide(ctx *Context) *Result {
 	// Delegate to strategy
-	return m.strategy.Decide(ctx)
+	result := m.strategy.Decide(ctx)
+
+	// Limits no prediction applies to are checked on the current state,
+	// so every strategy and its fallback enforce them
+	if ctx.Thresholds != nil && ctx.CurrentState != nil {
+		for _, reason := range ctx.Thresholds.StateExceeded(ctx.CurrentState) {
+			result.Allowed = false
+			// The threshold strategy checks these as well, and a hot
+			// GPU already gave thermal_throttling
+			if !slices.Contains(result.Reasons, reason) {
+				result.Reasons = append(result.Reasons, reason)
+			}
+		}
+	}
+
+	// Thresholds contributed by monitors are current-state checks as well,
+	// against the limits of the task and priority
+	if ctx.CurrentState != nil {
+		for _, reason := range m.aggregator.Check(ctx.CurrentState, ctx.Thresholds.Limits()) {
+			result.Allowed = false
+			result.Reasons = append(result.Reasons, Reason(reason))
+		}
+	}
+
+	return result
+}
+
+// PriorityReasons prefixes the reasons missing from base, which only the
+// thresholds of the priority class raised.
+func PriorityReasons(reasons, base []Reason) []Reason {
+	for i, reason := range reasons {
+		if !slices.Contains(base, reason) {
+			reasons[i] = ReasonPriorityPrefix + reason
+		}
+	}
+	return reasons
 }
 
 // AddPendingTask adds a task to the pending list.
@@ -92,13 +244,14 @@ func (m *Manager) AddPendingTask(task PendingTask) {
 	m.pendingTasks = append(m.pendingTasks, task)
 }
 
-// RemovePendingTask removes a task from the pending list.
+// RemovePendingTask removes a task from the pending list by its
+// observation ID, or by task name for tasks added without one.
 func (m *Manager) RemovePendingTask(taskID string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	for i, t := range m.pendingTasks {
-		if t.Task == taskID {
+		if t.ID == taskID || (t.ID == "" && t.Task == taskID) {
 			m.pendingTasks = append(m.pendingTasks[:i], m.pendingTasks[i+1:]...)
 			return
 		}
@@ -128,3 +281,11 @@ func (m *Manager) UpdateThresholds(thresholds *ThresholdsConfig) {
 	m.thresholds = thresholds
 	m.mu.Unlock()
 }
+
+// SetTaskThresholds sets the per-task overrides Decide resolves the
+// thresholds from. Nil checks every task against the global thresholds.
+func (m *Manager) SetTaskThresholds(tasks *config.TaskThresholds) {
+	m.mu.Lock()
+	m.tasks = tasks
+	m.mu.Unlock()
+}
@@ -1,9 +1,17 @@
 package decision
 
 import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
 	"sync"
 	"testing"
+	"time"
 
+	"gopkg.in/yaml.v3"
+
+	"github.com/haskel/capfox/internal/config"
 	"github.com/haskel/capfox/internal/monitor"
 )
 
@@ -62,7 +70,7 @@ func TestManager_PendingTasks_ConcurrentAccess(t *testing.T) {
 		go func() {
 			defer wg.Done()
 			// This should not race with AddPendingTask
-			_ = mgr.Decide("test", 10, nil)
+			_ = mgr.Decide("test", 10, "", nil)
 		}()
 	}
 
@@ -78,7 +86,7 @@ func TestManager_PendingTasks_ConcurrentAccess(t *testing.T) {
 	wg.Wait()
 
 	// Verify no panic occurred and manager is still functional
-	result := mgr.Decide("final-test", 5, nil)
+	result := mgr.Decide("final-test", 5, "", nil)
 	if result == nil {
 		t.Error("expected non-nil result")
 	}
@@ -136,8 +144,134 @@ func TestManager_UpdateThresholds(t *testing.T) {
 	mgr.UpdateThresholds(newThresholds)
 
 	// Verify update was applied (indirect check via Decide)
-	result := mgr.Decide("test", 10, nil)
+	result := mgr.Decide("test", 10, "", nil)
 	if result == nil {
 		t.Error("expected non-nil result after threshold update")
 	}
 }
+
+// cpuStrategy denies while cpu plus the current CPU usage is above the
+// cpu limit of the context.
+type cpuStrategy struct {
+	cpu float64
+}
+
+func (s *cpuStrategy) Name() string { return "cpu" }
+func (s *cpuStrategy) Decide(ctx *Context) *Result {
+	result := &Result{Allowed: true, Strategy: "cpu"}
+	if s.cpu+ctx.CurrentState.CPU.UsagePercent > ctx.Thresholds.CPU.MaxPercent {
+		result.Allowed = false
+		result.Reasons = append(result.Reasons, ReasonCPUOverload)
+	}
+	return result
+}
+
+func TestManager_Decide_PriorityHeadroom(t *testing.T) {
+	var priorities map[config.Priority]config.PriorityConfig
+	if err := yaml.Unmarshal([]byte(`
+low:
+  thresholds:
+    cpu:
+      max_percent: 60
+`), &priorities); err != nil {
+		t.Fatalf("failed to parse priorities: %v", err)
+	}
+	defaults := config.ThresholdsConfig{CPU: config.CPUThreshold{MaxPercent: 80}}
+	tasks, err := config.NewTaskThresholds(defaults, nil, priorities)
+	if err != nil {
+		t.Fatalf("failed to resolve priorities: %v", err)
+	}
+
+	mgr := NewManager(
+		&cpuStrategy{cpu: 70},
+		nil,
+		mockAggregator(),
+		ManagerConfig{Thresholds: ThresholdsFromConfig(defaults)},
+	)
+	mgr.SetTaskThresholds(tasks)
+
+	result := mgr.Decide("report", 0, config.PriorityLow, nil)
+	if result.Allowed || len(result.Reasons) != 1 || result.Reasons[0] != ReasonPriorityPrefix+ReasonCPUOverload {
+		t.Errorf("expected denial from priority headroom, got %+v", result)
+	}
+	if result.Priority != config.PriorityLow || result.Thresholds.CPU.MaxPercent != 60 {
+		t.Errorf("expected low priority thresholds, got %s with cpu %v", result.Priority, result.Thresholds.CPU.MaxPercent)
+	}
+
+	result = mgr.Decide("report", 0, "", nil)
+	if !result.Allowed || result.Priority != config.PriorityNormal {
+		t.Errorf("expected normal priority allowed, got %+v", result)
+	}
+
+	mgr.strategy = &cpuStrategy{cpu: 90}
+	result = mgr.Decide("report", 0, config.PriorityLow, nil)
+	if len(result.Reasons) != 1 || result.Reasons[0] != ReasonCPUOverload {
+		t.Errorf("expected raw overload above the task thresholds, got %v", result.Reasons)
+	}
+}
+
+// stateMonitor reports a fixed sample.
+type stateMonitor struct {
+	section string
+	sample  monitor.Sample
+}
+
+func (m *stateMonitor) Name() string                     { return m.section }
+func (m *stateMonitor) Section() string                  { return m.section }
+func (m *stateMonitor) Collect() (monitor.Sample, error) { return m.sample, nil }
+
+func TestManager_Decide_StateLimits(t *testing.T) {
+	agg := monitor.NewAggregator([]monitor.Monitor{
+		&stateMonitor{section: "storage", sample: monitor.StorageState{"/data": {Error: "no such file or directory"}}},
+	}, time.Second, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	_ = agg.Start(ctx)
+	defer agg.Stop()
+
+	// The strategy allows everything, the current state still denies
+	mgr := NewManager(&mockStrategy{}, &mockModel{}, agg, ManagerConfig{Thresholds: &ThresholdsConfig{}})
+
+	result := mgr.Decide("backup", 0, "", nil)
+	if result.Allowed || len(result.Reasons) != 1 || result.Reasons[0] != ReasonStorageUnavailable {
+		t.Errorf("expected [storage_unavailable], got %+v", result)
+	}
+}
+
+func TestManager_Reserve(t *testing.T) {
+	mgr := NewManager(
+		&cpuStrategy{},
+		&mockModel{},
+		mockAggregator(),
+		ManagerConfig{Thresholds: &ThresholdsConfig{CPU: CPUThreshold{MaxPercent: 20}}},
+	)
+
+	result, lease := mgr.Reserve("encode", 10, "", &ResourceEstimate{CPU: 30}, time.Minute)
+	if !result.Allowed || lease == nil {
+		t.Fatalf("expected a lease, got %+v", result)
+	}
+	// Without a model prediction the client estimate is leased
+	if lease.Predicted == nil || lease.Predicted.CPUDelta != 30 || lease.TTLSec != 60 {
+		t.Errorf("unexpected lease: %+v", lease)
+	}
+
+	// The lease holds its CPU on the state of the next decision
+	if state := mgr.ReservedState(); state.CPU.UsagePercent != 30 {
+		t.Errorf("expected 30%% cpu held by the lease, got %v", state.CPU.UsagePercent)
+	}
+	result, second := mgr.Reserve("encode", 10, "", nil, time.Minute)
+	if result.Allowed || second != nil {
+		t.Errorf("expected denial while the first lease is held, got %+v", result)
+	}
+
+	mgr.Leases().Release(lease.ID, time.Now())
+	if result := mgr.Decide("encode", 10, "", nil); !result.Allowed {
+		t.Errorf("expected allowed after release, got %+v", result)
+	}
+
+	// Neither a prediction nor an estimate leaves nothing to hold
+	result, empty := mgr.Reserve("encode", 10, "", nil, time.Minute)
+	if result.Allowed || empty != nil || !slices.Contains(result.Reasons, ReasonInsufficientData) {
+		t.Errorf("expected denial for insufficient data, got %+v", result)
+	}
+}
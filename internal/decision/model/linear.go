@@ -46,6 +46,30 @@ type linearTaskData struct {
 	VRAMMeanY float64 `json:"vram_mean_y"`
 	VRAMCov   float64 `json:"vram_cov"`
 
+	// Swap usage
+	SwapMeanY float64 `json:"swap_mean_y,omitempty"`
+	SwapCov   float64 `json:"swap_cov,omitempty"`
+
+	// Swap paging
+	SwapPagesMeanY float64 `json:"swap_pages_mean_y,omitempty"`
+	SwapPagesCov   float64 `json:"swap_pages_cov,omitempty"`
+
+	// I/O throughput and utilization
+	IOReadMeanY  float64 `json:"io_read_mean_y,omitempty"`
+	IOReadCov    float64 `json:"io_read_cov,omitempty"`
+	IOWriteMeanY float64 `json:"io_write_mean_y,omitempty"`
+	IOWriteCov   float64 `json:"io_write_cov,omitempty"`
+	IOUtilMeanY  float64 `json:"io_util_mean_y,omitempty"`
+	IOUtilCov    float64 `json:"io_util_cov,omitempty"`
+
+	// Network throughput and utilization
+	NetRxMeanY   float64 `json:"net_rx_mean_y,omitempty"`
+	NetRxCov     float64 `json:"net_rx_cov,omitempty"`
+	NetTxMeanY   float64 `json:"net_tx_mean_y,omitempty"`
+	NetTxCov     float64 `json:"net_tx_cov,omitempty"`
+	NetUtilMeanY float64 `json:"net_util_mean_y,omitempty"`
+	NetUtilCov   float64 `json:"net_util_cov,omitempty"`
+
 	// Shared variance of X (complexity)
 	VarX float64 `json:"var_x"`
 }
@@ -94,6 +118,17 @@ func (m *LinearModel) Predict(task string, complexity int) *decision.ResourceImp
 		MemoryDelta: coefs.MemA*x + coefs.MemB,
 		GPUDelta:    coefs.GPUA*x + coefs.GPUB,
 		VRAMDelta:   coefs.VRAMA*x + coefs.VRAMB,
+
+		SwapDelta:      coefs.SwapA*x + coefs.SwapB,
+		SwapPagesDelta: coefs.SwapPagesA*x + coefs.SwapPagesB,
+
+		IOReadDelta:  coefs.IOReadA*x + coefs.IOReadB,
+		IOWriteDelta: coefs.IOWriteA*x + coefs.IOWriteB,
+		IOUtilDelta:  coefs.IOUtilA*x + coefs.IOUtilB,
+
+		NetRxDelta:   coefs.NetRxA*x + coefs.NetRxB,
+		NetTxDelta:   coefs.NetTxA*x + coefs.NetTxB,
+		NetUtilDelta: coefs.NetUtilA*x + coefs.NetUtilB,
 	}
 }
 
@@ -112,6 +147,14 @@ func (m *LinearModel) calculateCoefficients(data *linearTaskData) *Coefficients
 		coefs.MemB = data.MemMeanY
 		coefs.GPUB = data.GPUMeanY
 		coefs.VRAMB = data.VRAMMeanY
+		coefs.SwapB = data.SwapMeanY
+		coefs.SwapPagesB = data.SwapPagesMeanY
+		coefs.IOReadB = data.IOReadMeanY
+		coefs.IOWriteB = data.IOWriteMeanY
+		coefs.IOUtilB = data.IOUtilMeanY
+		coefs.NetRxB = data.NetRxMeanY
+		coefs.NetTxB = data.NetTxMeanY
+		coefs.NetUtilB = data.NetUtilMeanY
 		return coefs
 	}
 
@@ -131,6 +174,33 @@ func (m *LinearModel) calculateCoefficients(data *linearTaskData) *Coefficients
 	coefs.VRAMA = data.VRAMCov / data.VarX
 	coefs.VRAMB = data.VRAMMeanY - coefs.VRAMA*data.MeanX
 
+	// Swap
+	coefs.SwapA = data.SwapCov / data.VarX
+	coefs.SwapB = data.SwapMeanY - coefs.SwapA*data.MeanX
+
+	coefs.SwapPagesA = data.SwapPagesCov / data.VarX
+	coefs.SwapPagesB = data.SwapPagesMeanY - coefs.SwapPagesA*data.MeanX
+
+	// I/O
+	coefs.IOReadA = data.IOReadCov / data.VarX
+	coefs.IOReadB = data.IOReadMeanY - coefs.IOReadA*data.MeanX
+
+	coefs.IOWriteA = data.IOWriteCov / data.VarX
+	coefs.IOWriteB = data.IOWriteMeanY - coefs.IOWriteA*data.MeanX
+
+	coefs.IOUtilA = data.IOUtilCov / data.VarX
+	coefs.IOUtilB = data.IOUtilMeanY - coefs.IOUtilA*data.MeanX
+
+	// Network
+	coefs.NetRxA = data.NetRxCov / data.VarX
+	coefs.NetRxB = data.NetRxMeanY - coefs.NetRxA*data.MeanX
+
+	coefs.NetTxA = data.NetTxCov / data.VarX
+	coefs.NetTxB = data.NetTxMeanY - coefs.NetTxA*data.MeanX
+
+	coefs.NetUtilA = data.NetUtilCov / data.VarX
+	coefs.NetUtilB = data.NetUtilMeanY - coefs.NetUtilA*data.MeanX
+
 	return coefs
 }
 
@@ -155,6 +225,17 @@ func (m *LinearModel) Observe(task string, complexity int, impact *decision.Reso
 			MemMeanY:  impact.MemoryDelta,
 			GPUMeanY:  impact.GPUDelta,
 			VRAMMeanY: impact.VRAMDelta,
+
+			SwapMeanY:      impact.SwapDelta,
+			SwapPagesMeanY: impact.SwapPagesDelta,
+
+			IOReadMeanY:  impact.IOReadDelta,
+			IOWriteMeanY: impact.IOWriteDelta,
+			IOUtilMeanY:  impact.IOUtilDelta,
+
+			NetRxMeanY:   impact.NetRxDelta,
+			NetTxMeanY:   impact.NetTxDelta,
+			NetUtilMeanY: impact.NetUtilDelta,
 			// Covariances and variances start at 0
 		}
 		return
@@ -169,6 +250,14 @@ func (m *LinearModel) Observe(task string, complexity int, impact *decision.Reso
 	deltaMem := impact.MemoryDelta - data.MemMeanY
 	deltaGPU := impact.GPUDelta - data.GPUMeanY
 	deltaVRAM := impact.VRAMDelta - data.VRAMMeanY
+	deltaSwap := impact.SwapDelta - data.SwapMeanY
+	deltaSwapPages := impact.SwapPagesDelta - data.SwapPagesMeanY
+	deltaIORead := impact.IOReadDelta - data.IOReadMeanY
+	deltaIOWrite := impact.IOWriteDelta - data.IOWriteMeanY
+	deltaIOUtil := impact.IOUtilDelta - data.IOUtilMeanY
+	deltaNetRx := impact.NetRxDelta - data.NetRxMeanY
+	deltaNetTx := impact.NetTxDelta - data.NetTxMeanY
+	deltaNetUtil := impact.NetUtilDelta - data.NetUtilMeanY
 
 	// Update means
 	data.MeanX += deltaX / n
@@ -176,6 +265,14 @@ func (m *LinearModel) Observe(task string, complexity int, impact *decision.Reso
 	data.MemMeanY += deltaMem / n
 	data.GPUMeanY += deltaGPU / n
 	data.VRAMMeanY += deltaVRAM / n
+	data.SwapMeanY += deltaSwap / n
+	data.SwapPagesMeanY += deltaSwapPages / n
+	data.IOReadMeanY += deltaIORead / n
+	data.IOWriteMeanY += deltaIOWrite / n
+	data.IOUtilMeanY += deltaIOUtil / n
+	data.NetRxMeanY += deltaNetRx / n
+	data.NetTxMeanY += deltaNetTx / n
+	data.NetUtilMeanY += deltaNetUtil / n
 
 	// New deviations (after mean update)
 	deltaX2 := x - data.MeanX
@@ -183,6 +280,14 @@ func (m *LinearModel) Observe(task string, complexity int, impact *decision.Reso
 	deltaMem2 := impact.MemoryDelta - data.MemMeanY
 	deltaGPU2 := impact.GPUDelta - data.GPUMeanY
 	deltaVRAM2 := impact.VRAMDelta - data.VRAMMeanY
+	deltaSwap2 := impact.SwapDelta - data.SwapMeanY
+	deltaSwapPages2 := impact.SwapPagesDelta - data.SwapPagesMeanY
+	deltaIORead2 := impact.IOReadDelta - data.IOReadMeanY
+	deltaIOWrite2 := impact.IOWriteDelta - data.IOWriteMeanY
+	deltaIOUtil2 := impact.IOUtilDelta - data.IOUtilMeanY
+	deltaNetRx2 := impact.NetRxDelta - data.NetRxMeanY
+	deltaNetTx2 := impact.NetTxDelta - data.NetTxMeanY
+	deltaNetUtil2 := impact.NetUtilDelta - data.NetUtilMeanY
 
 	// Update variance of X
 	data.VarX += deltaX * deltaX2
@@ -192,6 +297,14 @@ func (m *LinearModel) Observe(task string, complexity int, impact *decision.Reso
 	data.MemCov += deltaX * deltaMem2
 	data.GPUCov += deltaX * deltaGPU2
 	data.VRAMCov += deltaX * deltaVRAM2
+	data.SwapCov += deltaX * deltaSwap2
+	data.SwapPagesCov += deltaX * deltaSwapPages2
+	data.IOReadCov += deltaX * deltaIORead2
+	data.IOWriteCov += deltaX * deltaIOWrite2
+	data.IOUtilCov += deltaX * deltaIOUtil2
+	data.NetRxCov += deltaX * deltaNetRx2
+	data.NetTxCov += deltaX * deltaNetTx2
+	data.NetUtilCov += deltaX * deltaNetUtil2
 
 	data.Count++
 }
@@ -244,6 +357,18 @@ func (m *LinearModel) Stats() *Stats {
 			AvgMemDelta:  data.MemMeanY,
 			AvgGPUDelta:  data.GPUMeanY,
 			AvgVRAMDelta: data.VRAMMeanY,
+
+			AvgSwapDelta:      data.SwapMeanY,
+			AvgSwapPagesDelta: data.SwapPagesMeanY,
+
+			AvgIOReadDelta:  data.IOReadMeanY,
+			AvgIOWriteDelta: data.IOWriteMeanY,
+			AvgIOUtilDelta:  data.IOUtilMeanY,
+
+			AvgNetRxDelta:   data.NetRxMeanY,
+			AvgNetTxDelta:   data.NetTxMeanY,
+			AvgNetUtilDelta: data.NetUtilMeanY,
+
 			Coefficients: coefs,
 		}
 	}
@@ -278,6 +403,18 @@ func (m *LinearModel) TaskStats(task string) *TaskStats {
 		AvgMemDelta:  data.MemMeanY,
 		AvgGPUDelta:  data.GPUMeanY,
 		AvgVRAMDelta: data.VRAMMeanY,
+
+		AvgSwapDelta:      data.SwapMeanY,
+		AvgSwapPagesDelta: data.SwapPagesMeanY,
+
+		AvgIOReadDelta:  data.IOReadMeanY,
+		AvgIOWriteDelta: data.IOWriteMeanY,
+		AvgIOUtilDelta:  data.IOUtilMeanY,
+
+		AvgNetRxDelta:   data.NetRxMeanY,
+		AvgNetTxDelta:   data.NetTxMeanY,
+		AvgNetUtilDelta: data.NetUtilMeanY,
+
 		Coefficients: coefs,
 	}
 }
@@ -174,6 +174,35 @@ func TestLinearModel_MultipleResources(t *testing.T) {
 	}
 }
 
+func TestLinearModel_SwapPrediction(t *testing.T) {
+	m := NewLinearModel(5)
+
+	for i := 1; i <= 10; i++ {
+		m.Observe("test", i*100, &decision.ResourceImpact{
+			CPUDelta:       10,
+			SwapDelta:      float64(i),       // Swap = 0.01 * x
+			SwapPagesDelta: float64(50 * i), // Pages = 0.5 * x
+		})
+	}
+
+	prediction := m.Predict("test", 500)
+	if prediction == nil {
+		t.Fatal("expected prediction")
+	}
+
+	if math.Abs(prediction.SwapDelta-5.0) > 0.1 {
+		t.Errorf("expected swap ~5, got %f", prediction.SwapDelta)
+	}
+	if math.Abs(prediction.SwapPagesDelta-250.0) > 1.0 {
+		t.Errorf("expected swap pages ~250, got %f", prediction.SwapPagesDelta)
+	}
+
+	stats := m.TaskStats("test")
+	if math.Abs(stats.Coefficients.SwapPagesA-0.5) > 0.01 {
+		t.Errorf("expected swap pages slope 0.5, got %f", stats.Coefficients.SwapPagesA)
+	}
+}
+
 func TestLinearModel_Stats(t *testing.T) {
 	m := NewLinearModel(5)
 
@@ -101,6 +101,17 @@ type TaskStats struct {
 	AvgGPUDelta  float64 `json:"avg_gpu_delta,omitempty"`
 	AvgVRAMDelta float64 `json:"avg_vram_delta,omitempty"`
 
+	AvgSwapDelta      float64 `json:"avg_swap_delta,omitempty"`
+	AvgSwapPagesDelta float64 `json:"avg_swap_pages_delta,omitempty"`
+
+	AvgIOReadDelta  float64 `json:"avg_io_read_delta,omitempty"`
+	AvgIOWriteDelta float64 `json:"avg_io_write_delta,omitempty"`
+	AvgIOUtilDelta  float64 `json:"avg_io_util_delta,omitempty"`
+
+	AvgNetRxDelta   float64 `json:"avg_net_rx_delta,omitempty"`
+	AvgNetTxDelta   float64 `json:"avg_net_tx_delta,omitempty"`
+	AvgNetUtilDelta float64 `json:"avg_net_util_delta,omitempty"`
+
 	// For linear regression model
 	Coefficients *Coefficients `json:"coefficients,omitempty"`
 }
@@ -122,4 +133,32 @@ type Coefficients struct {
 	// VRAM: impact = A * complexity + B
 	VRAMA float64 `json:"vram_a,omitempty"`
 	VRAMB float64 `json:"vram_b,omitempty"`
+
+	// Swap usage: impact = A * complexity + B
+	SwapA float64 `json:"swap_a,omitempty"`
+	SwapB float64 `json:"swap_b,omitempty"`
+
+	// Swap paging: impact = A * complexity + B
+	SwapPagesA float64 `json:"swap_pages_a,omitempty"`
+	SwapPagesB float64 `json:"swap_pages_b,omitempty"`
+
+	// I/O read and write bytes/s: impact = A * complexity + B
+	IOReadA  float64 `json:"io_read_a,omitempty"`
+	IOReadB  float64 `json:"io_read_b,omitempty"`
+	IOWriteA float64 `json:"io_write_a,omitempty"`
+	IOWriteB float64 `json:"io_write_b,omitempty"`
+
+	// I/O device utilization: impact = A * complexity + B
+	IOUtilA float64 `json:"io_util_a,omitempty"`
+	IOUtilB float64 `json:"io_util_b,omitempty"`
+
+	// Network rx and tx bytes/s: impact = A * complexity + B
+	NetRxA float64 `json:"net_rx_a,omitempty"`
+	NetRxB float64 `json:"net_rx_b,omitempty"`
+	NetTxA float64 `json:"net_tx_a,omitempty"`
+	NetTxB float64 `json:"net_tx_b,omitempty"`
+
+	// Network interface utilization: impact = A * complexity + B
+	NetUtilA float64 `json:"net_util_a,omitempty"`
+	NetUtilB float64 `json:"net_util_b,omitempty"`
 }
@@ -24,6 +24,17 @@ type movingAverageTaskData struct {
 	MemAvg  float64 `json:"mem_avg"`
 	GPUAvg  float64 `json:"gpu_avg"`
 	VRAMAvg float64 `json:"vram_avg"`
+
+	SwapAvg      float64 `json:"swap_avg,omitempty"`
+	SwapPagesAvg float64 `json:"swap_pages_avg,omitempty"`
+
+	IOReadAvg  float64 `json:"io_read_avg,omitempty"`
+	IOWriteAvg float64 `json:"io_write_avg,omitempty"`
+	IOUtilAvg  float64 `json:"io_util_avg,omitempty"`
+
+	NetRxAvg   float64 `json:"net_rx_avg,omitempty"`
+	NetTxAvg   float64 `json:"net_tx_avg,omitempty"`
+	NetUtilAvg float64 `json:"net_util_avg,omitempty"`
 }
 
 type movingAverageState struct {
@@ -69,6 +80,17 @@ func (m *MovingAverageModel) Predict(task string, complexity int) *decision.Reso
 		MemoryDelta: data.MemAvg,
 		GPUDelta:    data.GPUAvg,
 		VRAMDelta:   data.VRAMAvg,
+
+		SwapDelta:      data.SwapAvg,
+		SwapPagesDelta: data.SwapPagesAvg,
+
+		IOReadDelta:  data.IOReadAvg,
+		IOWriteDelta: data.IOWriteAvg,
+		IOUtilDelta:  data.IOUtilAvg,
+
+		NetRxDelta:   data.NetRxAvg,
+		NetTxDelta:   data.NetTxAvg,
+		NetUtilDelta: data.NetUtilAvg,
 	}
 }
 
@@ -90,6 +112,17 @@ func (m *MovingAverageModel) Observe(task string, complexity int, impact *decisi
 			MemAvg:  impact.MemoryDelta,
 			GPUAvg:  impact.GPUDelta,
 			VRAMAvg: impact.VRAMDelta,
+
+			SwapAvg:      impact.SwapDelta,
+			SwapPagesAvg: impact.SwapPagesDelta,
+
+			IOReadAvg:  impact.IOReadDelta,
+			IOWriteAvg: impact.IOWriteDelta,
+			IOUtilAvg:  impact.IOUtilDelta,
+
+			NetRxAvg:   impact.NetRxDelta,
+			NetTxAvg:   impact.NetTxDelta,
+			NetUtilAvg: impact.NetUtilDelta,
 		}
 		return
 	}
@@ -100,6 +133,15 @@ func (m *MovingAverageModel) Observe(task string, complexity int, impact *decisi
 	data.MemAvg = m.alpha*impact.MemoryDelta + (1-m.alpha)*data.MemAvg
 	data.GPUAvg = m.alpha*impact.GPUDelta + (1-m.alpha)*data.GPUAvg
 	data.VRAMAvg = m.alpha*impact.VRAMDelta + (1-m.alpha)*data.VRAMAvg
+	data.SwapAvg = m.alpha*impact.SwapDelta + (1-m.alpha)*data.SwapAvg
+	data.SwapPagesAvg = m.alpha*impact.SwapPagesDelta + (1-m.alpha)*data.SwapPagesAvg
+	data.IOReadAvg = m.alpha*impact.IOReadDelta + (1-m.alpha)*data.IOReadAvg
+	data.IOWriteAvg = m.alpha*impact.IOWriteDelta + (1-m.alpha)*data.IOWriteAvg
+	data.IOUtilAvg = m.alpha*impact.IOUtilDelta + (1-m.alpha)*data.IOUtilAvg
+
+	data.NetRxAvg = m.alpha*impact.NetRxDelta + (1-m.alpha)*data.NetRxAvg
+	data.NetTxAvg = m.alpha*impact.NetTxDelta + (1-m.alpha)*data.NetTxAvg
+	data.NetUtilAvg = m.alpha*impact.NetUtilDelta + (1-m.alpha)*data.NetUtilAvg
 }
 
 // Confidence returns confidence based on observation count.
@@ -139,6 +181,17 @@ func (m *MovingAverageModel) Stats() *Stats {
 			AvgMemDelta:  data.MemAvg,
 			AvgGPUDelta:  data.GPUAvg,
 			AvgVRAMDelta: data.VRAMAvg,
+
+			AvgSwapDelta:      data.SwapAvg,
+			AvgSwapPagesDelta: data.SwapPagesAvg,
+
+			AvgIOReadDelta:  data.IOReadAvg,
+			AvgIOWriteDelta: data.IOWriteAvg,
+			AvgIOUtilDelta:  data.IOUtilAvg,
+
+			AvgNetRxDelta:   data.NetRxAvg,
+			AvgNetTxDelta:   data.NetTxAvg,
+			AvgNetUtilDelta: data.NetUtilAvg,
 		}
 	}
 
@@ -167,6 +220,17 @@ func (m *MovingAverageModel) TaskStats(task string) *TaskStats {
 		AvgMemDelta:  data.MemAvg,
 		AvgGPUDelta:  data.GPUAvg,
 		AvgVRAMDelta: data.VRAMAvg,
+
+		AvgSwapDelta:      data.SwapAvg,
+		AvgSwapPagesDelta: data.SwapPagesAvg,
+
+		AvgIOReadDelta:  data.IOReadAvg,
+		AvgIOWriteDelta: data.IOWriteAvg,
+		AvgIOUtilDelta:  data.IOUtilAvg,
+
+		AvgNetRxDelta:   data.NetRxAvg,
+		AvgNetTxDelta:   data.NetTxAvg,
+		AvgNetUtilDelta: data.NetUtilAvg,
 	}
 }
 
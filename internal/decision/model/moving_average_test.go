@@ -96,6 +96,63 @@ func TestMovingAverageModel_MultipleObservations(t *testing.T) {
 	}
 }
 
+func TestMovingAverageModel_SwapDeltas(t *testing.T) {
+	m := NewMovingAverageModel(0.5)
+
+	m.Observe("test", 0, &decision.ResourceImpact{SwapDelta: 10, SwapPagesDelta: 400})
+	m.Observe("test", 0, &decision.ResourceImpact{SwapDelta: 20, SwapPagesDelta: 200})
+
+	prediction := m.Predict("test", 0)
+	if prediction.SwapDelta != 15 {
+		t.Errorf("expected swap delta 15, got %f", prediction.SwapDelta)
+	}
+	if prediction.SwapPagesDelta != 300 {
+		t.Errorf("expected swap pages delta 300, got %f", prediction.SwapPagesDelta)
+	}
+
+	if stats := m.TaskStats("test"); stats.AvgSwapDelta != 15 {
+		t.Errorf("expected avg swap delta 15, got %f", stats.AvgSwapDelta)
+	}
+}
+
+func TestMovingAverageModel_IODeltas(t *testing.T) {
+	m := NewMovingAverageModel(0.5)
+
+	m.Observe("download", 0, &decision.ResourceImpact{IOWriteDelta: 100e6, IOUtilDelta: 40})
+	m.Observe("download", 0, &decision.ResourceImpact{IOWriteDelta: 50e6, IOUtilDelta: 20})
+
+	prediction := m.Predict("download", 0)
+	if prediction.IOWriteDelta != 75e6 {
+		t.Errorf("expected write delta 75e6, got %f", prediction.IOWriteDelta)
+	}
+	if prediction.IOUtilDelta != 30 {
+		t.Errorf("expected util delta 30, got %f", prediction.IOUtilDelta)
+	}
+
+	if stats := m.TaskStats("download"); stats.AvgIOUtilDelta != 30 {
+		t.Errorf("expected avg util delta 30, got %f", stats.AvgIOUtilDelta)
+	}
+}
+
+func TestMovingAverageModel_NetworkDeltas(t *testing.T) {
+	m := NewMovingAverageModel(0.5)
+
+	m.Observe("upload", 0, &decision.ResourceImpact{NetTxDelta: 40e6, NetUtilDelta: 30})
+	m.Observe("upload", 0, &decision.ResourceImpact{NetTxDelta: 20e6, NetUtilDelta: 10})
+
+	prediction := m.Predict("upload", 0)
+	if prediction.NetTxDelta != 30e6 {
+		t.Errorf("expected tx delta 30e6, got %f", prediction.NetTxDelta)
+	}
+	if prediction.NetUtilDelta != 20 {
+		t.Errorf("expected util delta 20, got %f", prediction.NetUtilDelta)
+	}
+
+	if stats := m.TaskStats("upload"); stats.AvgNetUtilDelta != 20 {
+		t.Errorf("expected avg util delta 20, got %f", stats.AvgNetUtilDelta)
+	}
+}
+
 func TestMovingAverageModel_ConfidenceGrows(t *testing.T) {
 	m := NewMovingAverageModel(0.3)
 
@@ -55,16 +55,17 @@ func (s *ConservativeStrategy) Decide(ctx *decision.Context) *decision.Result {
 	}
 
 	// Calculate future state with safety buffer
-	futureState := s.calculateFutureStateWithBuffer(ctx)
+	futureState, gpus := s.calculateFutureStateWithBuffer(ctx)
 
 	// Check if buffered future state exceeds thresholds
-	reasons := s.checkFutureThresholds(futureState, ctx.Thresholds)
+	reasons := s.checkFutureThresholds(futureState, gpus, ctx.Thresholds)
 
 	result := &decision.Result{
 		Allowed:        len(reasons) == 0,
 		Reasons:        reasons,
 		PredictedState: futureState,
 		Confidence:     confidence,
+		GPUs:           gpus.Fitting,
 		Strategy:       s.Name(),
 		Model:          s.model.Name(),
 	}
@@ -73,7 +74,8 @@ func (s *ConservativeStrategy) Decide(ctx *decision.Context) *decision.Result {
 }
 
 // calculateFutureStateWithBuffer calculates predicted state with safety buffer applied.
-func (s *ConservativeStrategy) calculateFutureStateWithBuffer(ctx *decision.Context) *decision.FutureState {
+// GPU figures are those of the GPU the task fits best.
+func (s *ConservativeStrategy) calculateFutureStateWithBuffer(ctx *decision.Context) (*decision.FutureState, decision.GPUPlacement) {
 	state := ctx.CurrentState
 	prediction := ctx.Prediction
 
@@ -85,33 +87,37 @@ func (s *ConservativeStrategy) calculateFutureStateWithBuffer(ctx *decision.Cont
 	bufferedMemoryDelta := prediction.MemoryDelta * bufferMultiplier
 	bufferedGPUDelta := prediction.GPUDelta * bufferMultiplier
 	bufferedVRAMDelta := prediction.VRAMDelta * bufferMultiplier
+	bufferedSwapDelta := prediction.SwapDelta * bufferMultiplier
+	bufferedSwapPagesDelta := prediction.SwapPagesDelta * bufferMultiplier
+	bufferedIOUtilDelta := prediction.IOUtilDelta * bufferMultiplier
+	bufferedNetUtilDelta := prediction.NetUtilDelta * bufferMultiplier
 
 	future := &decision.FutureState{
-		CPUPercent:    state.CPU.UsagePercent + bufferedCPUDelta,
-		MemoryPercent: state.Memory.UsagePercent + bufferedMemoryDelta,
+		CPUPercent:      state.CPU.UsagePercent + bufferedCPUDelta,
+		MemoryPercent:   state.Memory.Percent(ctx.Thresholds.Memory.Mode) + bufferedMemoryDelta,
+		SwapPercent:     state.Swap.UsagePercent + bufferedSwapDelta,
+		SwapPagesPerSec: state.Swap.PagesPerSec() + bufferedSwapPagesDelta,
+		IOUtilPercent:   projectIOUtil(state.IO, bufferedIOUtilDelta),
+		NetworkPercent:  state.Network.MaxPercent() + bufferedNetUtilDelta,
 	}
 
-	// GPU prediction
-	if len(state.GPUs) > 0 {
-		future.GPUPercent = state.GPUs[0].UsagePercent + bufferedGPUDelta
-
-		if state.GPUs[0].VRAMTotalBytes > 0 {
-			currentVRAMPercent := float64(state.GPUs[0].VRAMUsedBytes) / float64(state.GPUs[0].VRAMTotalBytes) * 100
-			future.VRAMPercent = currentVRAMPercent + bufferedVRAMDelta
-		}
-	}
+	// GPU prediction, each GPU is checked on its own
+	gpus := ctx.Thresholds.PlaceGPUs(state.GPUs, bufferedGPUDelta, bufferedVRAMDelta)
+	future.GPUPercent = gpus.UsagePercent
+	future.VRAMPercent = gpus.VRAMPercent
 
 	// Ensure values are within bounds [0, 100]
 	future.CPUPercent = clamp(future.CPUPercent, 0, 100)
 	future.MemoryPercent = clamp(future.MemoryPercent, 0, 100)
-	future.GPUPercent = clamp(future.GPUPercent, 0, 100)
-	future.VRAMPercent = clamp(future.VRAMPercent, 0, 100)
+	future.SwapPercent = clamp(future.SwapPercent, 0, 100)
+	future.SwapPagesPerSec = max(future.SwapPagesPerSec, 0)
+	future.NetworkPercent = clamp(future.NetworkPercent, 0, 100)
 
-	return future
+	return future, gpus
 }
 
 // checkFutureThresholds checks if predicted future state exceeds thresholds.
-func (s *ConservativeStrategy) checkFutureThresholds(future *decision.FutureState, thresholds *decision.ThresholdsConfig) []decision.Reason {
+func (s *ConservativeStrategy) checkFutureThresholds(future *decision.FutureState, gpus decision.GPUPlacement, thresholds *decision.ThresholdsConfig) []decision.Reason {
 	var reasons []decision.Reason
 
 	if future.CPUPercent > thresholds.CPU.MaxPercent {
@@ -122,12 +128,16 @@ func (s *ConservativeStrategy) checkFutureThresholds(future *decision.FutureStat
 		reasons = append(reasons, decision.ReasonMemoryOverload)
 	}
 
-	if future.GPUPercent > thresholds.GPU.MaxPercent {
-		reasons = append(reasons, decision.ReasonGPUOverload)
+	reasons = append(reasons, gpus.Reasons()...)
+
+	reasons = append(reasons, thresholds.Swap.Exceeded(future.SwapPercent, future.SwapPagesPerSec)...)
+
+	if thresholds.IO.Exceeded(future.IOUtilPercent) {
+		reasons = append(reasons, decision.ReasonIOSaturated)
 	}
 
-	if future.VRAMPercent > thresholds.VRAM.MaxPercent {
-		reasons = append(reasons, decision.ReasonVRAMOverload)
+	if thresholds.Network.Exceeded(future.NetworkPercent) {
+		reasons = append(reasons, decision.ReasonNetworkSaturated)
 	}
 
 	return reasons
@@ -3,6 +3,7 @@ package strategy
 import (
 	"github.com/haskel/capfox/internal/decision"
 	"github.com/haskel/capfox/internal/decision/model"
+	"github.com/haskel/capfox/internal/monitor"
 )
 
 // PredictiveStrategy makes decisions based on predicted future resource state.
@@ -52,16 +53,17 @@ func (s *PredictiveStrategy) Decide(ctx *decision.Context) *decision.Result {
 	}
 
 	// Calculate future state
-	futureState := s.calculateFutureState(ctx)
+	futureState, gpus := s.calculateFutureState(ctx)
 
 	// Check if future state exceeds thresholds
-	reasons := s.checkFutureThresholds(futureState, ctx.Thresholds)
+	reasons := s.checkFutureThresholds(futureState, gpus, ctx.Thresholds)
 
 	result := &decision.Result{
 		Allowed:        len(reasons) == 0,
 		Reasons:        reasons,
 		PredictedState: futureState,
 		Confidence:     confidence,
+		GPUs:           gpus.Fitting,
 		Strategy:       s.Name(),
 		Model:          s.model.Name(),
 	}
@@ -70,36 +72,37 @@ func (s *PredictiveStrategy) Decide(ctx *decision.Context) *decision.Result {
 }
 
 // calculateFutureState calculates predicted system state after task execution.
-func (s *PredictiveStrategy) calculateFutureState(ctx *decision.Context) *decision.FutureState {
+// GPU figures are those of the GPU the task fits best.
+func (s *PredictiveStrategy) calculateFutureState(ctx *decision.Context) (*decision.FutureState, decision.GPUPlacement) {
 	state := ctx.CurrentState
 	prediction := ctx.Prediction
 
 	future := &decision.FutureState{
-		CPUPercent:    state.CPU.UsagePercent + prediction.CPUDelta,
-		MemoryPercent: state.Memory.UsagePercent + prediction.MemoryDelta,
+		CPUPercent:      state.CPU.UsagePercent + prediction.CPUDelta,
+		MemoryPercent:   state.Memory.Percent(ctx.Thresholds.Memory.Mode) + prediction.MemoryDelta,
+		SwapPercent:     state.Swap.UsagePercent + prediction.SwapDelta,
+		SwapPagesPerSec: state.Swap.PagesPerSec() + prediction.SwapPagesDelta,
+		IOUtilPercent:   projectIOUtil(state.IO, prediction.IOUtilDelta),
+		NetworkPercent:  state.Network.MaxPercent() + prediction.NetUtilDelta,
 	}
 
-	// GPU prediction (use first GPU for now)
-	if len(state.GPUs) > 0 {
-		future.GPUPercent = state.GPUs[0].UsagePercent + prediction.GPUDelta
-
-		if state.GPUs[0].VRAMTotalBytes > 0 {
-			currentVRAMPercent := float64(state.GPUs[0].VRAMUsedBytes) / float64(state.GPUs[0].VRAMTotalBytes) * 100
-			future.VRAMPercent = currentVRAMPercent + prediction.VRAMDelta
-		}
-	}
+	// GPU prediction, each GPU is checked on its own
+	gpus := ctx.Thresholds.PlaceGPUs(state.GPUs, prediction.GPUDelta, prediction.VRAMDelta)
+	future.GPUPercent = gpus.UsagePercent
+	future.VRAMPercent = gpus.VRAMPercent
 
 	// Ensure values are within bounds [0, 100]
 	future.CPUPercent = clamp(future.CPUPercent, 0, 100)
 	future.MemoryPercent = clamp(future.MemoryPercent, 0, 100)
-	future.GPUPercent = clamp(future.GPUPercent, 0, 100)
-	future.VRAMPercent = clamp(future.VRAMPercent, 0, 100)
+	future.SwapPercent = clamp(future.SwapPercent, 0, 100)
+	future.SwapPagesPerSec = max(future.SwapPagesPerSec, 0)
+	future.NetworkPercent = clamp(future.NetworkPercent, 0, 100)
 
-	return future
+	return future, gpus
 }
 
 // checkFutureThresholds checks if predicted future state exceeds thresholds.
-func (s *PredictiveStrategy) checkFutureThresholds(future *decision.FutureState, thresholds *decision.ThresholdsConfig) []decision.Reason {
+func (s *PredictiveStrategy) checkFutureThresholds(future *decision.FutureState, gpus decision.GPUPlacement, thresholds *decision.ThresholdsConfig) []decision.Reason {
 	var reasons []decision.Reason
 
 	if future.CPUPercent > thresholds.CPU.MaxPercent {
@@ -110,17 +113,31 @@ func (s *PredictiveStrategy) checkFutureThresholds(future *decision.FutureState,
 		reasons = append(reasons, decision.ReasonMemoryOverload)
 	}
 
-	if future.GPUPercent > thresholds.GPU.MaxPercent {
-		reasons = append(reasons, decision.ReasonGPUOverload)
+	reasons = append(reasons, gpus.Reasons()...)
+
+	reasons = append(reasons, thresholds.Swap.Exceeded(future.SwapPercent, future.SwapPagesPerSec)...)
+
+	if thresholds.IO.Exceeded(future.IOUtilPercent) {
+		reasons = append(reasons, decision.ReasonIOSaturated)
 	}
 
-	if future.VRAMPercent > thresholds.VRAM.MaxPercent {
-		reasons = append(reasons, decision.ReasonVRAMOverload)
+	if thresholds.Network.Exceeded(future.NetworkPercent) {
+		reasons = append(reasons, decision.ReasonNetworkSaturated)
 	}
 
 	return reasons
 }
 
+// projectIOUtil adds a utilization delta to the device backing each
+// monitored path, bounded to [0, 100].
+func projectIOUtil(io monitor.IOState, delta float64) map[string]float64 {
+	util := io.PathUtil()
+	for path := range util {
+		util[path] = clamp(util[path]+delta, 0, 100)
+	}
+	return util
+}
+
 // clamp limits value to the range [min, max].
 func clamp(value, min, max float64) float64 {
 	if value < min {
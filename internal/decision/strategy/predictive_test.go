@@ -192,6 +192,164 @@ func TestPredictiveStrategy_Decide_GPUPrediction(t *testing.T) {
 	}
 }
 
+func TestPredictiveStrategy_Decide_PlacesOnFittingGPU(t *testing.T) {
+	prediction := &decision.ResourceImpact{
+		CPUDelta:    5.0,
+		MemoryDelta: 5.0,
+		GPUDelta:    40.0, // gpu0 50 + 40 = 90% > 80%, gpu1 10 + 40 = 50%
+		VRAMDelta:   10.0,
+	}
+	m := newMockModel("test", prediction, 0.85)
+	s := NewPredictiveStrategy(m, 5, nil)
+
+	ctx := decision.NewContext("test", 100).
+		WithCurrentState(&monitor.SystemState{
+			CPU:    monitor.CPUState{UsagePercent: 50.0},
+			Memory: monitor.MemoryState{UsagePercent: 40.0},
+			GPUs: []monitor.GPUState{
+				{Index: 0, UsagePercent: 50.0, VRAMUsedBytes: 5000, VRAMTotalBytes: 10000},
+				{Index: 1, UsagePercent: 10.0, VRAMUsedBytes: 2000, VRAMTotalBytes: 10000},
+			},
+		}).
+		WithThresholds(&decision.ThresholdsConfig{
+			CPU:    decision.CPUThreshold{MaxPercent: 80.0},
+			Memory: decision.MemoryThreshold{MaxPercent: 80.0},
+			GPU:    decision.GPUThreshold{MaxPercent: 80.0},
+			VRAM:   decision.VRAMThreshold{MaxPercent: 80.0},
+		}).
+		WithPrediction(prediction)
+
+	result := s.Decide(ctx)
+
+	if !result.Allowed {
+		t.Errorf("expected allowed=true with a fitting GPU, got reasons %v", result.Reasons)
+	}
+	if len(result.GPUs) != 1 || result.GPUs[0] != 1 {
+		t.Errorf("expected GPUs [1], got %v", result.GPUs)
+	}
+	if result.PredictedState.GPUPercent != 50.0 || result.PredictedState.VRAMPercent != 30.0 {
+		t.Errorf("expected GPU 1 projected to 50%%/30%%, got %f%%/%f%%",
+			result.PredictedState.GPUPercent, result.PredictedState.VRAMPercent)
+	}
+}
+
+func TestPredictiveStrategy_Decide_SwapPrediction(t *testing.T) {
+	prediction := &decision.ResourceImpact{
+		CPUDelta:       5.0,
+		MemoryDelta:    5.0,
+		SwapDelta:      15.0,  // 40 + 15 = 55% > 50%
+		SwapPagesDelta: 900.0, // 200 + 900 = 1100 > 1000
+	}
+	m := newMockModel("test", prediction, 0.9)
+	s := NewPredictiveStrategy(m, 5, nil)
+
+	ctx := decision.NewContext("test", 100).
+		WithCurrentState(&monitor.SystemState{
+			CPU:    monitor.CPUState{UsagePercent: 50.0},
+			Memory: monitor.MemoryState{UsagePercent: 40.0},
+			Swap:   monitor.SwapState{UsagePercent: 40.0, PagesInPerSec: 150, PagesOutPerSec: 50},
+		}).
+		WithThresholds(&decision.ThresholdsConfig{
+			CPU:    decision.CPUThreshold{MaxPercent: 80.0},
+			Memory: decision.MemoryThreshold{MaxPercent: 80.0},
+			Swap:   decision.SwapThreshold{MaxPercent: 50.0, MaxPagesPerSec: 1000},
+		}).
+		WithPrediction(prediction)
+
+	result := s.Decide(ctx)
+
+	if result.Allowed {
+		t.Error("expected allowed=false for predicted swap thrashing")
+	}
+	if !containsReason(result.Reasons, decision.ReasonSwapOverload) {
+		t.Error("expected ReasonSwapOverload in reasons")
+	}
+	if !containsReason(result.Reasons, decision.ReasonSwapThrashing) {
+		t.Error("expected ReasonSwapThrashing in reasons")
+	}
+	if result.PredictedState.SwapPercent != 55.0 {
+		t.Errorf("expected swap 55%%, got %f%%", result.PredictedState.SwapPercent)
+	}
+	if result.PredictedState.SwapPagesPerSec != 1100.0 {
+		t.Errorf("expected 1100 pages/s, got %f", result.PredictedState.SwapPagesPerSec)
+	}
+}
+
+func TestPredictiveStrategy_Decide_NetworkPrediction(t *testing.T) {
+	prediction := &decision.ResourceImpact{
+		CPUDelta:     5.0,
+		MemoryDelta:  5.0,
+		NetUtilDelta: 30.0, // 60 + 30 = 90% > 85%
+	}
+	m := newMockModel("test", prediction, 0.9)
+	s := NewPredictiveStrategy(m, 5, nil)
+
+	ctx := decision.NewContext("test", 100).
+		WithCurrentState(&monitor.SystemState{
+			CPU:    monitor.CPUState{UsagePercent: 50.0},
+			Memory: monitor.MemoryState{UsagePercent: 40.0},
+			Network: monitor.NetworkState{
+				"eth0": {RxPercent: 60.0},
+				"eth1": {TxPercent: 10.0},
+			},
+		}).
+		WithThresholds(&decision.ThresholdsConfig{
+			CPU:     decision.CPUThreshold{MaxPercent: 80.0},
+			Memory:  decision.MemoryThreshold{MaxPercent: 80.0},
+			Network: decision.NetworkThreshold{MaxPercent: 85.0},
+		}).
+		WithPrediction(prediction)
+
+	result := s.Decide(ctx)
+
+	if result.Allowed {
+		t.Error("expected allowed=false for predicted network saturation")
+	}
+	if !containsReason(result.Reasons, decision.ReasonNetworkSaturated) {
+		t.Error("expected ReasonNetworkSaturated in reasons")
+	}
+	if result.PredictedState.NetworkPercent != 90.0 {
+		t.Errorf("expected network 90%%, got %f%%", result.PredictedState.NetworkPercent)
+	}
+}
+
+func TestPredictiveStrategy_Decide_MemoryModeAvailable(t *testing.T) {
+	prediction := &decision.ResourceImpact{
+		CPUDelta:    5.0,
+		MemoryDelta: 15.0, // available basis: 70 + 15 = 85% > 80%
+	}
+	m := newMockModel("test", prediction, 0.9)
+	s := NewPredictiveStrategy(m, 5, nil)
+
+	ctx := decision.NewContext("test", 100).
+		WithCurrentState(&monitor.SystemState{
+			CPU: monitor.CPUState{UsagePercent: 50.0},
+			Memory: monitor.MemoryState{
+				TotalBytes:     1000,
+				UsagePercent:   40.0,
+				AvailableBytes: 300,
+				AvailableKnown: true,
+			},
+		}).
+		WithThresholds(&decision.ThresholdsConfig{
+			CPU:    decision.CPUThreshold{MaxPercent: 80.0},
+			Memory: decision.MemoryThreshold{MaxPercent: 80.0, Mode: monitor.MemoryModeAvailable},
+		}).
+		WithPrediction(prediction)
+
+	result := s.Decide(ctx)
+
+	if result.Allowed {
+		t.Error("expected allowed=false when available memory runs out")
+	}
+	if !containsReason(result.Reasons, decision.ReasonMemoryOverload) {
+		t.Error("expected ReasonMemoryOverload in reasons")
+	}
+	if result.PredictedState.MemoryPercent != 85.0 {
+		t.Errorf("expected memory 85%%, got %f%%", result.PredictedState.MemoryPercent)
+	}
+}
+
 func TestPredictiveStrategy_Decide_ClampsPrediction(t *testing.T) {
 	prediction := &decision.ResourceImpact{
 		CPUDelta:    60.0, // 90 + 60 = 150% should be clamped to 100%
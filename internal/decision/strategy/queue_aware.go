@@ -54,16 +54,17 @@ func (s *QueueAwareStrategy) Decide(ctx *decision.Context) *decision.Result {
 	pendingImpact := s.calculatePendingImpact(ctx)
 
 	// Calculate future state = current + pending tasks impact + new task prediction
-	futureState := s.calculateFutureState(ctx, pendingImpact)
+	futureState, gpus := s.calculateFutureState(ctx, pendingImpact)
 
 	// Check if future state exceeds thresholds
-	reasons := s.checkFutureThresholds(futureState, ctx.Thresholds)
+	reasons := s.checkFutureThresholds(futureState, gpus, ctx.Thresholds)
 
 	result := &decision.Result{
 		Allowed:        len(reasons) == 0,
 		Reasons:        reasons,
 		PredictedState: futureState,
 		Confidence:     confidence,
+		GPUs:           gpus.Fitting,
 		Strategy:       s.Name(),
 		Model:          s.model.Name(),
 	}
@@ -82,6 +83,10 @@ func (s *QueueAwareStrategy) calculatePendingImpact(ctx *decision.Context) *deci
 			impact.MemoryDelta += task.Predicted.MemoryDelta
 			impact.GPUDelta += task.Predicted.GPUDelta
 			impact.VRAMDelta += task.Predicted.VRAMDelta
+			impact.SwapDelta += task.Predicted.SwapDelta
+			impact.SwapPagesDelta += task.Predicted.SwapPagesDelta
+			impact.IOUtilDelta += task.Predicted.IOUtilDelta
+			impact.NetUtilDelta += task.Predicted.NetUtilDelta
 		} else {
 			// Otherwise, get fresh prediction from model
 			prediction := s.model.Predict(task.Task, task.Complexity)
@@ -90,6 +95,10 @@ func (s *QueueAwareStrategy) calculatePendingImpact(ctx *decision.Context) *deci
 				impact.MemoryDelta += prediction.MemoryDelta
 				impact.GPUDelta += prediction.GPUDelta
 				impact.VRAMDelta += prediction.VRAMDelta
+				impact.SwapDelta += prediction.SwapDelta
+				impact.SwapPagesDelta += prediction.SwapPagesDelta
+				impact.IOUtilDelta += prediction.IOUtilDelta
+				impact.NetUtilDelta += prediction.NetUtilDelta
 			}
 		}
 	}
@@ -98,37 +107,40 @@ func (s *QueueAwareStrategy) calculatePendingImpact(ctx *decision.Context) *deci
 }
 
 // calculateFutureState calculates predicted state considering pending tasks.
-func (s *QueueAwareStrategy) calculateFutureState(ctx *decision.Context, pendingImpact *decision.ResourceImpact) *decision.FutureState {
+// The GPU load of pending tasks is added to every GPU, since where they
+// run is unknown. GPU figures are those of the GPU the task fits best.
+func (s *QueueAwareStrategy) calculateFutureState(ctx *decision.Context, pendingImpact *decision.ResourceImpact) (*decision.FutureState, decision.GPUPlacement) {
 	state := ctx.CurrentState
 	prediction := ctx.Prediction
 
 	// Future = Current + Pending Tasks Impact + New Task Prediction
 	future := &decision.FutureState{
-		CPUPercent:    state.CPU.UsagePercent + pendingImpact.CPUDelta + prediction.CPUDelta,
-		MemoryPercent: state.Memory.UsagePercent + pendingImpact.MemoryDelta + prediction.MemoryDelta,
+		CPUPercent:      state.CPU.UsagePercent + pendingImpact.CPUDelta + prediction.CPUDelta,
+		MemoryPercent:   state.Memory.Percent(ctx.Thresholds.Memory.Mode) + pendingImpact.MemoryDelta + prediction.MemoryDelta,
+		SwapPercent:     state.Swap.UsagePercent + pendingImpact.SwapDelta + prediction.SwapDelta,
+		SwapPagesPerSec: state.Swap.PagesPerSec() + pendingImpact.SwapPagesDelta + prediction.SwapPagesDelta,
+		IOUtilPercent:   projectIOUtil(state.IO, pendingImpact.IOUtilDelta+prediction.IOUtilDelta),
+		NetworkPercent:  state.Network.MaxPercent() + pendingImpact.NetUtilDelta + prediction.NetUtilDelta,
 	}
 
-	// GPU prediction
-	if len(state.GPUs) > 0 {
-		future.GPUPercent = state.GPUs[0].UsagePercent + pendingImpact.GPUDelta + prediction.GPUDelta
-
-		if state.GPUs[0].VRAMTotalBytes > 0 {
-			currentVRAMPercent := float64(state.GPUs[0].VRAMUsedBytes) / float64(state.GPUs[0].VRAMTotalBytes) * 100
-			future.VRAMPercent = currentVRAMPercent + pendingImpact.VRAMDelta + prediction.VRAMDelta
-		}
-	}
+	// GPU prediction, each GPU is checked on its own
+	gpus := ctx.Thresholds.PlaceGPUs(state.GPUs,
+		pendingImpact.GPUDelta+prediction.GPUDelta, pendingImpact.VRAMDelta+prediction.VRAMDelta)
+	future.GPUPercent = gpus.UsagePercent
+	future.VRAMPercent = gpus.VRAMPercent
 
 	// Ensure values are within bounds [0, 100]
 	future.CPUPercent = clamp(future.CPUPercent, 0, 100)
 	future.MemoryPercent = clamp(future.MemoryPercent, 0, 100)
-	future.GPUPercent = clamp(future.GPUPercent, 0, 100)
-	future.VRAMPercent = clamp(future.VRAMPercent, 0, 100)
+	future.SwapPercent = clamp(future.SwapPercent, 0, 100)
+	future.SwapPagesPerSec = max(future.SwapPagesPerSec, 0)
+	future.NetworkPercent = clamp(future.NetworkPercent, 0, 100)
 
-	return future
+	return future, gpus
 }
 
 // checkFutureThresholds checks if predicted future state exceeds thresholds.
-func (s *QueueAwareStrategy) checkFutureThresholds(future *decision.FutureState, thresholds *decision.ThresholdsConfig) []decision.Reason {
+func (s *QueueAwareStrategy) checkFutureThresholds(future *decision.FutureState, gpus decision.GPUPlacement, thresholds *decision.ThresholdsConfig) []decision.Reason {
 	var reasons []decision.Reason
 
 	if future.CPUPercent > thresholds.CPU.MaxPercent {
@@ -139,12 +151,16 @@ func (s *QueueAwareStrategy) checkFutureThresholds(future *decision.FutureState,
 		reasons = append(reasons, decision.ReasonMemoryOverload)
 	}
 
-	if future.GPUPercent > thresholds.GPU.MaxPercent {
-		reasons = append(reasons, decision.ReasonGPUOverload)
+	reasons = append(reasons, gpus.Reasons()...)
+
+	reasons = append(reasons, thresholds.Swap.Exceeded(future.SwapPercent, future.SwapPagesPerSec)...)
+
+	if thresholds.IO.Exceeded(future.IOUtilPercent) {
+		reasons = append(reasons, decision.ReasonIOSaturated)
 	}
 
-	if future.VRAMPercent > thresholds.VRAM.MaxPercent {
-		reasons = append(reasons, decision.ReasonVRAMOverload)
+	if thresholds.Network.Exceeded(future.NetworkPercent) {
+		reasons = append(reasons, decision.ReasonNetworkSaturated)
 	}
 
 	return reasons
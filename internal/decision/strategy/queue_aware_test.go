@@ -190,6 +190,56 @@ func TestQueueAwareStrategy_Decide_RejectsWithPendingTasks(t *testing.T) {
 	}
 }
 
+func TestQueueAwareStrategy_Decide_IOWithPendingTasks(t *testing.T) {
+	newTaskPrediction := &decision.ResourceImpact{IOUtilDelta: 30.0}
+	m := newMockModel("test", newTaskPrediction, 0.9)
+	s := NewQueueAwareStrategy(m, 5, nil)
+
+	pendingTasks := []decision.PendingTask{
+		{
+			Task:      "video_encode",
+			StartedAt: time.Now(),
+			Predicted: &decision.ResourceImpact{IOUtilDelta: 35.0},
+		},
+	}
+
+	ctx := decision.NewContext("video_encode", 100).
+		WithCurrentState(&monitor.SystemState{
+			CPU:    monitor.CPUState{UsagePercent: 20.0},
+			Memory: monitor.MemoryState{UsagePercent: 20.0},
+			IO: monitor.IOState{
+				Devices: map[string]monitor.DeviceIOState{
+					"nvme0n1p2": {UtilPercent: 10.0},
+					"sdb1":      {UtilPercent: 25.0},
+				},
+				Paths: map[string]string{"/": "nvme0n1p2", "/data": "sdb1"},
+			},
+		}).
+		WithThresholds(&decision.ThresholdsConfig{
+			CPU:    decision.CPUThreshold{MaxPercent: 80.0},
+			Memory: decision.MemoryThreshold{MaxPercent: 80.0},
+			IO:     decision.IOThreshold{MaxUtilPercent: 80.0, Paths: map[string]float64{"/": 95.0}},
+		}).
+		WithPrediction(newTaskPrediction).
+		WithPendingTasks(pendingTasks)
+
+	result := s.Decide(ctx)
+
+	// /data: 25 + 35 + 30 = 90% > 80%; /: 10 + 35 + 30 = 75% < 95%
+	if result.Allowed {
+		t.Error("expected allowed=false for saturated /data device")
+	}
+	if !containsReason(result.Reasons, decision.ReasonIOSaturated) {
+		t.Error("expected ReasonIOSaturated in reasons")
+	}
+	if got := result.PredictedState.IOUtilPercent["/data"]; got != 90.0 {
+		t.Errorf("expected /data util 90%%, got %f%%", got)
+	}
+	if got := result.PredictedState.IOUtilPercent["/"]; got != 75.0 {
+		t.Errorf("expected / util 75%%, got %f%%", got)
+	}
+}
+
 func TestQueueAwareStrategy_Decide_UsesModelForPendingWithoutPrediction(t *testing.T) {
 	// Model returns this prediction for all tasks
 	prediction := &decision.ResourceImpact{
@@ -1,6 +1,8 @@
 package strategy
 
 import (
+	"slices"
+
 	"github.com/haskel/capfox/internal/decision"
 )
 
@@ -31,7 +33,10 @@ func (s *ThresholdStrategy) Decide(ctx *decision.Context) *decision.Result {
 		return result
 	}
 
-	reasons := s.checkThresholds(ctx)
+	gpus := ctx.Thresholds.PlaceGPUs(ctx.CurrentState.GPUs, 0, 0)
+	result.GPUs = gpus.Fitting
+
+	reasons := s.checkThresholds(ctx, gpus)
 	if len(reasons) > 0 {
 		result.Allowed = false
 		result.Reasons = reasons
@@ -41,7 +46,7 @@ func (s *ThresholdStrategy) Decide(ctx *decision.Context) *decision.Result {
 }
 
 // checkThresholds checks all resource thresholds and returns violations.
-func (s *ThresholdStrategy) checkThresholds(ctx *decision.Context) []decision.Reason {
+func (s *ThresholdStrategy) checkThresholds(ctx *decision.Context, gpus decision.GPUPlacement) []decision.Reason {
 	var reasons []decision.Reason
 
 	state := ctx.CurrentState
@@ -53,34 +58,50 @@ func (s *ThresholdStrategy) checkThresholds(ctx *decision.Context) []decision.Re
 	}
 
 	// Check Memory threshold
-	if state.Memory.UsagePercent > thresholds.Memory.MaxPercent {
+	if state.Memory.Percent(thresholds.Memory.Mode) > thresholds.Memory.MaxPercent {
 		reasons = append(reasons, decision.ReasonMemoryOverload)
 	}
 
-	// Check GPU thresholds
-	for _, gpu := range state.GPUs {
-		if gpu.UsagePercent > thresholds.GPU.MaxPercent {
-			reasons = append(reasons, decision.ReasonGPUOverload)
-			break
-		}
-
-		// Check VRAM
-		if gpu.VRAMTotalBytes > 0 {
-			vramPercent := float64(gpu.VRAMUsedBytes) / float64(gpu.VRAMTotalBytes) * 100
-			if vramPercent > thresholds.VRAM.MaxPercent {
-				reasons = append(reasons, decision.ReasonVRAMOverload)
-				break
-			}
-		}
+	// Check that at least one GPU is within its limits
+	reasons = append(reasons, gpus.Reasons()...)
+
+	// Check storage space, inodes and mount health
+	reasons = append(reasons, thresholds.Storage.Exceeded(state.Storage)...)
+
+	// Check swap thresholds
+	reasons = append(reasons, thresholds.Swap.Exceeded(state.Swap.UsagePercent, state.Swap.PagesPerSec())...)
+
+	// Check utilization of the devices backing monitored paths
+	if thresholds.IO.Exceeded(state.IO.PathUtil()) {
+		reasons = append(reasons, decision.ReasonIOSaturated)
+	}
+
+	// Check interface utilization against capacity
+	if thresholds.Network.Exceeded(state.Network.MaxPercent()) {
+		reasons = append(reasons, decision.ReasonNetworkSaturated)
 	}
 
-	// Check storage thresholds
-	for _, disk := range state.Storage {
-		freeGB := float64(disk.TotalBytes-disk.UsedBytes) / (1024 * 1024 * 1024)
-		if freeGB < thresholds.Storage.MinFreeGB {
-			reasons = append(reasons, decision.ReasonStorageLow)
-			break
-		}
+	// Check CPU temperatures and active throttling, hot GPUs are part of
+	// the GPU check
+	if thresholds.Thermal.Exceeded(state.Thermal) && !slices.Contains(reasons, decision.ReasonThermalThrottling) {
+		reasons = append(reasons, decision.ReasonThermalThrottling)
+	}
+
+	// Check that at least one NUMA node is within the per-node limits
+	if thresholds.NUMAExceeded(state) {
+		reasons = append(reasons, decision.ReasonNUMAOverload)
+	}
+
+	// Check pressure stall thresholds
+	pressure := thresholds.Pressure
+	if pressure.CPU.Exceeded(state.Pressure.CPU.Window(pressure.Window)) {
+		reasons = append(reasons, decision.ReasonCPUPressure)
+	}
+	if pressure.Memory.Exceeded(state.Pressure.Memory.Window(pressure.Window)) {
+		reasons = append(reasons, decision.ReasonMemoryPressure)
+	}
+	if pressure.IO.Exceeded(state.Pressure.IO.Window(pressure.Window)) {
+		reasons = append(reasons, decision.ReasonIOPressure)
 	}
 
 	return reasons
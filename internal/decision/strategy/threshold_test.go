@@ -205,6 +205,49 @@ func TestThresholdStrategy_Decide_RejectsStorageLow(t *testing.T) {
 	}
 }
 
+func TestThresholdStrategy_Decide_RejectsStorageUnhealthy(t *testing.T) {
+	s := NewThresholdStrategy()
+
+	ctx := decision.NewContext("test", 100).
+		WithCurrentState(&monitor.SystemState{
+			CPU:    monitor.CPUState{UsagePercent: 50.0},
+			Memory: monitor.MemoryState{UsagePercent: 40.0},
+			Storage: monitor.StorageState{
+				"/": {
+					UsedBytes:   50 * 1024 * 1024 * 1024,
+					TotalBytes:  200 * 1024 * 1024 * 1024,
+					InodesUsed:  990,
+					InodesTotal: 1000,
+					ReadOnly:    true,
+				},
+				"/data": {Error: "no such file or directory"},
+			},
+		}).
+		WithThresholds(&decision.ThresholdsConfig{
+			CPU:     decision.CPUThreshold{MaxPercent: 80.0},
+			Memory:  decision.MemoryThreshold{MaxPercent: 80.0},
+			Storage: decision.StorageThreshold{MinFreeGB: 10.0, MinFreeInodesPercent: 5.0},
+		})
+
+	result := s.Decide(ctx)
+
+	if result.Allowed {
+		t.Error("expected allowed=false for unhealthy storage")
+	}
+	for _, reason := range []decision.Reason{
+		decision.ReasonStorageInodesLow,
+		decision.ReasonStorageReadOnly,
+		decision.ReasonStorageUnavailable,
+	} {
+		if !containsReason(result.Reasons, reason) {
+			t.Errorf("expected %s in reasons, got %v", reason, result.Reasons)
+		}
+	}
+	if containsReason(result.Reasons, decision.ReasonStorageLow) {
+		t.Error("unavailable path should not be reported as low on space")
+	}
+}
+
 func TestThresholdStrategy_Decide_MultipleReasons(t *testing.T) {
 	s := NewThresholdStrategy()
 
@@ -235,6 +278,217 @@ func TestThresholdStrategy_Decide_MultipleReasons(t *testing.T) {
 	}
 }
 
+func TestThresholdStrategy_Decide_RejectsSwapThrashing(t *testing.T) {
+	s := NewThresholdStrategy()
+
+	ctx := decision.NewContext("test", 100).
+		WithCurrentState(&monitor.SystemState{
+			CPU:    monitor.CPUState{UsagePercent: 50.0},
+			Memory: monitor.MemoryState{UsagePercent: 40.0},
+			Swap:   monitor.SwapState{UsagePercent: 30.0, PagesInPerSec: 800, PagesOutPerSec: 400},
+		}).
+		WithThresholds(&decision.ThresholdsConfig{
+			CPU:    decision.CPUThreshold{MaxPercent: 80.0},
+			Memory: decision.MemoryThreshold{MaxPercent: 80.0},
+			Swap:   decision.SwapThreshold{MaxPercent: 50.0, MaxPagesPerSec: 1000},
+		})
+
+	result := s.Decide(ctx)
+
+	if result.Allowed {
+		t.Error("expected allowed=false for swap thrashing")
+	}
+	if len(result.Reasons) != 1 || !containsReason(result.Reasons, decision.ReasonSwapThrashing) {
+		t.Errorf("expected [swap_thrashing], got %v", result.Reasons)
+	}
+}
+
+func TestThresholdStrategy_Decide_RejectsIOSaturated(t *testing.T) {
+	s := NewThresholdStrategy()
+
+	ctx := decision.NewContext("test", 100).
+		WithCurrentState(&monitor.SystemState{
+			CPU:    monitor.CPUState{UsagePercent: 50.0},
+			Memory: monitor.MemoryState{UsagePercent: 40.0},
+			IO: monitor.IOState{
+				Devices: map[string]monitor.DeviceIOState{"nvme0n1": {UtilPercent: 92.0}},
+				Paths:   map[string]string{"/": "nvme0n1"},
+			},
+		}).
+		WithThresholds(&decision.ThresholdsConfig{
+			CPU:    decision.CPUThreshold{MaxPercent: 80.0},
+			Memory: decision.MemoryThreshold{MaxPercent: 80.0},
+			IO:     decision.IOThreshold{MaxUtilPercent: 90.0},
+		})
+
+	result := s.Decide(ctx)
+
+	if result.Allowed {
+		t.Error("expected allowed=false for saturated device")
+	}
+	if len(result.Reasons) != 1 || !containsReason(result.Reasons, decision.ReasonIOSaturated) {
+		t.Errorf("expected [io_saturated], got %v", result.Reasons)
+	}
+}
+
+func TestThresholdStrategy_Decide_RejectsNetworkSaturated(t *testing.T) {
+	s := NewThresholdStrategy()
+
+	ctx := decision.NewContext("test", 100).
+		WithCurrentState(&monitor.SystemState{
+			CPU:     monitor.CPUState{UsagePercent: 50.0},
+			Memory:  monitor.MemoryState{UsagePercent: 40.0},
+			Network: monitor.NetworkState{"eth0": {RxPercent: 20.0, TxPercent: 95.0}},
+		}).
+		WithThresholds(&decision.ThresholdsConfig{
+			CPU:     decision.CPUThreshold{MaxPercent: 80.0},
+			Memory:  decision.MemoryThreshold{MaxPercent: 80.0},
+			Network: decision.NetworkThreshold{MaxPercent: 90.0},
+		})
+
+	result := s.Decide(ctx)
+
+	if result.Allowed {
+		t.Error("expected allowed=false for saturated interface")
+	}
+	if len(result.Reasons) != 1 || !containsReason(result.Reasons, decision.ReasonNetworkSaturated) {
+		t.Errorf("expected [network_saturated], got %v", result.Reasons)
+	}
+}
+
+func TestThresholdStrategy_Decide_RejectsThermalThrottling(t *testing.T) {
+	s := NewThresholdStrategy()
+
+	tests := []struct {
+		name    string
+		thermal monitor.ThermalState
+		gpuTemp int
+	}{
+		{"cpu throttled", monitor.ThermalState{ThrottleEventsPerSec: 4}, 60},
+		{"gpu hot", monitor.ThermalState{Sensors: map[string]float64{"k10temp/Tctl": 70}}, 91},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := decision.NewContext("test", 100).
+				WithCurrentState(&monitor.SystemState{
+					CPU:     monitor.CPUState{UsagePercent: 50.0},
+					Memory:  monitor.MemoryState{UsagePercent: 40.0},
+					GPUs:    []monitor.GPUState{{Index: 0, UsagePercent: 20.0, Temperature: tt.gpuTemp}},
+					Thermal: tt.thermal,
+				}).
+				WithThresholds(&decision.ThresholdsConfig{
+					CPU:     decision.CPUThreshold{MaxPercent: 80.0},
+					Memory:  decision.MemoryThreshold{MaxPercent: 80.0},
+					GPU:     decision.GPUThreshold{MaxPercent: 90.0, MaxCelsius: 85.0},
+					Thermal: decision.ThermalThreshold{MaxCelsius: 90.0},
+				})
+
+			result := s.Decide(ctx)
+
+			if result.Allowed {
+				t.Error("expected allowed=false when running hot")
+			}
+			if len(result.Reasons) != 1 || !containsReason(result.Reasons, decision.ReasonThermalThrottling) {
+				t.Errorf("expected [thermal_throttling], got %v", result.Reasons)
+			}
+		})
+	}
+}
+
+func TestThresholdStrategy_Decide_RejectsNUMAOverload(t *testing.T) {
+	s := NewThresholdStrategy()
+
+	ctx := decision.NewContext("test", 100).
+		WithCurrentState(&monitor.SystemState{
+			CPU:    monitor.CPUState{UsagePercent: 50.0, Cores: []float64{95.0, 5.0}},
+			Memory: monitor.MemoryState{UsagePercent: 40.0},
+			NUMA: monitor.NUMAState{
+				Nodes: []monitor.NUMANodeState{
+					{ID: 0, CPUs: []int{0}, TotalBytes: 100, UsedBytes: 10},
+					{ID: 1, CPUs: []int{1}, TotalBytes: 100, UsedBytes: 90},
+				},
+			},
+		}).
+		WithThresholds(&decision.ThresholdsConfig{
+			CPU:    decision.CPUThreshold{MaxPercent: 80.0},
+			Memory: decision.MemoryThreshold{MaxPercent: 80.0},
+			NUMA:   decision.NUMAThreshold{MaxMemoryPercent: 85.0, MaxCPUPercent: 90.0},
+		})
+
+	result := s.Decide(ctx)
+
+	if result.Allowed {
+		t.Error("expected allowed=false when every node is over its limits")
+	}
+	if len(result.Reasons) != 1 || !containsReason(result.Reasons, decision.ReasonNUMAOverload) {
+		t.Errorf("expected [numa_overload], got %v", result.Reasons)
+	}
+}
+
+func TestThresholdStrategy_Decide_RejectsPressure(t *testing.T) {
+	s := NewThresholdStrategy()
+
+	ctx := decision.NewContext("test", 100).
+		WithCurrentState(&monitor.SystemState{
+			CPU:    monitor.CPUState{UsagePercent: 50.0},
+			Memory: monitor.MemoryState{UsagePercent: 40.0},
+			Pressure: monitor.PressureState{
+				CPU:    monitor.PressureResource{Some: monitor.PressureStats{Avg10: 10, Avg60: 35}},
+				Memory: monitor.PressureResource{Full: monitor.PressureStats{Avg10: 2, Avg60: 15}},
+				IO:     monitor.PressureResource{Some: monitor.PressureStats{Avg10: 80, Avg60: 45}},
+			},
+		}).
+		WithThresholds(&decision.ThresholdsConfig{
+			CPU:    decision.CPUThreshold{MaxPercent: 80.0},
+			Memory: decision.MemoryThreshold{MaxPercent: 80.0},
+			Pressure: decision.PressureThreshold{
+				Window: "avg60",
+				CPU:    decision.PressureLimit{MaxSome: 30},
+				Memory: decision.PressureLimit{MaxFull: 10},
+				IO:     decision.PressureLimit{MaxSome: 50},
+			},
+		})
+
+	result := s.Decide(ctx)
+
+	if result.Allowed {
+		t.Error("expected allowed=false under pressure")
+	}
+	if len(result.Reasons) != 2 {
+		t.Errorf("expected 2 reasons, got %v", result.Reasons)
+	}
+	if !containsReason(result.Reasons, decision.ReasonCPUPressure) {
+		t.Error("expected ReasonCPUPressure in reasons")
+	}
+	if !containsReason(result.Reasons, decision.ReasonMemoryPressure) {
+		t.Error("expected ReasonMemoryPressure in reasons")
+	}
+}
+
+func TestThresholdStrategy_Decide_PressureDisabled(t *testing.T) {
+	s := NewThresholdStrategy()
+
+	ctx := decision.NewContext("test", 100).
+		WithCurrentState(&monitor.SystemState{
+			CPU:    monitor.CPUState{UsagePercent: 50.0},
+			Memory: monitor.MemoryState{UsagePercent: 40.0},
+			Pressure: monitor.PressureState{
+				IO: monitor.PressureResource{Some: monitor.PressureStats{Avg10: 99}},
+			},
+		}).
+		WithThresholds(&decision.ThresholdsConfig{
+			CPU:    decision.CPUThreshold{MaxPercent: 80.0},
+			Memory: decision.MemoryThreshold{MaxPercent: 80.0},
+		})
+
+	result := s.Decide(ctx)
+
+	if !result.Allowed {
+		t.Errorf("expected allowed=true with pressure limits unset, got %v", result.Reasons)
+	}
+}
+
 // Helper function
 func containsReason(reasons []decision.Reason, target decision.Reason) bool {
 	for _, r := range reasons {
@@ -7,6 +7,10 @@ import (
 	"github.com/haskel/capfox/internal/decision/model"
 )
 
+// maxSeedObservations bounds the observations replayed per task by
+// LoadStats; confidence saturates well before it.
+const maxSeedObservations = 1000
+
 // ModelAdapter wraps decision/model.PredictionModel to implement the old learning.Model interface.
 // This allows gradual migration to the new model system.
 type ModelAdapter struct {
@@ -37,6 +41,17 @@ func (a *ModelAdapter) Observe(task string, complexity int, impact *ResourceImpa
 		MemoryDelta: impact.MemoryDelta,
 		GPUDelta:    impact.GPUDelta,
 		VRAMDelta:   impact.VRAMDelta,
+
+		SwapDelta:      impact.SwapDelta,
+		SwapPagesDelta: impact.SwapPagesDelta,
+
+		IOReadDelta:  impact.IOReadDelta,
+		IOWriteDelta: impact.IOWriteDelta,
+		IOUtilDelta:  impact.IOUtilDelta,
+
+		NetRxDelta:   impact.NetRxDelta,
+		NetTxDelta:   impact.NetTxDelta,
+		NetUtilDelta: impact.NetUtilDelta,
 	}
 
 	a.model.Observe(task, complexity, decisionImpact)
@@ -64,6 +79,17 @@ func (a *ModelAdapter) Predict(task string, complexity int) *ResourceImpact {
 		MemoryDelta: prediction.MemoryDelta,
 		GPUDelta:    prediction.GPUDelta,
 		VRAMDelta:   prediction.VRAMDelta,
+
+		SwapDelta:      prediction.SwapDelta,
+		SwapPagesDelta: prediction.SwapPagesDelta,
+
+		IOReadDelta:  prediction.IOReadDelta,
+		IOWriteDelta: prediction.IOWriteDelta,
+		IOUtilDelta:  prediction.IOUtilDelta,
+
+		NetRxDelta:   prediction.NetRxDelta,
+		NetTxDelta:   prediction.NetTxDelta,
+		NetUtilDelta: prediction.NetUtilDelta,
 	}
 }
 
@@ -85,6 +111,17 @@ func (a *ModelAdapter) GetStats() *AllStats {
 			AvgMemDelta:  ts.AvgMemDelta,
 			AvgGPUDelta:  ts.AvgGPUDelta,
 			AvgVRAMDelta: ts.AvgVRAMDelta,
+
+			AvgSwapDelta:      ts.AvgSwapDelta,
+			AvgSwapPagesDelta: ts.AvgSwapPagesDelta,
+
+			AvgIOReadDelta:  ts.AvgIOReadDelta,
+			AvgIOWriteDelta: ts.AvgIOWriteDelta,
+			AvgIOUtilDelta:  ts.AvgIOUtilDelta,
+
+			AvgNetRxDelta:   ts.AvgNetRxDelta,
+			AvgNetTxDelta:   ts.AvgNetTxDelta,
+			AvgNetUtilDelta: ts.AvgNetUtilDelta,
 		}
 		total += ts.Count
 	}
@@ -109,6 +146,17 @@ func (a *ModelAdapter) GetTaskStats(task string) *TaskStats {
 		AvgMemDelta:  ts.AvgMemDelta,
 		AvgGPUDelta:  ts.AvgGPUDelta,
 		AvgVRAMDelta: ts.AvgVRAMDelta,
+
+		AvgSwapDelta:      ts.AvgSwapDelta,
+		AvgSwapPagesDelta: ts.AvgSwapPagesDelta,
+
+		AvgIOReadDelta:  ts.AvgIOReadDelta,
+		AvgIOWriteDelta: ts.AvgIOWriteDelta,
+		AvgIOUtilDelta:  ts.AvgIOUtilDelta,
+
+		AvgNetRxDelta:   ts.AvgNetRxDelta,
+		AvgNetTxDelta:   ts.AvgNetTxDelta,
+		AvgNetUtilDelta: ts.AvgNetUtilDelta,
 	}
 }
 
@@ -119,11 +167,25 @@ func (a *ModelAdapter) SetObserver(observer StatsObserver) {
 	a.observerMu.Unlock()
 }
 
-// LoadStats loads previously saved statistics.
-// Note: This is a no-op for the new model system - use model.Load() instead.
+// LoadStats seeds the model with previously saved statistics, for when no
+// saved model state exists. Each task's averages are observed once per
+// recorded run at complexity 0, so the model predicts the averages with the
+// confidence of the recorded count. The observer is not notified.
 func (a *ModelAdapter) LoadStats(stats *AllStats) {
-	// The new model system uses Save/Load for persistence.
-	// This method is kept for backwards compatibility but does nothing.
+	if stats == nil {
+		return
+	}
+	for task, ts := range stats.Tasks {
+		impact := &decision.ResourceImpact{
+			CPUDelta:    ts.AvgCPUDelta,
+			MemoryDelta: ts.AvgMemDelta,
+			GPUDelta:    ts.AvgGPUDelta,
+			VRAMDelta:   ts.AvgVRAMDelta,
+		}
+		for range min(ts.Count, maxSeedObservations) {
+			a.model.Observe(task, 0, impact)
+		}
+	}
 }
 
 // Underlying returns the wrapped PredictionModel.
@@ -141,6 +203,17 @@ func ConvertImpact(impact *ResourceImpact) *decision.ResourceImpact {
 		MemoryDelta: impact.MemoryDelta,
 		GPUDelta:    impact.GPUDelta,
 		VRAMDelta:   impact.VRAMDelta,
+
+		SwapDelta:      impact.SwapDelta,
+		SwapPagesDelta: impact.SwapPagesDelta,
+
+		IOReadDelta:  impact.IOReadDelta,
+		IOWriteDelta: impact.IOWriteDelta,
+		IOUtilDelta:  impact.IOUtilDelta,
+
+		NetRxDelta:   impact.NetRxDelta,
+		NetTxDelta:   impact.NetTxDelta,
+		NetUtilDelta: impact.NetUtilDelta,
 	}
 }
 
@@ -154,5 +227,16 @@ func ConvertImpactBack(impact *decision.ResourceImpact) *ResourceImpact {
 		MemoryDelta: impact.MemoryDelta,
 		GPUDelta:    impact.GPUDelta,
 		VRAMDelta:   impact.VRAMDelta,
+
+		SwapDelta:      impact.SwapDelta,
+		SwapPagesDelta: impact.SwapPagesDelta,
+
+		IOReadDelta:  impact.IOReadDelta,
+		IOWriteDelta: impact.IOWriteDelta,
+		IOUtilDelta:  impact.IOUtilDelta,
+
+		NetRxDelta:   impact.NetRxDelta,
+		NetTxDelta:   impact.NetTxDelta,
+		NetUtilDelta: impact.NetUtilDelta,
 	}
 }
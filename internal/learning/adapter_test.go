@@ -0,0 +1,46 @@
+package learning
+
+import (
+	"testing"
+
+	"github.com/haskel/capfox/internal/decision/model"
+)
+
+func TestModelAdapter_LoadStats(t *testing.T) {
+	adapter := NewModelAdapter(model.NewLinearModel(5))
+
+	notified := false
+	adapter.SetObserver(func(task string, stats *TaskStats) {
+		notified = true
+	})
+
+	adapter.LoadStats(&AllStats{
+		Tasks: map[string]*TaskStats{
+			"encode": {Task: "encode", Count: 12, AvgCPUDelta: 20, AvgMemDelta: 5, AvgGPUDelta: 10},
+			"rare":   {Task: "rare", Count: 2, AvgCPUDelta: 50},
+		},
+		TotalTasks: 14,
+	})
+
+	if notified {
+		t.Error("expected seeding not to notify the observer")
+	}
+
+	stats := adapter.GetTaskStats("encode")
+	if stats == nil || stats.Count != 12 {
+		t.Fatalf("expected 12 seeded observations, got %+v", stats)
+	}
+
+	prediction := adapter.Predict("encode", 100)
+	if prediction == nil {
+		t.Fatal("expected a prediction from seeded stats")
+	}
+	if prediction.CPUDelta != 20 || prediction.MemoryDelta != 5 || prediction.GPUDelta != 10 {
+		t.Errorf("expected the saved averages, got %+v", prediction)
+	}
+
+	// Below min observations the model still has no prediction
+	if adapter.Predict("rare", 0) != nil {
+		t.Error("expected no prediction below min observations")
+	}
+}
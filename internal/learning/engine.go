@@ -2,8 +2,10 @@ package learning
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
+	"runtime"
 	"sync"
 	"time"
 
@@ -15,6 +17,10 @@ const (
 	DefaultMaxWorkers = 100
 )
 
+// ErrTaskNotPending is returned when usage is reported for a task that is
+// unknown or was already observed.
+var ErrTaskNotPending = errors.New("task is not awaiting observation")
+
 // Engine coordinates learning from task executions.
 type Engine struct {
 	model      Model
@@ -27,6 +33,8 @@ type Engine struct {
 	mu             sync.Mutex
 	pendingTasks   map[string]*pendingTask
 	taskCounter    int64
+	memoryMode     string // basis of MemoryDelta, see monitor.MemoryState.Percent
+	onDone         func(taskID string)
 
 	// Goroutine management
 	ctx        context.Context
@@ -65,19 +73,55 @@ func NewEngineWithWorkers(model Model, aggregator *monitor.Aggregator, observati
 		observationDelay: observationDelay,
 		maxWorkers:       maxWorkers,
 		pendingTasks:     make(map[string]*pendingTask),
+		memoryMode:       monitor.MemoryModeUsed,
 		ctx:              ctx,
 		cancel:           cancel,
 		workerSem:        make(chan struct{}, maxWorkers),
 	}
 }
 
+// SetMemoryMode sets the accounting basis of learned memory deltas
+// (monitor.MemoryModeUsed or monitor.MemoryModeAvailable). It should match
+// the memory threshold mode so predictions and limits share one scale.
+func (e *Engine) SetMemoryMode(mode string) {
+	e.mu.Lock()
+	e.memoryMode = mode
+	e.mu.Unlock()
+}
+
+// SetOnDone sets a callback for tasks leaving observation, observed or
+// reported, e.g. to drop them from the pending load of the decision engine.
+func (e *Engine) SetOnDone(onDone func(taskID string)) {
+	e.mu.Lock()
+	e.onDone = onDone
+	e.mu.Unlock()
+}
+
 // NotifyTaskStart records that a task has started.
 // It captures a baseline of system state and schedules an observation.
-func (e *Engine) NotifyTaskStart(task string, complexity int) {
+// Returns the ID of the observation.
+func (e *Engine) NotifyTaskStart(task string, complexity int) string {
+	return e.notifyTaskStart(task, complexity, e.observationDelay)
+}
+
+// NotifyAttributedTaskStart records a task whose client will report the
+// usage of its process tree through ReportTaskUsage after the observation
+// delay. The system delta observation is kept as a fallback for a report
+// that never arrives, one delay later.
+func (e *Engine) NotifyAttributedTaskStart(task string, complexity int) string {
+	return e.notifyTaskStart(task, complexity, 2*e.observationDelay)
+}
+
+// ObservationDelay returns how long after its start a task is observed.
+func (e *Engine) ObservationDelay() time.Duration {
+	return e.observationDelay
+}
+
+func (e *Engine) notifyTaskStart(task string, complexity int, delay time.Duration) string {
 	e.mu.Lock()
 	if e.stopped {
 		e.mu.Unlock()
-		return
+		return ""
 	}
 	e.taskCounter++
 	taskID := task + "_" + time.Now().Format("20060102150405") + "_" + formatCounter(e.taskCounter)
@@ -97,7 +141,7 @@ func (e *Engine) NotifyTaskStart(task string, complexity int) {
 	e.logger.Debug("task started, scheduling observation",
 		"task", task,
 		"task_id", taskID,
-		"delay", e.observationDelay,
+		"delay", delay,
 	)
 
 	// Schedule observation after delay with bounded concurrency
@@ -116,12 +160,66 @@ func (e *Engine) NotifyTaskStart(task string, complexity int) {
 
 		// Wait for observation delay or cancellation
 		select {
-		case <-time.After(e.observationDelay):
+		case <-time.After(delay):
 			e.observe(taskID)
 		case <-e.ctx.Done():
 			return
 		}
 	}()
+
+	return taskID
+}
+
+// ReportTaskUsage observes a pending task from the measured usage of its
+// own process tree. CPU, memory and I/O throughput come from the
+// measurement, resources it cannot see (GPU, swap, network, device
+// utilization) from system deltas as usual.
+func (e *Engine) ReportTaskUsage(taskID string, usage monitor.ProcessUsage) error {
+	pt, memoryMode, ok := e.takePending(taskID)
+	if !ok {
+		return ErrTaskNotPending
+	}
+
+	current := e.aggregator.GetState()
+	if current == nil || pt.baseline == nil {
+		e.logger.Warn("observation skipped: missing state",
+			"task_id", taskID,
+		)
+		return nil
+	}
+
+	impact := systemImpact(pt.baseline, current, memoryMode)
+	attributeImpact(impact, usage, current, memoryMode)
+
+	e.logger.Debug("task impact attributed",
+		"task", pt.task,
+		"task_id", taskID,
+		"cpu_delta", impact.CPUDelta,
+		"mem_delta", impact.MemoryDelta,
+		"io_read_delta", impact.IOReadDelta,
+		"io_write_delta", impact.IOWriteDelta,
+		"threads", usage.PeakThreads,
+	)
+
+	e.model.Observe(pt.task, pt.complexity, impact)
+	return nil
+}
+
+// takePending removes a task awaiting observation.
+func (e *Engine) takePending(taskID string) (*pendingTask, string, bool) {
+	e.mu.Lock()
+	pt, exists := e.pendingTasks[taskID]
+	delete(e.pendingTasks, taskID)
+	memoryMode, onDone := e.memoryMode, e.onDone
+	e.mu.Unlock()
+
+	if !exists {
+		return nil, "", false
+	}
+	if onDone != nil {
+		onDone(taskID)
+	}
+	return pt, memoryMode, true
 }
 
 // formatCounter formats a counter value as a zero-padded string.
@@ -131,14 +229,10 @@ func formatCounter(n int64) string {
 
 // observe captures the impact of a task after the observation delay.
 func (e *Engine) observe(taskID string) {
-	e.mu.Lock()
-	pt, exists := e.pendingTasks[taskID]
-	if !exists {
-		e.mu.Unlock()
+	pt, memoryMode, ok := e.takePending(taskID)
+	if !ok {
 		return
 	}
-	delete(e.pendingTasks, taskID)
-	e.mu.Unlock()
 
 	// Get current state
 	current := e.aggregator.GetState()
@@ -149,23 +243,60 @@ func (e *Engine) observe(taskID string) {
 		return
 	}
 
-	// Calculate impact (delta between baseline and current)
+	impact := systemImpact(pt.baseline, current, memoryMode)
+
+	e.logger.Debug("task impact observed",
+		"task", pt.task,
+		"task_id", taskID,
+		"cpu_delta", impact.CPUDelta,
+		"mem_delta", impact.MemoryDelta,
+		"gpu_delta", impact.GPUDelta,
+		"swap_delta", impact.SwapDelta,
+		"io_util_delta", impact.IOUtilDelta,
+		"net_util_delta", impact.NetUtilDelta,
+	)
+
+	// Feed observation to the model
+	e.model.Observe(pt.task, pt.complexity, impact)
+}
+
+// systemImpact calculates the impact of a task as the delta between the
+// system state at its start and now.
+func systemImpact(baseline, current *monitor.SystemState, memoryMode string) *ResourceImpact {
 	impact := &ResourceImpact{
-		CPUDelta:    current.CPU.UsagePercent - pt.baseline.CPU.UsagePercent,
-		MemoryDelta: current.Memory.UsagePercent - pt.baseline.Memory.UsagePercent,
+		CPUDelta:    current.CPU.UsagePercent - baseline.CPU.UsagePercent,
+		MemoryDelta: current.Memory.Percent(memoryMode) - baseline.Memory.Percent(memoryMode),
+
+		SwapDelta:      current.Swap.UsagePercent - baseline.Swap.UsagePercent,
+		SwapPagesDelta: current.Swap.PagesPerSec() - baseline.Swap.PagesPerSec(),
+
+		IOUtilDelta:  current.IO.MaxUtilPercent() - baseline.IO.MaxUtilPercent(),
+		NetUtilDelta: current.Network.MaxPercent() - baseline.Network.MaxPercent(),
 	}
 
+	// I/O throughput delta on the monitored devices
+	currentRead, currentWrite := current.IO.Throughput()
+	baselineRead, baselineWrite := baseline.IO.Throughput()
+	impact.IOReadDelta = currentRead - baselineRead
+	impact.IOWriteDelta = currentWrite - baselineWrite
+
+	// Network throughput delta over all monitored interfaces
+	currentRx, currentTx := current.Network.Throughput()
+	baselineRx, baselineTx := baseline.Network.Throughput()
+	impact.NetRxDelta = currentRx - baselineRx
+	impact.NetTxDelta = currentTx - baselineTx
+
 	// GPU delta (average across all GPUs)
-	if len(current.GPUs) > 0 && len(pt.baseline.GPUs) > 0 {
+	if len(current.GPUs) > 0 && len(baseline.GPUs) > 0 {
 		var gpuDelta, vramDelta float64
-		count := min(len(current.GPUs), len(pt.baseline.GPUs))
+		count := min(len(current.GPUs), len(baseline.GPUs))
 		for i := 0; i < count; i++ {
-			gpuDelta += current.GPUs[i].UsagePercent - pt.baseline.GPUs[i].UsagePercent
+			gpuDelta += current.GPUs[i].UsagePercent - baseline.GPUs[i].UsagePercent
 
 			// VRAM delta (as percentage)
-			if current.GPUs[i].VRAMTotalBytes > 0 && pt.baseline.GPUs[i].VRAMTotalBytes > 0 {
+			if current.GPUs[i].VRAMTotalBytes > 0 && baseline.GPUs[i].VRAMTotalBytes > 0 {
 				currentVRAMPct := float64(current.GPUs[i].VRAMUsedBytes) / float64(current.GPUs[i].VRAMTotalBytes) * 100
-				baselineVRAMPct := float64(pt.baseline.GPUs[i].VRAMUsedBytes) / float64(pt.baseline.GPUs[i].VRAMTotalBytes) * 100
+				baselineVRAMPct := float64(baseline.GPUs[i].VRAMUsedBytes) / float64(baseline.GPUs[i].VRAMTotalBytes) * 100
 				vramDelta += currentVRAMPct - baselineVRAMPct
 			}
 		}
@@ -173,16 +304,39 @@ func (e *Engine) observe(taskID string) {
 		impact.VRAMDelta = vramDelta / float64(count)
 	}
 
-	e.logger.Debug("task impact observed",
-		"task", pt.task,
-		"task_id", taskID,
-		"cpu_delta", impact.CPUDelta,
-		"mem_delta", impact.MemoryDelta,
-		"gpu_delta", impact.GPUDelta,
-	)
+	return impact
+}
 
-	// Feed observation to the model
-	e.model.Observe(pt.task, pt.complexity, impact)
+// attributeImpact replaces the CPU, memory and I/O throughput deltas with
+// the measured usage of the task, scaled like the system metrics: CPU as a
+// percent of all cores (or the cgroup quota), memory as a percent of total
+// in the given memory mode.
+func attributeImpact(impact *ResourceImpact, usage monitor.ProcessUsage, state *monitor.SystemState, memoryMode string) {
+	if usage.WallSeconds <= 0 {
+		return
+	}
+
+	cores := state.CPU.LimitCores
+	if cores <= 0 {
+		cores = float64(len(state.CPU.Cores))
+	}
+	if cores <= 0 {
+		cores = float64(runtime.NumCPU())
+	}
+	impact.CPUDelta = usage.CPUSeconds / usage.WallSeconds / cores * 100
+
+	if state.Memory.TotalBytes > 0 {
+		rss := float64(usage.PeakRSSBytes) / float64(state.Memory.TotalBytes) * 100
+		// Page cache the task keeps from being reclaimed is not in its RSS
+		// but is used memory on the available basis, where only the system
+		// delta sees it
+		if memoryMode != monitor.MemoryModeAvailable || rss > impact.MemoryDelta {
+			impact.MemoryDelta = rss
+		}
+	}
+
+	impact.IOReadDelta = float64(usage.ReadBytes) / usage.WallSeconds
+	impact.IOWriteDelta = float64(usage.WriteBytes) / usage.WallSeconds
 }
 
 // Predict returns predicted resource impact for a task.
@@ -4,6 +4,7 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -16,14 +17,18 @@ func testLogger() *slog.Logger {
 
 type mockMonitor struct {
 	name string
-	data any
+	data monitor.Sample
 }
 
 func (m *mockMonitor) Name() string {
 	return m.name
 }
 
-func (m *mockMonitor) Collect() (any, error) {
+func (m *mockMonitor) Section() string {
+	return m.name
+}
+
+func (m *mockMonitor) Collect() (monitor.Sample, error) {
 	return m.data, nil
 }
 
@@ -89,6 +94,205 @@ func TestEngine_Observation(t *testing.T) {
 	}
 }
 
+func TestEngine_OnDone(t *testing.T) {
+	agg := testAggregator(50, 50)
+	defer func() { _ = agg.Stop() }()
+
+	engine := NewEngine(NewMovingAverageModel(0.2), agg, 50*time.Millisecond, testLogger())
+
+	done := make(chan string, 2)
+	engine.SetOnDone(func(taskID string) { done <- taskID })
+
+	observed := engine.NotifyTaskStart("test_task", 100)
+	reported := engine.NotifyAttributedTaskStart("test_task", 100)
+	if err := engine.ReportTaskUsage(reported, monitor.ProcessUsage{WallSeconds: 1}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, want := range []string{reported, observed} {
+		select {
+		case got := <-done:
+			if got != want {
+				t.Errorf("expected done %s, got %s", want, got)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("expected done %s", want)
+		}
+	}
+}
+
+// switchingMonitor returns before until switched, then after.
+type switchingMonitor struct {
+	name     string
+	before   monitor.Sample
+	after    monitor.Sample
+	switched atomic.Bool
+}
+
+func (m *switchingMonitor) Name() string {
+	return m.name
+}
+
+func (m *switchingMonitor) Section() string {
+	return m.name
+}
+
+func (m *switchingMonitor) Collect() (monitor.Sample, error) {
+	if m.switched.Load() {
+		return m.after, nil
+	}
+	return m.before, nil
+}
+
+func TestEngine_ObservesSwapDelta(t *testing.T) {
+	swap := &switchingMonitor{
+		name:   "swap",
+		before: &monitor.SwapState{UsagePercent: 10, PagesInPerSec: 50},
+		after:  &monitor.SwapState{UsagePercent: 25, PagesInPerSec: 350, PagesOutPerSec: 100},
+	}
+
+	agg := monitor.NewAggregator([]monitor.Monitor{swap}, 10*time.Millisecond, testLogger())
+	_ = agg.Start(context.Background())
+	defer func() { _ = agg.Stop() }()
+
+	model := NewMovingAverageModel(0.2)
+	engine := NewEngine(model, agg, 60*time.Millisecond, testLogger())
+	defer engine.Stop()
+
+	engine.NotifyTaskStart("swappy", 0)
+	swap.switched.Store(true)
+
+	time.Sleep(150 * time.Millisecond)
+
+	stats := engine.GetTaskStats("swappy")
+	if stats == nil {
+		t.Fatal("expected stats after observation")
+	}
+	if stats.AvgSwapDelta != 15 {
+		t.Errorf("expected swap delta 15, got %f", stats.AvgSwapDelta)
+	}
+	if stats.AvgSwapPagesDelta != 400 {
+		t.Errorf("expected swap pages delta 400, got %f", stats.AvgSwapPagesDelta)
+	}
+}
+
+func TestEngine_MemoryModeAvailable(t *testing.T) {
+	// The task fills page cache: used barely moves, MemAvailable drops
+	memory := &switchingMonitor{
+		name:   "memory",
+		before: &monitor.MemoryState{TotalBytes: 1000, UsagePercent: 20, AvailableBytes: 700, AvailableKnown: true},
+		after:  &monitor.MemoryState{TotalBytes: 1000, UsagePercent: 22, AvailableBytes: 400, AvailableKnown: true},
+	}
+
+	agg := monitor.NewAggregator([]monitor.Monitor{memory}, 10*time.Millisecond, testLogger())
+	_ = agg.Start(context.Background())
+	defer func() { _ = agg.Stop() }()
+
+	model := NewMovingAverageModel(0.2)
+	engine := NewEngine(model, agg, 60*time.Millisecond, testLogger())
+	engine.SetMemoryMode(monitor.MemoryModeAvailable)
+	defer engine.Stop()
+
+	engine.NotifyTaskStart("index", 0)
+	memory.switched.Store(true)
+
+	time.Sleep(150 * time.Millisecond)
+
+	stats := engine.GetTaskStats("index")
+	if stats == nil {
+		t.Fatal("expected stats after observation")
+	}
+	if stats.AvgMemDelta != 30 {
+		t.Errorf("expected memory delta 30 on the available basis, got %f", stats.AvgMemDelta)
+	}
+}
+
+func TestEngine_ObservesIODelta(t *testing.T) {
+	paths := map[string]string{"/": "sda1", "/data": "sdb1"}
+	io := &switchingMonitor{
+		name: "io",
+		before: &monitor.IOState{
+			Devices: map[string]monitor.DeviceIOState{
+				"sda1": {ReadBytesPerSec: 1000, UtilPercent: 5},
+				"sdb1": {UtilPercent: 10},
+			},
+			Paths: paths,
+		},
+		after: &monitor.IOState{
+			Devices: map[string]monitor.DeviceIOState{
+				"sda1": {ReadBytesPerSec: 1000, UtilPercent: 5},
+				"sdb1": {ReadBytesPerSec: 5000, WriteBytesPerSec: 8000, UtilPercent: 70},
+			},
+			Paths: paths,
+		},
+	}
+
+	agg := monitor.NewAggregator([]monitor.Monitor{io}, 10*time.Millisecond, testLogger())
+	_ = agg.Start(context.Background())
+	defer func() { _ = agg.Stop() }()
+
+	model := NewMovingAverageModel(0.2)
+	engine := NewEngine(model, agg, 60*time.Millisecond, testLogger())
+	defer engine.Stop()
+
+	engine.NotifyTaskStart("encode", 0)
+	io.switched.Store(true)
+
+	time.Sleep(150 * time.Millisecond)
+
+	stats := engine.GetTaskStats("encode")
+	if stats == nil {
+		t.Fatal("expected stats after observation")
+	}
+	if stats.AvgIOReadDelta != 5000 {
+		t.Errorf("expected read delta 5000, got %f", stats.AvgIOReadDelta)
+	}
+	if stats.AvgIOWriteDelta != 8000 {
+		t.Errorf("expected write delta 8000, got %f", stats.AvgIOWriteDelta)
+	}
+	if stats.AvgIOUtilDelta != 60 {
+		t.Errorf("expected util delta 60, got %f", stats.AvgIOUtilDelta)
+	}
+}
+
+func TestEngine_ObservesNetworkDelta(t *testing.T) {
+	network := &switchingMonitor{
+		name:   "network",
+		before: monitor.NetworkState{"eth0": {RxBytesPerSec: 1000, RxPercent: 5}},
+		after: monitor.NetworkState{
+			"eth0": {RxBytesPerSec: 1000, TxBytesPerSec: 9000, RxPercent: 5, TxPercent: 45},
+			"eth1": {RxBytesPerSec: 4000, RxPercent: 20},
+		},
+	}
+
+	agg := monitor.NewAggregator([]monitor.Monitor{network}, 10*time.Millisecond, testLogger())
+	_ = agg.Start(context.Background())
+	defer func() { _ = agg.Stop() }()
+
+	model := NewMovingAverageModel(0.2)
+	engine := NewEngine(model, agg, 60*time.Millisecond, testLogger())
+	defer engine.Stop()
+
+	engine.NotifyTaskStart("sync", 0)
+	network.switched.Store(true)
+
+	time.Sleep(150 * time.Millisecond)
+
+	stats := engine.GetTaskStats("sync")
+	if stats == nil {
+		t.Fatal("expected stats after observation")
+	}
+	if stats.AvgNetRxDelta != 4000 {
+		t.Errorf("expected rx delta 4000, got %f", stats.AvgNetRxDelta)
+	}
+	if stats.AvgNetTxDelta != 9000 {
+		t.Errorf("expected tx delta 9000, got %f", stats.AvgNetTxDelta)
+	}
+	if stats.AvgNetUtilDelta != 40 {
+		t.Errorf("expected util delta 40, got %f", stats.AvgNetUtilDelta)
+	}
+}
+
 func TestEngine_GetStats(t *testing.T) {
 	agg := testAggregator(50, 50)
 	defer func() { _ = agg.Stop() }()
@@ -273,3 +477,133 @@ func TestEngine_TaskIDFormat(t *testing.T) {
 		t.Errorf("expected taskCounter=5, got %d", counter)
 	}
 }
+
+func TestEngine_ReportTaskUsage(t *testing.T) {
+	// Another workload starts alongside the task and doubles system CPU
+	cpu := &switchingMonitor{
+		name:   "cpu",
+		before: &monitor.CPUState{UsagePercent: 20, Cores: []float64{20, 20, 20, 20}},
+		after:  &monitor.CPUState{UsagePercent: 90, Cores: []float64{90, 90, 90, 90}},
+	}
+	memory := &mockMonitor{
+		name: "memory",
+		data: &monitor.MemoryState{UsagePercent: 50, UsedBytes: 4 << 30, TotalBytes: 8 << 30},
+	}
+
+	agg := monitor.NewAggregator([]monitor.Monitor{cpu, memory}, 10*time.Millisecond, testLogger())
+	_ = agg.Start(context.Background())
+	defer func() { _ = agg.Stop() }()
+
+	model := NewMovingAverageModel(0.2)
+	engine := NewEngine(model, agg, time.Hour, testLogger())
+	defer engine.Stop()
+
+	taskID := engine.NotifyAttributedTaskStart("render", 0)
+	cpu.switched.Store(true)
+	time.Sleep(30 * time.Millisecond)
+
+	err := engine.ReportTaskUsage(taskID, monitor.ProcessUsage{
+		WallSeconds:  10,
+		CPUSeconds:   20, // two of four cores
+		PeakRSSBytes: 1 << 30,
+		ReadBytes:    5000,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stats := engine.GetTaskStats("render")
+	if stats == nil {
+		t.Fatal("expected stats after report")
+	}
+	if stats.AvgCPUDelta != 50 {
+		t.Errorf("expected attributed cpu delta 50, got %f", stats.AvgCPUDelta)
+	}
+	if stats.AvgMemDelta != 12.5 {
+		t.Errorf("expected attributed memory delta 12.5, got %f", stats.AvgMemDelta)
+	}
+	if stats.AvgIOReadDelta != 500 {
+		t.Errorf("expected io read delta 500, got %f", stats.AvgIOReadDelta)
+	}
+
+	if engine.PendingCount() != 0 {
+		t.Errorf("expected report to complete the observation, got %d pending", engine.PendingCount())
+	}
+
+	if err := engine.ReportTaskUsage(taskID, monitor.ProcessUsage{WallSeconds: 1}); err != ErrTaskNotPending {
+		t.Errorf("expected ErrTaskNotPending for a second report, got %v", err)
+	}
+}
+
+func TestEngine_ReportTaskUsage_MemoryModeAvailable(t *testing.T) {
+	// The task fills page cache: MemAvailable drops by 30%
+	memory := &switchingMonitor{
+		name:   "memory",
+		before: &monitor.MemoryState{TotalBytes: 1000, UsagePercent: 20, AvailableBytes: 700, AvailableKnown: true},
+		after:  &monitor.MemoryState{TotalBytes: 1000, UsagePercent: 22, AvailableBytes: 400, AvailableKnown: true},
+	}
+
+	agg := monitor.NewAggregator([]monitor.Monitor{memory}, 10*time.Millisecond, testLogger())
+	_ = agg.Start(context.Background())
+	defer func() { _ = agg.Stop() }()
+
+	tests := []struct {
+		name string
+		mode string
+		rss  uint64
+		want float64
+	}{
+		{"used mode", monitor.MemoryModeUsed, 100, 10},
+		{"page cache over rss", monitor.MemoryModeAvailable, 100, 30},
+		{"rss over page cache", monitor.MemoryModeAvailable, 500, 50},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memory.switched.Store(false)
+			time.Sleep(30 * time.Millisecond)
+
+			model := NewMovingAverageModel(0.2)
+			engine := NewEngine(model, agg, time.Hour, testLogger())
+			engine.SetMemoryMode(tt.mode)
+			defer engine.Stop()
+
+			taskID := engine.NotifyAttributedTaskStart("index", 0)
+			memory.switched.Store(true)
+			time.Sleep(30 * time.Millisecond)
+
+			if err := engine.ReportTaskUsage(taskID, monitor.ProcessUsage{WallSeconds: 1, PeakRSSBytes: tt.rss}); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if stats := engine.GetTaskStats("index"); stats == nil || stats.AvgMemDelta != tt.want {
+				t.Errorf("expected memory delta %v, got %+v", tt.want, stats)
+			}
+		})
+	}
+}
+
+func TestEngine_AttributedFallback(t *testing.T) {
+	agg := testAggregator(50, 50)
+	defer func() { _ = agg.Stop() }()
+
+	model := NewMovingAverageModel(0.2)
+	engine := NewEngine(model, agg, 40*time.Millisecond, testLogger())
+	defer engine.Stop()
+
+	engine.NotifyAttributedTaskStart("render", 0)
+
+	// Still waiting for the report after one delay
+	time.Sleep(60 * time.Millisecond)
+	if engine.PendingCount() != 1 {
+		t.Fatalf("expected task to wait for its report, got %d pending", engine.PendingCount())
+	}
+
+	// Observed from system deltas after two
+	time.Sleep(60 * time.Millisecond)
+	if engine.PendingCount() != 0 {
+		t.Errorf("expected fallback observation, got %d pending", engine.PendingCount())
+	}
+	if stats := engine.GetTaskStats("render"); stats == nil || stats.Count != 1 {
+		t.Errorf("expected one observation, got %+v", stats)
+	}
+}
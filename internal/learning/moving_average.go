@@ -17,6 +17,17 @@ type taskState struct {
 	avgMemDelta  float64
 	avgGPUDelta  float64
 	avgVRAMDelta float64
+
+	avgSwapDelta      float64
+	avgSwapPagesDelta float64
+
+	avgIOReadDelta  float64
+	avgIOWriteDelta float64
+	avgIOUtilDelta  float64
+
+	avgNetRxDelta   float64
+	avgNetTxDelta   float64
+	avgNetUtilDelta float64
 }
 
 // NewMovingAverageModel creates a new MovingAverageModel.
@@ -52,6 +63,17 @@ func (m *MovingAverageModel) Observe(task string, complexity int, impact *Resour
 			avgMemDelta:  impact.MemoryDelta,
 			avgGPUDelta:  impact.GPUDelta,
 			avgVRAMDelta: impact.VRAMDelta,
+
+			avgSwapDelta:      impact.SwapDelta,
+			avgSwapPagesDelta: impact.SwapPagesDelta,
+
+			avgIOReadDelta:  impact.IOReadDelta,
+			avgIOWriteDelta: impact.IOWriteDelta,
+			avgIOUtilDelta:  impact.IOUtilDelta,
+
+			avgNetRxDelta:   impact.NetRxDelta,
+			avgNetTxDelta:   impact.NetTxDelta,
+			avgNetUtilDelta: impact.NetUtilDelta,
 		}
 		state = m.stats[task]
 	} else {
@@ -61,6 +83,15 @@ func (m *MovingAverageModel) Observe(task string, complexity int, impact *Resour
 		state.avgMemDelta = m.alpha*impact.MemoryDelta + (1-m.alpha)*state.avgMemDelta
 		state.avgGPUDelta = m.alpha*impact.GPUDelta + (1-m.alpha)*state.avgGPUDelta
 		state.avgVRAMDelta = m.alpha*impact.VRAMDelta + (1-m.alpha)*state.avgVRAMDelta
+		state.avgSwapDelta = m.alpha*impact.SwapDelta + (1-m.alpha)*state.avgSwapDelta
+		state.avgSwapPagesDelta = m.alpha*impact.SwapPagesDelta + (1-m.alpha)*state.avgSwapPagesDelta
+		state.avgIOReadDelta = m.alpha*impact.IOReadDelta + (1-m.alpha)*state.avgIOReadDelta
+		state.avgIOWriteDelta = m.alpha*impact.IOWriteDelta + (1-m.alpha)*state.avgIOWriteDelta
+		state.avgIOUtilDelta = m.alpha*impact.IOUtilDelta + (1-m.alpha)*state.avgIOUtilDelta
+
+		state.avgNetRxDelta = m.alpha*impact.NetRxDelta + (1-m.alpha)*state.avgNetRxDelta
+		state.avgNetTxDelta = m.alpha*impact.NetTxDelta + (1-m.alpha)*state.avgNetTxDelta
+		state.avgNetUtilDelta = m.alpha*impact.NetUtilDelta + (1-m.alpha)*state.avgNetUtilDelta
 	}
 
 	// Get stats before unlocking
@@ -71,6 +102,17 @@ func (m *MovingAverageModel) Observe(task string, complexity int, impact *Resour
 		AvgMemDelta:  state.avgMemDelta,
 		AvgGPUDelta:  state.avgGPUDelta,
 		AvgVRAMDelta: state.avgVRAMDelta,
+
+		AvgSwapDelta:      state.avgSwapDelta,
+		AvgSwapPagesDelta: state.avgSwapPagesDelta,
+
+		AvgIOReadDelta:  state.avgIOReadDelta,
+		AvgIOWriteDelta: state.avgIOWriteDelta,
+		AvgIOUtilDelta:  state.avgIOUtilDelta,
+
+		AvgNetRxDelta:   state.avgNetRxDelta,
+		AvgNetTxDelta:   state.avgNetTxDelta,
+		AvgNetUtilDelta: state.avgNetUtilDelta,
 	}
 	observer := m.observer
 
@@ -99,6 +141,17 @@ func (m *MovingAverageModel) Predict(task string, complexity int) *ResourceImpac
 		MemoryDelta: state.avgMemDelta,
 		GPUDelta:    state.avgGPUDelta,
 		VRAMDelta:   state.avgVRAMDelta,
+
+		SwapDelta:      state.avgSwapDelta,
+		SwapPagesDelta: state.avgSwapPagesDelta,
+
+		IOReadDelta:  state.avgIOReadDelta,
+		IOWriteDelta: state.avgIOWriteDelta,
+		IOUtilDelta:  state.avgIOUtilDelta,
+
+		NetRxDelta:   state.avgNetRxDelta,
+		NetTxDelta:   state.avgNetTxDelta,
+		NetUtilDelta: state.avgNetUtilDelta,
 	}
 }
 
@@ -119,6 +172,17 @@ func (m *MovingAverageModel) GetStats() *AllStats {
 			AvgMemDelta:  state.avgMemDelta,
 			AvgGPUDelta:  state.avgGPUDelta,
 			AvgVRAMDelta: state.avgVRAMDelta,
+
+			AvgSwapDelta:      state.avgSwapDelta,
+			AvgSwapPagesDelta: state.avgSwapPagesDelta,
+
+			AvgIOReadDelta:  state.avgIOReadDelta,
+			AvgIOWriteDelta: state.avgIOWriteDelta,
+			AvgIOUtilDelta:  state.avgIOUtilDelta,
+
+			AvgNetRxDelta:   state.avgNetRxDelta,
+			AvgNetTxDelta:   state.avgNetTxDelta,
+			AvgNetUtilDelta: state.avgNetUtilDelta,
 		}
 		result.TotalTasks += state.count
 	}
@@ -142,6 +206,17 @@ func (m *MovingAverageModel) GetTaskStats(task string) *TaskStats {
 		AvgMemDelta:  state.avgMemDelta,
 		AvgGPUDelta:  state.avgGPUDelta,
 		AvgVRAMDelta: state.avgVRAMDelta,
+
+		AvgSwapDelta:      state.avgSwapDelta,
+		AvgSwapPagesDelta: state.avgSwapPagesDelta,
+
+		AvgIOReadDelta:  state.avgIOReadDelta,
+		AvgIOWriteDelta: state.avgIOWriteDelta,
+		AvgIOUtilDelta:  state.avgIOUtilDelta,
+
+		AvgNetRxDelta:   state.avgNetRxDelta,
+		AvgNetTxDelta:   state.avgNetTxDelta,
+		AvgNetUtilDelta: state.avgNetUtilDelta,
 	}
 }
 
@@ -166,6 +241,17 @@ func (m *MovingAverageModel) LoadStats(stats *AllStats) {
 			avgMemDelta:  ts.AvgMemDelta,
 			avgGPUDelta:  ts.AvgGPUDelta,
 			avgVRAMDelta: ts.AvgVRAMDelta,
+
+			avgSwapDelta:      ts.AvgSwapDelta,
+			avgSwapPagesDelta: ts.AvgSwapPagesDelta,
+
+			avgIOReadDelta:  ts.AvgIOReadDelta,
+			avgIOWriteDelta: ts.AvgIOWriteDelta,
+			avgIOUtilDelta:  ts.AvgIOUtilDelta,
+
+			avgNetRxDelta:   ts.AvgNetRxDelta,
+			avgNetTxDelta:   ts.AvgNetTxDelta,
+			avgNetUtilDelta: ts.AvgNetUtilDelta,
 		}
 	}
 }
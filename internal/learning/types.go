@@ -1,6 +1,10 @@
 package learning
 
-import "time"
+import (
+	"time"
+
+	"github.com/haskel/capfox/internal/monitor"
+)
 
 // TaskRecord represents a single task execution record.
 type TaskRecord struct {
@@ -16,6 +20,21 @@ type ResourceImpact struct {
 	MemoryDelta float64 `json:"memory_delta"`
 	GPUDelta    float64 `json:"gpu_delta,omitempty"`
 	VRAMDelta   float64 `json:"vram_delta,omitempty"`
+	// SwapDelta is the change in swap usage percent
+	SwapDelta float64 `json:"swap_delta,omitempty"`
+	// SwapPagesDelta is the change in swap-in + swap-out pages per second
+	SwapPagesDelta float64 `json:"swap_pages_delta,omitempty"`
+	// IOReadDelta and IOWriteDelta are the change in bytes/s on the monitored devices
+	IOReadDelta  float64 `json:"io_read_delta,omitempty"`
+	IOWriteDelta float64 `json:"io_write_delta,omitempty"`
+	// IOUtilDelta is the change in utilization percent of the busiest monitored device
+	IOUtilDelta float64 `json:"io_util_delta,omitempty"`
+
+	// NetRxDelta and NetTxDelta are the change in bytes/s over all interfaces
+	NetRxDelta float64 `json:"net_rx_delta,omitempty"`
+	NetTxDelta float64 `json:"net_tx_delta,omitempty"`
+	// NetUtilDelta is the change in usage percent of the busiest interface
+	NetUtilDelta float64 `json:"net_util_delta,omitempty"`
 }
 
 // TaskStats holds aggregated statistics for a specific task type.
@@ -26,6 +45,17 @@ type TaskStats struct {
 	AvgMemDelta  float64 `json:"avg_mem_delta"`
 	AvgGPUDelta  float64 `json:"avg_gpu_delta,omitempty"`
 	AvgVRAMDelta float64 `json:"avg_vram_delta,omitempty"`
+
+	AvgSwapDelta      float64 `json:"avg_swap_delta,omitempty"`
+	AvgSwapPagesDelta float64 `json:"avg_swap_pages_delta,omitempty"`
+
+	AvgIOReadDelta  float64 `json:"avg_io_read_delta,omitempty"`
+	AvgIOWriteDelta float64 `json:"avg_io_write_delta,omitempty"`
+	AvgIOUtilDelta  float64 `json:"avg_io_util_delta,omitempty"`
+
+	AvgNetRxDelta   float64 `json:"avg_net_rx_delta,omitempty"`
+	AvgNetTxDelta   float64 `json:"avg_net_tx_delta,omitempty"`
+	AvgNetUtilDelta float64 `json:"avg_net_util_delta,omitempty"`
 }
 
 // AllStats holds statistics for all task types.
@@ -38,10 +68,30 @@ type AllStats struct {
 type TaskStartRequest struct {
 	Task       string `json:"task"`
 	Complexity int    `json:"complexity,omitempty"`
+	// Attributed announces a POST /task/usage report for this task, so the
+	// observation waits for it before falling back to system deltas
+	Attributed bool `json:"attributed,omitempty"`
 }
 
 // TaskStartResponse represents the response for POST /task/notify.
 type TaskStartResponse struct {
 	Received bool   `json:"received"`
 	Task     string `json:"task"`
+	// TaskID identifies the observation in POST /task/usage
+	TaskID string `json:"task_id,omitempty"`
+	// ObserveAfterMS is when usage should be reported, after the task start
+	ObserveAfterMS int64 `json:"observe_after_ms,omitempty"`
+}
+
+// TaskUsageRequest represents the request body for POST /task/usage: the
+// usage of a task's own process tree, measured by the client that ran it.
+type TaskUsageRequest struct {
+	TaskID string `json:"task_id"`
+	monitor.ProcessUsage
+}
+
+// TaskUsageResponse represents the response for POST /task/usage.
+type TaskUsageResponse struct {
+	Received bool   `json:"received"`
+	TaskID   string `json:"task_id"`
 }
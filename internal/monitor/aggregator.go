@@ -20,18 +20,119 @@ type Aggregator struct {
 
 	ready     bool      // true after first successful collection
 	readyTime time.Time // when first collection completed
+
+	history *History // nil if history is disabled
+
+	// smoothed is the state used for decisions, guarded by mu
+	smoothed *SystemState
+	// smoothing and series are only used by collect and SetSmoothing
+	smoothMu  sync.Mutex
+	smoothing map[string]Smoothing // by resource
+	series    map[string]*smoother // by series key, e.g. "io/sda"
+
+	// timeout bounds each Collect call, timeouts overrides it per monitor;
+	// guarded by mu
+	timeout  time.Duration
+	timeouts map[string]time.Duration
+	// inflight, lastGood and health are only used by collect
+	inflight map[string]chan collectResult // collections that missed their deadline
+	lastGood map[string]Sample
+	health   map[string]MonitorHealth
+
+	// pushed holds metrics reported through the push API; guarded by mu
+	pushed map[string]PushedMetric
+
+	// thresholds are contributed by monitors implementing ThresholdProvider
+	thresholds []Threshold
+
+	// observer is called with every collected snapshot; guarded by mu
+	observer SnapshotObserver
 }
 
+// SnapshotObserver is called with each collected snapshot. The state must
+// not be modified.
+type SnapshotObserver func(state *SystemState)
+
 func NewAggregator(monitors []Monitor, interval time.Duration, logger *slog.Logger) *Aggregator {
+	return NewAggregatorWithHistory(monitors, interval, logger, nil)
+}
+
+// NewAggregatorWithHistory creates an aggregator that records every
+// collected snapshot into the given history.
+func NewAggregatorWithHistory(monitors []Monitor, interval time.Duration, logger *slog.Logger, history *History) *Aggregator {
+	var thresholds []Threshold
+	for _, m := range monitors {
+		if p, ok := m.(ThresholdProvider); ok {
+			for _, t := range p.Thresholds() {
+				if t.Exceeded != nil {
+					thresholds = append(thresholds, t)
+				}
+			}
+		}
+	}
+
 	return &Aggregator{
-		monitors: monitors,
-		state:    &SystemState{},
-		interval: interval,
-		done:     make(chan struct{}),
-		logger:   logger,
+		monitors:   monitors,
+		state:      &SystemState{},
+		smoothed:   &SystemState{},
+		interval:   interval,
+		done:       make(chan struct{}),
+		logger:     logger,
+		history:    history,
+		series:     make(map[string]*smoother),
+		timeout:    DefaultCollectTimeout,
+		inflight:   make(map[string]chan collectResult),
+		lastGood:   make(map[string]Sample),
+		health:     make(map[string]MonitorHealth),
+		pushed:     make(map[string]PushedMetric),
+		thresholds: thresholds,
 	}
 }
 
+// Check returns the reasons of the monitor-contributed thresholds the state
+// exceeds under limits, in monitor order.
+func (a *Aggregator) Check(state *SystemState, limits Limits) []string {
+	return checkThresholds(a.thresholds, state, limits)
+}
+
+// SetObserver sets a callback for collected snapshots, e.g. a trace
+// recorder.
+func (a *Aggregator) SetObserver(observer SnapshotObserver) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.observer = observer
+}
+
+// SetTimeouts sets the deadline of each Collect call. perMonitor overrides
+// the default by monitor name. Non-positive values keep DefaultCollectTimeout.
+func (a *Aggregator) SetTimeouts(timeout time.Duration, perMonitor map[string]time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultCollectTimeout
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.timeout = timeout
+	a.timeouts = perMonitor
+}
+
+// Health returns the collection health of every monitor.
+func (a *Aggregator) Health() HealthState {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	health := make(HealthState, len(a.state.Health))
+	for name, h := range a.state.Health {
+		health[name] = h
+	}
+	return health
+}
+
+// History returns the snapshot history, or nil if it is disabled.
+func (a *Aggregator) History() *History {
+	return a.history
+}
+
 func (a *Aggregator) Start(ctx context.Context) error {
 	// Initial collection
 	a.collect()
@@ -69,6 +170,35 @@ func (a *Aggregator) GetState() *SystemState {
 	return a.state.Clone()
 }
 
+// GetSmoothedState returns the state used for admission decisions: the
+// latest snapshot with each resource aggregated per its smoothing.
+func (a *Aggregator) GetSmoothedState() *SystemState {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.smoothed.Clone()
+}
+
+// SetSmoothing replaces the smoothing per resource and restarts all
+// smoothed series. Resources not listed use the latest sample.
+func (a *Aggregator) SetSmoothing(smoothing map[string]Smoothing) {
+	a.smoothMu.Lock()
+	defer a.smoothMu.Unlock()
+	a.smoothing = smoothing
+	a.series = make(map[string]*smoother)
+}
+
+// Smoothing returns the smoothing spec of every smoothed resource.
+func (a *Aggregator) Smoothing() map[string]string {
+	a.smoothMu.Lock()
+	defer a.smoothMu.Unlock()
+
+	specs := make(map[string]string, len(SmoothedResources))
+	for _, resource := range SmoothedResources {
+		specs[resource] = a.smoothing[resource].String()
+	}
+	return specs
+}
+
 func (a *Aggregator) GetStateJSON() ([]byte, error) {
 	state := a.GetState()
 	return json.Marshal(state)
@@ -109,29 +239,35 @@ func (a *Aggregator) InjectMetrics(metrics *InjectedMetrics) error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
+	// The current snapshot may be held by history and the observer, so
+	// the injected values go into a copy
+	state := a.state.Clone()
+
 	if metrics.CPU != nil {
-		a.state.CPU.UsagePercent = *metrics.CPU
+		state.CPU.UsagePercent = *metrics.CPU
 		// Update all cores proportionally
-		for i := range a.state.CPU.Cores {
-			a.state.CPU.Cores[i] = *metrics.CPU
+		for i := range state.CPU.Cores {
+			state.CPU.Cores[i] = *metrics.CPU
 		}
 	}
 
 	if metrics.Memory != nil {
-		a.state.Memory.UsagePercent = *metrics.Memory
+		state.Memory.UsagePercent = *metrics.Memory
 		// Calculate used bytes based on percentage
-		a.state.Memory.UsedBytes = uint64(float64(a.state.Memory.TotalBytes) * (*metrics.Memory / 100))
+		state.Memory.UsedBytes = uint64(float64(state.Memory.TotalBytes) * (*metrics.Memory / 100))
+		state.Memory.AvailableBytes = state.Memory.TotalBytes - state.Memory.UsedBytes
+		state.Memory.AvailableKnown = state.Memory.TotalBytes > 0
 	}
 
 	// GPU metrics
 	gpuIdx := metrics.GPUIndex
 	if metrics.GPUUsage != nil || metrics.VRAMUsage != nil {
 		// Ensure GPU exists
-		if gpuIdx >= len(a.state.GPUs) {
+		if gpuIdx >= len(state.GPUs) {
 			// Create a synthetic GPU for testing
-			for len(a.state.GPUs) <= gpuIdx {
-				a.state.GPUs = append(a.state.GPUs, GPUState{
-					Index:          len(a.state.GPUs),
+			for len(state.GPUs) <= gpuIdx {
+				state.GPUs = append(state.GPUs, GPUState{
+					Index:          len(state.GPUs),
 					Name:           "Debug GPU",
 					VRAMTotalBytes: 24 * 1024 * 1024 * 1024, // 24GB default
 				})
@@ -139,16 +275,19 @@ func (a *Aggregator) InjectMetrics(metrics *InjectedMetrics) error {
 		}
 
 		if metrics.GPUUsage != nil {
-			a.state.GPUs[gpuIdx].UsagePercent = *metrics.GPUUsage
+			state.GPUs[gpuIdx].UsagePercent = *metrics.GPUUsage
 		}
 		if metrics.VRAMUsage != nil {
-			a.state.GPUs[gpuIdx].VRAMUsedBytes = uint64(
-				float64(a.state.GPUs[gpuIdx].VRAMTotalBytes) * (*metrics.VRAMUsage / 100),
+			state.GPUs[gpuIdx].VRAMUsedBytes = uint64(
+				float64(state.GPUs[gpuIdx].VRAMTotalBytes) * (*metrics.VRAMUsage / 100),
 			)
 		}
 	}
 
-	a.state.Timestamp = time.Now()
+	state.Timestamp = time.Now()
+	a.state = state
+	// Injected values take effect immediately, bypassing smoothing
+	a.smoothed = a.state.Clone()
 	a.logger.Debug("metrics injected",
 		"cpu", metrics.CPU,
 		"memory", metrics.Memory,
@@ -175,54 +314,165 @@ func (a *Aggregator) runLoop(ctx context.Context) {
 	}
 }
 
+// collect runs all monitors concurrently, each bounded by its timeout.
+// A monitor that fails or misses its deadline contributes its last good
+// value and is marked stale. A monitor still running from an earlier
+// cycle is not started again until it returns.
 func (a *Aggregator) collect() {
+	a.mu.RLock()
+	timeout, timeouts, observer := a.timeout, a.timeouts, a.observer
+	a.mu.RUnlock()
+
+	started := time.Now()
+	pending := make([]chan collectResult, len(a.monitors))
+	for i, m := range a.monitors {
+		if ch, ok := a.inflight[m.Name()]; ok {
+			pending[i] = ch
+		} else {
+			pending[i] = startCollect(m)
+		}
+	}
+
 	newState := &SystemState{
 		Timestamp: time.Now(),
 		GPUs:      []GPUState{},
 		Storage:   make(StorageState),
+		Health:    make(HealthState, len(a.monitors)),
 	}
 
-	for _, m := range a.monitors {
-		data, err := m.Collect()
-		if err != nil {
+	for i, m := range a.monitors {
+		name := m.Name()
+		deadline := timeout
+		if d, ok := timeouts[name]; ok && d > 0 {
+			deadline = d
+		}
+
+		var result collectResult
+		wait := time.NewTimer(time.Until(started.Add(deadline)))
+		select {
+		case result = <-pending[i]:
+			delete(a.inflight, name)
+		case <-wait.C:
+			a.inflight[name] = pending[i]
+			result.err = fmt.Errorf("collection timed out after %s", deadline)
+		}
+		wait.Stop()
+
+		health := a.health[name]
+		if result.err != nil {
 			a.logger.Warn("monitor collection failed",
-				"monitor", m.Name(),
-				"error", err,
+				"monitor", name,
+				"error", result.err,
 			)
-			continue
+			health.Stale = true
+			health.ConsecutiveFailures++
+			health.LastError = result.err.Error()
+			result.data = a.lastGood[name]
+		} else {
+			health.Stale = false
+			health.ConsecutiveFailures = 0
+			health.LastError = ""
+			health.LastSuccess = newState.Timestamp
+			a.lastGood[name] = result.data
 		}
+		health.Section = m.Section()
+		a.health[name] = health
+		newState.Health[name] = health
 
-		switch m.Name() {
-		case "cpu":
-			if cpuState, ok := data.(*CPUState); ok {
-				newState.CPU = *cpuState
-			}
-		case "memory":
-			if memState, ok := data.(*MemoryState); ok {
-				newState.Memory = *memState
-			}
-		case "storage":
-			if storageState, ok := data.(StorageState); ok {
-				newState.Storage = storageState
-			}
-		case "process":
-			if procState, ok := data.(*ProcessState); ok {
-				newState.Processes = procState.Processes
-				newState.Threads = procState.Threads
-				newState.ContextSwitchesPerSec = procState.ContextSwitchesPerSec
-			}
-		case "gpu":
-			if gpuStates, ok := data.([]GPUState); ok {
-				newState.GPUs = gpuStates
-			}
+		if result.data != nil {
+			result.data.Apply(newState)
 		}
 	}
 
+	a.mergePushed(newState)
+	smoothed := a.smooth(newState)
+
 	a.mu.Lock()
 	a.state = newState
+	a.smoothed = smoothed
 	if !a.ready {
 		a.ready = true
 		a.readyTime = time.Now()
 	}
 	a.mu.Unlock()
+
+	if a.history != nil {
+		a.history.Record(newState)
+	}
+	if observer != nil {
+		observer(newState)
+	}
+}
+
+// smooth returns the state with each resource replaced by its smoothed
+// value. Returns the state itself if every resource is instant.
+func (a *Aggregator) smooth(state *SystemState) *SystemState {
+	a.smoothMu.Lock()
+	defer a.smoothMu.Unlock()
+
+	active := false
+	for _, s := range a.smoothing {
+		if s.Kind != SmoothingInstant {
+			active = true
+		}
+	}
+	if !active {
+		return state
+	}
+
+	smoothed := state.Clone()
+	seen := make(map[string]bool)
+	apply := func(resource, key string, v float64) float64 {
+		s, ok := a.smoothing[resource]
+		if !ok || s.Kind == SmoothingInstant {
+			return v
+		}
+		seen[key] = true
+		series, ok := a.series[key]
+		if !ok {
+			series = &smoother{smoothing: s}
+			a.series[key] = series
+		}
+		return series.add(state.Timestamp, v)
+	}
+
+	smoothed.CPU.UsagePercent = apply("cpu", "cpu", state.CPU.UsagePercent)
+	for i, v := range state.CPU.Cores {
+		smoothed.CPU.Cores[i] = apply("cpu", fmt.Sprintf("cpu/%d", i), v)
+	}
+
+	mem := &smoothed.Memory
+	mem.UsagePercent = apply("memory", "memory", state.Memory.UsagePercent)
+	if mem.TotalBytes > 0 && mem.AvailableKnown {
+		// Smoothed as used percent, so max and percentiles pick the worst case
+		used := apply("memory", "memory/used_from_available", state.Memory.UsedPercentFromAvailable())
+		mem.AvailableBytes = uint64(float64(mem.TotalBytes) * (100 - used) / 100)
+	}
+
+	smoothed.Swap.UsagePercent = apply("swap", "swap", state.Swap.UsagePercent)
+
+	for i, gpu := range state.GPUs {
+		smoothed.GPUs[i].UsagePercent = apply("gpu", fmt.Sprintf("gpu/%d", i), gpu.UsagePercent)
+		smoothed.GPUs[i].VRAMUsedBytes = uint64(apply("vram", fmt.Sprintf("vram/%d", i), float64(gpu.VRAMUsedBytes)))
+	}
+
+	for name, dev := range state.IO.Devices {
+		dev.UtilPercent = apply("io", "io/"+name, dev.UtilPercent)
+		smoothed.IO.Devices[name] = dev
+	}
+
+	for name, iface := range state.Network {
+		iface.RxPercent = apply("network", "network/"+name+"/rx", iface.RxPercent)
+		iface.TxPercent = apply("network", "network/"+name+"/tx", iface.TxPercent)
+		smoothed.Network[name] = iface
+	}
+
+	// Forget series of devices that disappeared
+	for key := range a.series {
+		if !seen[key] {
+			delete(a.series, key)
+		}
+	}
+
+	return smoothed
 }
@@ -14,7 +14,7 @@ func testLogger() *slog.Logger {
 
 type mockMonitor struct {
 	name string
-	data any
+	data Sample
 	err  error
 }
 
@@ -22,7 +22,11 @@ func (m *mockMonitor) Name() string {
 	return m.name
 }
 
-func (m *mockMonitor) Collect() (any, error) {
+func (m *mockMonitor) Section() string {
+	return m.name
+}
+
+func (m *mockMonitor) Collect() (Sample, error) {
 	return m.data, m.err
 }
 
@@ -77,6 +81,20 @@ func TestAggregator_StateClone(t *testing.T) {
 		Storage: StorageState{
 			"/": {UsedBytes: 100, TotalBytes: 200},
 		},
+		IO: IOState{
+			Devices: map[string]DeviceIOState{"sda": {UtilPercent: 10}},
+			Paths:   map[string]string{"/": "sda"},
+		},
+		Network: NetworkState{"eth0": {RxPercent: 10}},
+		Thermal: ThermalState{
+			Sensors: map[string]float64{"acpitz": 40},
+			Cores:   []CoreFreqState{{CPU: 0, CurMHz: 2000}},
+		},
+		NUMA: NUMAState{
+			Nodes:    []NUMANodeState{{ID: 0, CPUs: []int{0, 1}}},
+			CoreNode: []int{0, 0},
+		},
+		Health: HealthState{"cpu": {ConsecutiveFailures: 1}},
 	}
 
 	clone := state.Clone()
@@ -85,6 +103,14 @@ func TestAggregator_StateClone(t *testing.T) {
 	state.CPU.Cores[0] = 100.0
 	state.GPUs[0].Name = "Modified"
 	state.Storage["/tmp"] = DiskState{}
+	state.IO.Devices["sda"] = DeviceIOState{UtilPercent: 90}
+	state.IO.Paths["/data"] = "sdb"
+	state.Network["eth0"] = InterfaceState{RxPercent: 90}
+	state.Thermal.Sensors["acpitz"] = 99
+	state.Thermal.Cores[0].CurMHz = 800
+	state.NUMA.Nodes[0].CPUs[0] = 7
+	state.NUMA.CoreNode[1] = -1
+	state.Health["cpu"] = MonitorHealth{ConsecutiveFailures: 5}
 
 	// Clone should be unchanged
 	if clone.CPU.Cores[0] != 40.0 {
@@ -98,6 +124,30 @@ func TestAggregator_StateClone(t *testing.T) {
 	if _, exists := clone.Storage["/tmp"]; exists {
 		t.Error("clone storage should not have /tmp")
 	}
+
+	if clone.IO.Devices["sda"].UtilPercent != 10 {
+		t.Errorf("clone I/O device modified: %f", clone.IO.Devices["sda"].UtilPercent)
+	}
+
+	if _, exists := clone.IO.Paths["/data"]; exists {
+		t.Error("clone I/O paths should not have /data")
+	}
+
+	if clone.Network["eth0"].RxPercent != 10 {
+		t.Errorf("clone network interface modified: %f", clone.Network["eth0"].RxPercent)
+	}
+
+	if clone.Thermal.Sensors["acpitz"] != 40 || clone.Thermal.Cores[0].CurMHz != 2000 {
+		t.Errorf("clone thermal state modified: %+v", clone.Thermal)
+	}
+
+	if clone.NUMA.Nodes[0].CPUs[0] != 0 || clone.NUMA.CoreNode[1] != 0 {
+		t.Errorf("clone NUMA state modified: %+v", clone.NUMA)
+	}
+
+	if clone.Health["cpu"].ConsecutiveFailures != 1 {
+		t.Errorf("clone health modified: %+v", clone.Health)
+	}
 }
 
 func TestAggregator_GetStateJSON(t *testing.T) {
@@ -300,3 +350,49 @@ func TestAggregator_InjectMetrics_ValidGPUIndex(t *testing.T) {
 		t.Errorf("expected GPU usage 75.0, got %f", state.GPUs[0].UsagePercent)
 	}
 }
+
+func TestAggregator_InjectMetrics_KeepsPublishedSnapshots(t *testing.T) {
+	monitors := []Monitor{
+		&mockMonitor{name: "cpu", data: &CPUState{UsagePercent: 50.0, Cores: []float64{50.0}}},
+	}
+
+	agg := NewAggregator(monitors, time.Second, testLogger())
+	var observed *SystemState
+	agg.SetObserver(func(state *SystemState) { observed = state })
+	agg.collect()
+
+	cpu := 90.0
+	if err := agg.InjectMetrics(&InjectedMetrics{CPU: &cpu}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if observed.CPU.UsagePercent != 50.0 || observed.CPU.Cores[0] != 50.0 {
+		t.Errorf("expected the published snapshot to be unchanged, got %+v", observed.CPU)
+	}
+	if state := agg.GetState(); state.CPU.UsagePercent != 90.0 {
+		t.Errorf("expected the injected value in the current state, got %v", state.CPU.UsagePercent)
+	}
+}
+
+func TestAggregator_InjectMetrics_SmoothedIsCopy(t *testing.T) {
+	monitors := []Monitor{
+		&mockMonitor{name: "cpu", data: &CPUState{UsagePercent: 50.0, Cores: []float64{50.0}}},
+	}
+
+	agg := NewAggregator(monitors, time.Second, testLogger())
+	agg.collect()
+
+	cpu := 90.0
+	if err := agg.InjectMetrics(&InjectedMetrics{CPU: &cpu}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The smoothed snapshot must not share memory with the raw state
+	if agg.smoothed == agg.state || &agg.smoothed.CPU.Cores[0] == &agg.state.CPU.Cores[0] {
+		t.Fatal("expected the smoothed snapshot to be a copy of the state")
+	}
+	agg.state.CPU.Cores[0] = 10.0
+	if smoothed := agg.GetSmoothedState(); smoothed.CPU.UsagePercent != 90.0 || smoothed.CPU.Cores[0] != 90.0 {
+		t.Errorf("expected the injected values in the smoothed state, got %+v", smoothed.CPU)
+	}
+}
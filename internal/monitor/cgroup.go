@@ -0,0 +1,270 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/mem"
+)
+
+// DefaultCgroupPath is the cgroup v2 unified hierarchy mount point.
+// Inside a container with a private cgroup namespace it is the container's own cgroup.
+const DefaultCgroupPath = "/sys/fs/cgroup"
+
+// CheckCgroup returns an error unless path is a cgroup v2 directory with
+// the memory and cpu controllers enabled.
+func CheckCgroup(path string) error {
+	for _, file := range []string{"memory.current", "memory.max", "cpu.max", "cpu.stat"} {
+		if _, err := os.Stat(filepath.Join(path, file)); err != nil {
+			return fmt.Errorf("cgroup v2 file %s not found in %s: %w", file, path, err)
+		}
+	}
+	return nil
+}
+
+// CgroupMemoryMonitor reports memory usage relative to the cgroup limit
+// (memory.max) instead of host RAM, which is what the OOM killer enforces.
+// Without a limit, host RAM is used as the ceiling.
+type CgroupMemoryMonitor struct {
+	path      string
+	hostTotal func() (uint64, error)
+}
+
+// NewCgroupMemoryMonitor creates a memory monitor reading the given cgroup v2 directory.
+func NewCgroupMemoryMonitor(path string) *CgroupMemoryMonitor {
+	if path == "" {
+		path = DefaultCgroupPath
+	}
+
+	return &CgroupMemoryMonitor{
+		path:      path,
+		hostTotal: hostMemoryTotal,
+	}
+}
+
+func (m *CgroupMemoryMonitor) Name() string {
+	return "memory"
+}
+
+func (m *CgroupMemoryMonitor) Section() string {
+	return SectionMemory
+}
+
+func (m *CgroupMemoryMonitor) Collect() (Sample, error) {
+	used, err := readCgroupUint(filepath.Join(m.path, "memory.current"))
+	if err != nil {
+		return nil, err
+	}
+
+	limit, unlimited, err := readCgroupMax(filepath.Join(m.path, "memory.max"))
+	if err != nil {
+		return nil, err
+	}
+
+	if unlimited {
+		if limit, err = m.hostTotal(); err != nil {
+			return nil, err
+		}
+	}
+
+	state := &MemoryState{
+		UsedBytes:  used,
+		TotalBytes: limit,
+	}
+	if limit > 0 {
+		state.UsagePercent = float64(used) / float64(limit) * 100
+	}
+
+	// memory.current includes page cache and slab. Inactive file pages and
+	// reclaimable slab can be dropped before the OOM killer runs, so they
+	// count as available. Missing keys read as 0.
+	stat := filepath.Join(m.path, "memory.stat")
+	state.CachedBytes, _ = readCgroupStat(stat, "file")
+	state.ShmemBytes, _ = readCgroupStat(stat, "shmem")
+	state.SlabReclaimableBytes, _ = readCgroupStat(stat, "slab_reclaimable")
+	inactiveFile, _ := readCgroupStat(stat, "inactive_file")
+
+	if used < limit {
+		state.AvailableBytes = limit - used
+	}
+	state.AvailableBytes = min(state.AvailableBytes+inactiveFile+state.SlabReclaimableBytes, limit)
+	state.AvailableKnown = true
+
+	return state, nil
+}
+
+// CgroupCPUMonitor reports CPU usage relative to the cgroup quota (cpu.max),
+// computed from usage_usec in cpu.stat. Without a quota, all host CPUs are
+// the ceiling. Per-core usage is not available for a cgroup.
+type CgroupCPUMonitor struct {
+	path      string
+	hostCPUs  int
+	prevUsage uint64
+	prevTime  time.Time
+	mu        sync.Mutex
+}
+
+// NewCgroupCPUMonitor creates a CPU monitor reading the given cgroup v2 directory.
+func NewCgroupCPUMonitor(path string) *CgroupCPUMonitor {
+	if path == "" {
+		path = DefaultCgroupPath
+	}
+
+	return &CgroupCPUMonitor{
+		path:     path,
+		hostCPUs: runtime.NumCPU(),
+	}
+}
+
+func (m *CgroupCPUMonitor) Name() string {
+	return "cpu"
+}
+
+func (m *CgroupCPUMonitor) Section() string {
+	return SectionCPU
+}
+
+func (m *CgroupCPUMonitor) Collect() (Sample, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	limitCores, err := m.limitCores()
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := readCgroupStat(filepath.Join(m.path, "cpu.stat"), "usage_usec")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	state := &CPUState{
+		Cores:      []float64{},
+		LimitCores: limitCores,
+	}
+
+	// Usage is a rate, so the first sample only records the baseline
+	if !m.prevTime.IsZero() && usage >= m.prevUsage {
+		elapsed := now.Sub(m.prevTime).Seconds()
+		if elapsed > 0 && limitCores > 0 {
+			usedCores := float64(usage-m.prevUsage) / 1e6 / elapsed
+			state.UsagePercent = clampPercent(usedCores / limitCores * 100)
+		}
+	}
+
+	m.prevUsage = usage
+	m.prevTime = now
+
+	return state, nil
+}
+
+// limitCores returns the CPU quota in cores, or the host CPU count if unlimited.
+func (m *CgroupCPUMonitor) limitCores() (float64, error) {
+	path := filepath.Join(m.path, "cpu.max")
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	// Format: "<quota|max> <period>"
+	fields := strings.Fields(string(data))
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("unexpected %s content: %q", path, strings.TrimSpace(string(data)))
+	}
+
+	if fields[0] == "max" {
+		return float64(m.hostCPUs), nil
+	}
+
+	quota, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid quota in %s: %w", path, err)
+	}
+	period, err := strconv.ParseFloat(fields[1], 64)
+	if err != nil || period <= 0 {
+		return 0, fmt.Errorf("invalid period in %s: %q", path, fields[1])
+	}
+
+	return quota / period, nil
+}
+
+// readCgroupUint reads a single-value cgroup file.
+func readCgroupUint(path string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	v, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value in %s: %w", path, err)
+	}
+	return v, nil
+}
+
+// readCgroupMax reads a limit file where "max" means unlimited.
+func readCgroupMax(path string) (value uint64, unlimited bool, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false, err
+	}
+
+	s := strings.TrimSpace(string(data))
+	if s == "max" {
+		return 0, true, nil
+	}
+
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid value in %s: %w", path, err)
+	}
+	return v, false, nil
+}
+
+// readCgroupStat reads a key from a flat-keyed cgroup file such as cpu.stat.
+func readCgroupStat(path, key string) (uint64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 && fields[0] == key {
+			v, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("invalid %s in %s: %w", key, path, err)
+			}
+			return v, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%s not found in %s", key, path)
+}
+
+// hostMemoryTotal returns host RAM, used when the cgroup has no memory limit.
+func hostMemoryTotal() (uint64, error) {
+	v, err := mem.VirtualMemory()
+	if err != nil {
+		return 0, err
+	}
+	return v.Total, nil
+}
+
+// clampPercent limits a percentage to [0, 100].
+func clampPercent(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	if v > 100 {
+		return 100
+	}
+	return v
+}
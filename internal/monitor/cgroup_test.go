@@ -0,0 +1,214 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeCgroup writes cgroup v2 fixture files into a temp directory.
+func fakeCgroup(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content+"\n"), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func cgroupCPUStat(usageUsec string) string {
+	return "usage_usec " + usageUsec + "\nuser_usec 1000\nsystem_usec 500\nnr_periods 0\nnr_throttled 0\nthrottled_usec 0"
+}
+
+func TestCheckCgroup(t *testing.T) {
+	dir := fakeCgroup(t, map[string]string{
+		"memory.current": "0",
+		"memory.max":     "max",
+		"cpu.max":        "max 100000",
+		"cpu.stat":       cgroupCPUStat("0"),
+	})
+
+	if err := CheckCgroup(dir); err != nil {
+		t.Errorf("expected valid cgroup, got %v", err)
+	}
+
+	if err := CheckCgroup(t.TempDir()); err == nil {
+		t.Error("expected error for directory without cgroup files")
+	}
+}
+
+func TestCgroupMemoryMonitor_Limited(t *testing.T) {
+	dir := fakeCgroup(t, map[string]string{
+		"memory.current": "536870912",  // 512 MiB
+		"memory.max":     "2147483648", // 2 GiB
+	})
+
+	m := NewCgroupMemoryMonitor(dir)
+	if m.Name() != "memory" {
+		t.Errorf("expected name 'memory', got %s", m.Name())
+	}
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	state, ok := data.(*MemoryState)
+	if !ok {
+		t.Fatalf("expected *MemoryState, got %T", data)
+	}
+
+	if state.UsedBytes != 536870912 {
+		t.Errorf("expected 536870912 used, got %d", state.UsedBytes)
+	}
+	if state.TotalBytes != 2147483648 {
+		t.Errorf("expected limit 2147483648 as total, got %d", state.TotalBytes)
+	}
+	if state.UsagePercent != 25 {
+		t.Errorf("expected 25%% usage, got %f", state.UsagePercent)
+	}
+}
+
+func TestCgroupMemoryMonitor_Reclaimable(t *testing.T) {
+	dir := fakeCgroup(t, map[string]string{
+		"memory.current": "1610612736", // 1.5 GiB, mostly page cache
+		"memory.max":     "2147483648", // 2 GiB
+		"memory.stat": "anon 268435456\nfile 1207959552\nshmem 67108864\n" +
+			"inactive_file 1073741824\nslab_reclaimable 134217728\n",
+	})
+
+	data, err := NewCgroupMemoryMonitor(dir).Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	state := data.(*MemoryState)
+	if state.UsagePercent != 75 {
+		t.Errorf("expected 75%% used, got %f", state.UsagePercent)
+	}
+	if state.CachedBytes != 1207959552 || state.ShmemBytes != 67108864 || state.SlabReclaimableBytes != 134217728 {
+		t.Errorf("unexpected memory.stat breakdown: %+v", state)
+	}
+	// 512 MiB free + 1 GiB inactive file + 128 MiB slab
+	if !state.AvailableKnown || state.AvailableBytes != 1744830464 {
+		t.Errorf("expected 1744830464 available, got %d", state.AvailableBytes)
+	}
+	if got := state.Percent(MemoryModeAvailable); got != 18.75 {
+		t.Errorf("expected 18.75%% in available mode, got %f", got)
+	}
+}
+
+func TestCgroupMemoryMonitor_Unlimited(t *testing.T) {
+	dir := fakeCgroup(t, map[string]string{
+		"memory.current": "1073741824",
+		"memory.max":     "max",
+	})
+
+	m := NewCgroupMemoryMonitor(dir)
+	m.hostTotal = func() (uint64, error) { return 4294967296, nil }
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	state := data.(*MemoryState)
+	if state.TotalBytes != 4294967296 {
+		t.Errorf("expected host total without limit, got %d", state.TotalBytes)
+	}
+	if state.UsagePercent != 25 {
+		t.Errorf("expected 25%% usage, got %f", state.UsagePercent)
+	}
+}
+
+func TestCgroupMemoryMonitor_Missing(t *testing.T) {
+	if _, err := NewCgroupMemoryMonitor(t.TempDir()).Collect(); err == nil {
+		t.Error("expected error without memory.current")
+	}
+}
+
+func TestCgroupCPUMonitor_Quota(t *testing.T) {
+	dir := fakeCgroup(t, map[string]string{
+		"cpu.max":  "200000 100000", // 2 cores
+		"cpu.stat": cgroupCPUStat("10000000"),
+	})
+
+	m := NewCgroupCPUMonitor(dir)
+	if m.Name() != "cpu" {
+		t.Errorf("expected name 'cpu', got %s", m.Name())
+	}
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("first collect failed: %v", err)
+	}
+
+	state, ok := data.(*CPUState)
+	if !ok {
+		t.Fatalf("expected *CPUState, got %T", data)
+	}
+	if state.UsagePercent != 0 {
+		t.Errorf("expected 0%% on first sample, got %f", state.UsagePercent)
+	}
+	if state.LimitCores != 2 {
+		t.Errorf("expected 2 limit cores, got %f", state.LimitCores)
+	}
+
+	// One core busy for one second = 50% of a 2-core quota
+	m.prevTime = time.Now().Add(-time.Second)
+	if err := os.WriteFile(filepath.Join(dir, "cpu.stat"), []byte(cgroupCPUStat("11000000")), 0644); err != nil {
+		t.Fatalf("failed to update cpu.stat: %v", err)
+	}
+
+	data, err = m.Collect()
+	if err != nil {
+		t.Fatalf("second collect failed: %v", err)
+	}
+
+	state = data.(*CPUState)
+	if state.UsagePercent < 45 || state.UsagePercent > 50 {
+		t.Errorf("expected ~50%% usage, got %f", state.UsagePercent)
+	}
+}
+
+func TestCgroupCPUMonitor_Unlimited(t *testing.T) {
+	dir := fakeCgroup(t, map[string]string{
+		"cpu.max":  "max 100000",
+		"cpu.stat": cgroupCPUStat("0"),
+	})
+
+	m := NewCgroupCPUMonitor(dir)
+	m.hostCPUs = 8
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	if state := data.(*CPUState); state.LimitCores != 8 {
+		t.Errorf("expected host CPUs as limit, got %f", state.LimitCores)
+	}
+}
+
+func TestCgroupCPUMonitor_Malformed(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+	}{
+		{"bad cpu.max", map[string]string{"cpu.max": "lots", "cpu.stat": cgroupCPUStat("0")}},
+		{"bad quota", map[string]string{"cpu.max": "abc 100000", "cpu.stat": cgroupCPUStat("0")}},
+		{"no usage_usec", map[string]string{"cpu.max": "max 100000", "cpu.stat": "user_usec 1"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewCgroupCPUMonitor(fakeCgroup(t, tt.files)).Collect(); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
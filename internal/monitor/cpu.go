@@ -14,7 +14,11 @@ func (m *CPUMonitor) Name() string {
 	return "cpu"
 }
 
-func (m *CPUMonitor) Collect() (any, error) {
+func (m *CPUMonitor) Section() string {
+	return SectionCPU
+}
+
+func (m *CPUMonitor) Collect() (Sample, error) {
 	// Get overall CPU usage
 	percentages, err := cpu.Percent(0, false)
 	if err != nil {
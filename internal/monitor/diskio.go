@@ -0,0 +1,324 @@
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// diskSectorSize is the unit of the sector counters in /proc/diskstats,
+// independent of the device's physical sector size.
+const diskSectorSize = 512
+
+// DiskIOMonitor derives per-device throughput, IOPS and utilization from
+// /proc/diskstats and CPU iowait from /proc/stat. Monitored paths are mapped
+// to their backing device through /proc/self/mountinfo.
+// Graceful degradation: on systems without these files, returns zero values.
+type DiskIOMonitor struct {
+	procRoot string
+	paths    []string
+
+	prev     map[string]diskCounters // keyed by major:minor
+	prevCPU  cpuTimes
+	prevTime time.Time
+	mu       sync.Mutex
+}
+
+// diskCounters holds the /proc/diskstats counters of one device.
+type diskCounters struct {
+	name           string
+	readsDone      uint64
+	sectorsRead    uint64
+	writesDone     uint64
+	sectorsWritten uint64
+	ioTicksMS      uint64 // time spent doing I/Os
+}
+
+// cpuTimes holds the aggregate "cpu" line of /proc/stat.
+type cpuTimes struct {
+	iowait uint64
+	total  uint64
+}
+
+// NewDiskIOMonitor creates a disk I/O monitor reading from the given procfs root.
+func NewDiskIOMonitor(procRoot string, paths []string) *DiskIOMonitor {
+	if procRoot == "" {
+		procRoot = DefaultProcRoot
+	}
+	if len(paths) == 0 {
+		paths = []string{"/"}
+	}
+
+	return &DiskIOMonitor{
+		procRoot: procRoot,
+		paths:    paths,
+	}
+}
+
+func (m *DiskIOMonitor) Name() string {
+	return "io"
+}
+
+func (m *DiskIOMonitor) Section() string {
+	return SectionIO
+}
+
+func (m *DiskIOMonitor) Collect() (Sample, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := &IOState{
+		Devices: make(map[string]DeviceIOState),
+		Paths:   make(map[string]string),
+	}
+
+	counters, err := readDiskStats(filepath.Join(m.procRoot, "diskstats"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	cpu, err := readCPUTimes(filepath.Join(m.procRoot, "stat"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	// Paths on filesystems without a block device (tmpfs, overlay, btrfs
+	// subvolumes) are left unmapped
+	mounts := readMountDevices(filepath.Join(m.procRoot, "self", "mountinfo"))
+	monitored := make(map[string]bool)
+	for _, path := range m.paths {
+		if dev, ok := counters[mountDeviceFor(mounts, path)]; ok {
+			state.Paths[path] = dev.name
+			monitored[dev.name] = true
+		}
+	}
+
+	now := time.Now()
+	elapsed := now.Sub(m.prevTime).Seconds()
+	hasPrev := !m.prevTime.IsZero() && elapsed > 0
+
+	for id, cur := range counters {
+		if !monitored[cur.name] && isVirtualDisk(cur.name) {
+			continue
+		}
+
+		var dev DeviceIOState
+		if prev, ok := m.prev[id]; ok && hasPrev {
+			dev.ReadBytesPerSec = float64(counterRate(prev.sectorsRead, cur.sectorsRead, elapsed) * diskSectorSize)
+			dev.WriteBytesPerSec = float64(counterRate(prev.sectorsWritten, cur.sectorsWritten, elapsed) * diskSectorSize)
+			dev.ReadIOPS = float64(counterRate(prev.readsDone, cur.readsDone, elapsed))
+			dev.WriteIOPS = float64(counterRate(prev.writesDone, cur.writesDone, elapsed))
+			// io_ticks is the wall time (ms) the device had I/O in flight
+			dev.UtilPercent = clampPercent(float64(counterRate(prev.ioTicksMS, cur.ioTicksMS, elapsed)) / 10)
+		}
+		state.Devices[cur.name] = dev
+	}
+
+	if hasPrev && cpu.total > m.prevCPU.total && cpu.iowait >= m.prevCPU.iowait {
+		state.IOWaitPercent = clampPercent(
+			float64(cpu.iowait-m.prevCPU.iowait) / float64(cpu.total-m.prevCPU.total) * 100,
+		)
+	}
+
+	m.prev = counters
+	m.prevCPU = cpu
+	m.prevTime = now
+
+	return state, nil
+}
+
+// isVirtualDisk reports whether a device is a loop or ram disk, which are
+// only reported when they back a monitored path.
+func isVirtualDisk(name string) bool {
+	return strings.HasPrefix(name, "loop") || strings.HasPrefix(name, "ram")
+}
+
+// readDiskStats parses /proc/diskstats into counters keyed by major:minor.
+func readDiskStats(path string) (map[string]diskCounters, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	counters := make(map[string]diskCounters)
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		// major minor name reads merged sectors ms writes merged sectors ms in_flight io_ticks ...
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 13 {
+			continue
+		}
+
+		values := make([]uint64, 10)
+		for i := range values {
+			v, err := strconv.ParseUint(fields[i+3], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid diskstats value for %s: %w", fields[2], err)
+			}
+			values[i] = v
+		}
+
+		counters[fields[0]+":"+fields[1]] = diskCounters{
+			name:           fields[2],
+			readsDone:      values[0],
+			sectorsRead:    values[2],
+			writesDone:     values[4],
+			sectorsWritten: values[6],
+			ioTicksMS:      values[9],
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return counters, nil
+}
+
+// readCPUTimes reads iowait and total jiffies from the "cpu" line of /proc/stat.
+// guest and guest_nice are already included in user and nice, so only the
+// first eight fields are summed.
+func readCPUTimes(path string) (cpuTimes, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return cpuTimes{}, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 6 || fields[0] != "cpu" {
+			continue
+		}
+
+		var times cpuTimes
+		for i := 1; i < len(fields) && i <= 8; i++ {
+			v, err := strconv.ParseUint(fields[i], 10, 64)
+			if err != nil {
+				return cpuTimes{}, fmt.Errorf("invalid cpu time %q: %w", fields[i], err)
+			}
+			times.total += v
+			if i == 5 {
+				times.iowait = v
+			}
+		}
+		return times, nil
+	}
+
+	return cpuTimes{}, scanner.Err()
+}
+
+// mountDevice is a mount point and the major:minor of its source device.
+type mountDevice struct {
+	mountPoint string
+	device     string
+	readOnly   bool
+}
+
+// readMountDevices parses mount points, device numbers and the read-only
+// flag from a mountinfo file. Returns nil if the file cannot be read.
+func readMountDevices(path string) []mountDevice {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	var mounts []mountDevice
+	for _, line := range strings.Split(string(data), "\n") {
+		// id parent major:minor root mount_point options [optional...] - fstype source super_options
+		fields := strings.Fields(line)
+		if len(fields) < 5 {
+			continue
+		}
+		mnt := mountDevice{
+			mountPoint: unescapeMountPath(fields[4]),
+			device:     fields[2],
+		}
+		if len(fields) > 5 {
+			mnt.readOnly = hasMountOption(fields[5], "ro")
+		}
+		// A filesystem remounted read-only after errors shows it in the
+		// superblock options
+		for i := 6; i < len(fields)-3; i++ {
+			if fields[i] == "-" {
+				mnt.readOnly = mnt.readOnly || hasMountOption(fields[i+3], "ro")
+				break
+			}
+		}
+		mounts = append(mounts, mnt)
+	}
+
+	return mounts
+}
+
+// hasMountOption reports whether a comma-separated option list contains opt.
+func hasMountOption(options, opt string) bool {
+	for _, o := range strings.Split(options, ",") {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// mountFor returns the mount containing path, choosing the longest matching
+// mount point. Later mounts over the same point shadow earlier ones.
+func mountFor(mounts []mountDevice, path string) (mountDevice, bool) {
+	path = filepath.Clean(path)
+
+	var found mountDevice
+	best := -1
+	for _, mnt := range mounts {
+		if !pathUnder(path, mnt.mountPoint) || len(mnt.mountPoint) < best {
+			continue
+		}
+		best = len(mnt.mountPoint)
+		found = mnt
+	}
+
+	return found, best >= 0
+}
+
+// mountDeviceFor returns the major:minor of the mount containing path.
+func mountDeviceFor(mounts []mountDevice, path string) string {
+	mnt, _ := mountFor(mounts, path)
+	return mnt.device
+}
+
+// pathUnder reports whether path is mountPoint or lies below it.
+func pathUnder(path, mountPoint string) bool {
+	if mountPoint == "/" || path == mountPoint {
+		return true
+	}
+	return strings.HasPrefix(path, mountPoint+"/")
+}
+
+// unescapeMountPath decodes the octal escapes (\040 for space, etc.)
+// used in mountinfo paths.
+func unescapeMountPath(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+3 < len(s) {
+			if v, err := strconv.ParseUint(s[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(v))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(s[i])
+	}
+
+	return b.String()
+}
@@ -0,0 +1,188 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeProcDiskIO builds a procfs tree with / on nvme0n1p2, /data on sdb1
+// and /tmp on tmpfs.
+func fakeProcDiskIO(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	mountinfo := "22 1 259:2 / / rw,relatime shared:1 - ext4 /dev/nvme0n1p2 rw\n" +
+		"23 22 0:21 / /proc rw,nosuid shared:12 - proc proc rw\n" +
+		"24 22 8:17 / /data rw,relatime shared:2 - xfs /dev/sdb1 rw\n" +
+		"25 22 0:35 / /tmp rw,nosuid shared:3 - tmpfs tmpfs rw\n" +
+		"26 22 8:33 / /mnt/my\\040disk rw,relatime shared:4 - ext4 /dev/sdc1 rw\n"
+	writeSysfsFile(t, filepath.Join(root, "self", "mountinfo"), mountinfo)
+
+	writeDiskStats(t, root, 0, 0, 0, 0)
+	writeCPUStat(t, root, 0, 0)
+
+	return root
+}
+
+// writeDiskStats writes /proc/diskstats where nvme0n1p2 and sdb1 have the
+// given cumulative counters and loop0 is idle.
+func writeDiskStats(t *testing.T, root string, reads, sectorsRead, writes, ioTicks uint64) {
+	t.Helper()
+
+	line := func(major, minor int, name string) string {
+		// reads merged sectors ms writes merged sectors ms in_flight io_ticks weighted
+		return fmt.Sprintf("%4d %7d %s %d 0 %d 10 %d 0 %d 20 0 %d 30\n",
+			major, minor, name, reads, sectorsRead, writes, writes*8, ioTicks)
+	}
+
+	content := line(259, 0, "nvme0n1") + line(259, 2, "nvme0n1p2") +
+		line(8, 16, "sdb") + line(8, 17, "sdb1") +
+		"   7       0 loop0 0 0 0 0 0 0 0 0 0 0 0\n"
+
+	if err := os.WriteFile(filepath.Join(root, "diskstats"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write diskstats: %v", err)
+	}
+}
+
+// writeCPUStat writes the aggregate cpu line of /proc/stat.
+func writeCPUStat(t *testing.T, root string, iowait, total uint64) {
+	t.Helper()
+
+	// user nice system idle iowait irq softirq steal guest guest_nice
+	content := fmt.Sprintf("cpu  %d 0 0 0 %d 0 0 0 500 0\nctxt 1\n", total-iowait, iowait)
+	if err := os.WriteFile(filepath.Join(root, "stat"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write stat: %v", err)
+	}
+}
+
+func TestDiskIOMonitor_Name(t *testing.T) {
+	m := NewDiskIOMonitor(t.TempDir(), nil)
+	if m.Name() != "io" {
+		t.Errorf("expected name 'io', got %s", m.Name())
+	}
+}
+
+func TestDiskIOMonitor_Collect(t *testing.T) {
+	root := fakeProcDiskIO(t)
+	m := NewDiskIOMonitor(root, []string{"/", "/data/datasets", "/tmp"})
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("first collect failed: %v", err)
+	}
+
+	state, ok := data.(*IOState)
+	if !ok {
+		t.Fatalf("expected *IOState, got %T", data)
+	}
+
+	if state.Paths["/"] != "nvme0n1p2" {
+		t.Errorf("expected / on nvme0n1p2, got %q", state.Paths["/"])
+	}
+	if state.Paths["/data/datasets"] != "sdb1" {
+		t.Errorf("expected /data/datasets on sdb1, got %q", state.Paths["/data/datasets"])
+	}
+	if _, ok := state.Paths["/tmp"]; ok {
+		t.Error("expected tmpfs path to be unmapped")
+	}
+	if _, ok := state.Devices["loop0"]; ok {
+		t.Error("expected unmonitored loop device to be skipped")
+	}
+	if state.Devices["nvme0n1p2"].UtilPercent != 0 {
+		t.Errorf("expected zero util on first call, got %f", state.Devices["nvme0n1p2"].UtilPercent)
+	}
+
+	// Pretend the previous sample was taken one second ago:
+	// 100 reads, 2048 sectors read, 50 writes, 500ms busy, 25% iowait
+	m.prevTime = time.Now().Add(-time.Second)
+	writeDiskStats(t, root, 100, 2048, 50, 500)
+	writeCPUStat(t, root, 25, 100)
+
+	data, err = m.Collect()
+	if err != nil {
+		t.Fatalf("second collect failed: %v", err)
+	}
+
+	state = data.(*IOState)
+	dev := state.Devices["sdb1"]
+
+	if dev.ReadIOPS < 90 || dev.ReadIOPS > 100 {
+		t.Errorf("expected ~100 read IOPS, got %f", dev.ReadIOPS)
+	}
+	if dev.WriteIOPS < 45 || dev.WriteIOPS > 50 {
+		t.Errorf("expected ~50 write IOPS, got %f", dev.WriteIOPS)
+	}
+	if dev.ReadBytesPerSec < 0.9*1048576 || dev.ReadBytesPerSec > 1048576 {
+		t.Errorf("expected ~1 MiB/s read, got %f", dev.ReadBytesPerSec)
+	}
+	if dev.WriteBytesPerSec < 0.9*204800 || dev.WriteBytesPerSec > 204800 {
+		t.Errorf("expected ~200 KiB/s write, got %f", dev.WriteBytesPerSec)
+	}
+	if dev.UtilPercent < 45 || dev.UtilPercent > 50 {
+		t.Errorf("expected ~50%% util, got %f", dev.UtilPercent)
+	}
+	if state.IOWaitPercent != 25 {
+		t.Errorf("expected 25%% iowait, got %f", state.IOWaitPercent)
+	}
+
+	if util := state.PathUtil()["/data/datasets"]; util != dev.UtilPercent {
+		t.Errorf("expected path util %f, got %f", dev.UtilPercent, util)
+	}
+}
+
+func TestDiskIOMonitor_GracefulDegradation(t *testing.T) {
+	data, err := NewDiskIOMonitor(t.TempDir(), []string{"/"}).Collect()
+	if err != nil {
+		t.Fatalf("collect should not fail without procfs: %v", err)
+	}
+
+	state := data.(*IOState)
+	if len(state.Devices) != 0 || len(state.Paths) != 0 || state.IOWaitPercent != 0 {
+		t.Errorf("expected empty I/O state, got %+v", state)
+	}
+}
+
+func TestMountDeviceFor(t *testing.T) {
+	mounts := readMountDevices(filepath.Join(fakeProcDiskIO(t), "self", "mountinfo"))
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/", "259:2"},
+		{"/var/lib", "259:2"},
+		{"/data", "8:17"},
+		{"/data/", "8:17"},
+		{"/database", "259:2"},
+		{"/mnt/my disk/x", "8:33"},
+	}
+
+	for _, tt := range tests {
+		if got := mountDeviceFor(mounts, tt.path); got != tt.want {
+			t.Errorf("mountDeviceFor(%q): expected %s, got %s", tt.path, tt.want, got)
+		}
+	}
+}
+
+func TestIOState_Aggregates(t *testing.T) {
+	state := IOState{
+		Devices: map[string]DeviceIOState{
+			"sda1": {ReadBytesPerSec: 100, WriteBytesPerSec: 10, UtilPercent: 30},
+			"sdb1": {ReadBytesPerSec: 200, WriteBytesPerSec: 20, UtilPercent: 70},
+			"sdc1": {ReadBytesPerSec: 999, UtilPercent: 99}, // not monitored
+		},
+		Paths: map[string]string{"/": "sda1", "/home": "sda1", "/data": "sdb1"},
+	}
+
+	if got := state.MaxUtilPercent(); got != 70 {
+		t.Errorf("expected max util 70, got %f", got)
+	}
+
+	read, write := state.Throughput()
+	if read != 300 || write != 30 {
+		t.Errorf("expected 300/30 bytes/s, got %f/%f", read, write)
+	}
+}
@@ -1,20 +1,58 @@
 package monitor
 
-// GPUMonitor collects NVIDIA GPU metrics.
-// Graceful degradation: if NVML is not available, returns empty slice.
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultNvidiaSMIPath is the nvidia-smi binary looked up in PATH.
+	DefaultNvidiaSMIPath = "nvidia-smi"
+	// DefaultGPUTimeout bounds a single nvidia-smi invocation.
+	DefaultGPUTimeout = 2 * time.Second
+)
+
+// nvidiaSMIQuery lists the fields requested from nvidia-smi, in output order.
+// The name is last since it may contain commas.
+const nvidiaSMIQuery = "index,utilization.gpu,memory.used,memory.total,temperature.gpu,power.draw,name"
+
+// GPUMonitor collects NVIDIA GPU metrics by querying nvidia-smi.
+// Graceful degradation: if nvidia-smi is not available, returns empty slice.
 type GPUMonitor struct {
+	smiPath   string
+	timeout   time.Duration
 	available bool
 }
 
+// NewGPUMonitor creates a GPU monitor using nvidia-smi from PATH.
 func NewGPUMonitor() *GPUMonitor {
+	return NewGPUMonitorWithSMI(DefaultNvidiaSMIPath, DefaultGPUTimeout)
+}
+
+// NewGPUMonitorWithSMI creates a GPU monitor using a custom nvidia-smi binary and timeout.
+func NewGPUMonitorWithSMI(smiPath string, timeout time.Duration) *GPUMonitor {
+	if smiPath == "" {
+		smiPath = DefaultNvidiaSMIPath
+	}
+	if timeout <= 0 {
+		timeout = DefaultGPUTimeout
+	}
+
 	m := &GPUMonitor{
-		available: false,
+		smiPath: smiPath,
+		timeout: timeout,
 	}
 
-	// Try to initialize NVML
-	// For now, we'll implement a stub that returns empty data
-	// Real implementation would use github.com/NVIDIA/go-nvml/pkg/nvml
-	m.available = initNVML()
+	// nvidia-smi is installed alongside the driver, so its presence
+	// is a good indicator that NVIDIA GPUs can be queried
+	if path, err := exec.LookPath(smiPath); err == nil {
+		m.smiPath = path
+		m.available = true
+	}
 
 	return m
 }
@@ -23,12 +61,38 @@ func (m *GPUMonitor) Name() string {
 	return "gpu"
 }
 
-func (m *GPUMonitor) Collect() (any, error) {
+func (m *GPUMonitor) Section() string {
+	return SectionGPU
+}
+
+func (m *GPUMonitor) Collect() (Sample, error) {
 	if !m.available {
-		return []GPUState{}, nil
+		return GPUStates{}, nil
 	}
 
-	return collectGPUMetrics()
+	ctx, cancel := context.WithTimeout(context.Background(), m.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, m.smiPath,
+		"--query-gpu="+nvidiaSMIQuery,
+		"--format=csv,noheader,nounits",
+	)
+	// Don't wait for orphaned children holding stdout after the timeout
+	cmd.WaitDelay = m.timeout
+
+	out, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("nvidia-smi timed out after %s", m.timeout)
+		}
+		return nil, fmt.Errorf("nvidia-smi failed: %w", err)
+	}
+
+	states, err := parseNvidiaSMIOutput(string(out))
+	if err != nil {
+		return nil, err
+	}
+	return GPUStates(states), nil
 }
 
 func (m *GPUMonitor) Available() bool {
@@ -36,34 +100,109 @@ func (m *GPUMonitor) Available() bool {
 }
 
 func (m *GPUMonitor) Close() error {
-	if m.available {
-		return shutdownNVML()
-	}
 	return nil
 }
 
-// Stub implementations - will be replaced with real NVML calls
-// when github.com/NVIDIA/go-nvml is added
+// parseNvidiaSMIOutput parses CSV output of nvidia-smi --query-gpu.
+// Memory is reported in MiB and converted to bytes.
+func parseNvidiaSMIOutput(out string) ([]GPUState, error) {
+	expectedFields := len(strings.Split(nvidiaSMIQuery, ","))
+	states := []GPUState{}
+
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
 
-func initNVML() bool {
-	// TODO: Implement real NVML initialization
-	// ret := nvml.Init()
-	// return ret == nvml.SUCCESS
-	return false
+		// The name takes the rest of the line, commas included
+		fields := strings.SplitN(line, ",", expectedFields)
+		if len(fields) != expectedFields {
+			return nil, fmt.Errorf("unexpected nvidia-smi output: %q", line)
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+
+		index, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid GPU index %q: %w", fields[0], err)
+		}
+
+		states = append(states, GPUState{
+			Index:          index,
+			UsagePercent:   parseSMIFloat(fields[1]),
+			VRAMUsedBytes:  uint64(parseSMIFloat(fields[2]) * 1024 * 1024),
+			VRAMTotalBytes: uint64(parseSMIFloat(fields[3]) * 1024 * 1024),
+			Temperature:    int(parseSMIFloat(fields[4])),
+			PowerWatts:     parseSMIFloat(fields[5]),
+			Name:           fields[6],
+		})
+	}
+
+	return states, nil
 }
 
-func shutdownNVML() error {
-	// TODO: Implement real NVML shutdown
-	// nvml.Shutdown()
-	return nil
+// parseSMIFloat parses a numeric nvidia-smi field.
+// Unsupported values such as "[N/A]" or "[Not Supported]" are reported as 0.
+func parseSMIFloat(s string) float64 {
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// GPU backend names accepted by NewGPUBackend.
+const (
+	GPUBackendAuto   = "auto"
+	GPUBackendNvidia = "nvidia"
+	GPUBackendAMD    = "amd"
+	GPUBackendNone   = "none"
+)
+
+// GPUBackendConfig holds settings for all GPU backends.
+type GPUBackendConfig struct {
+	Backend       string
+	NvidiaSMIPath string
+	Timeout       time.Duration
+	SysfsRoot     string
 }
 
-func collectGPUMetrics() ([]GPUState, error) {
-	// TODO: Implement real GPU metrics collection
-	// count, _ := nvml.DeviceGetCount()
-	// for i := 0; i < count; i++ {
-	//     device, _ := nvml.DeviceGetHandleByIndex(i)
-	//     ...
-	// }
-	return []GPUState{}, nil
+// NewGPUBackend returns the GPU monitor for the configured backend.
+// In auto mode NVIDIA is preferred, then AMD. Returns nil for the none backend.
+func NewGPUBackend(cfg GPUBackendConfig) (Monitor, error) {
+	switch cfg.Backend {
+	case GPUBackendNone:
+		return nil, nil
+
+	case GPUBackendNvidia:
+		return NewGPUMonitorWithSMI(cfg.NvidiaSMIPath, cfg.Timeout), nil
+
+	case GPUBackendAMD:
+		return NewAMDGPUMonitor(cfg.SysfsRoot), nil
+
+	case GPUBackendAuto, "":
+		nvidia := NewGPUMonitorWithSMI(cfg.NvidiaSMIPath, cfg.Timeout)
+		if nvidia.Available() {
+			return nvidia, nil
+		}
+		if amd := NewAMDGPUMonitor(cfg.SysfsRoot); amd.Available() {
+			return amd, nil
+		}
+		return nvidia, nil
+
+	default:
+		return nil, fmt.Errorf("unknown GPU backend: %s", cfg.Backend)
+	}
+}
+
+// VisibleDevices formats GPU indices for CUDA_VISIBLE_DEVICES and
+// HIP_VISIBLE_DEVICES, e.g. "2,0".
+func VisibleDevices(indices []int) string {
+	parts := make([]string, len(indices))
+	for i, index := range indices {
+		parts[i] = strconv.Itoa(index)
+	}
+	return strings.Join(parts, ",")
 }
@@ -0,0 +1,148 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// DefaultSysfsRoot is the mount point of sysfs.
+	DefaultSysfsRoot = "/sys"
+
+	// amdVendorID is the PCI vendor ID of AMD/ATI devices.
+	amdVendorID = "0x1002"
+)
+
+// drmCardRegex matches DRM card directories (card0, card1, ...) but not
+// connector entries such as card0-DP-1.
+var drmCardRegex = regexp.MustCompile(`^card(\d+)$`)
+
+// AMDGPUMonitor collects AMD GPU metrics from the amdgpu DRM sysfs interface.
+// Graceful degradation: if no AMD cards are found, returns empty slice.
+type AMDGPUMonitor struct {
+	devices []amdDevice
+}
+
+// amdDevice is a discovered AMD card.
+type amdDevice struct {
+	card int
+	path string // <sysfs>/class/drm/cardN/device
+}
+
+// NewAMDGPUMonitor creates an AMD GPU monitor reading from the given sysfs root.
+func NewAMDGPUMonitor(sysfsRoot string) *AMDGPUMonitor {
+	if sysfsRoot == "" {
+		sysfsRoot = DefaultSysfsRoot
+	}
+
+	return &AMDGPUMonitor{
+		devices: discoverAMDDevices(sysfsRoot),
+	}
+}
+
+func (m *AMDGPUMonitor) Name() string {
+	return "gpu"
+}
+
+func (m *AMDGPUMonitor) Section() string {
+	return SectionGPU
+}
+
+func (m *AMDGPUMonitor) Collect() (Sample, error) {
+	states := make(GPUStates, 0, len(m.devices))
+
+	for i, dev := range m.devices {
+		state := GPUState{
+			Index:          i,
+			Name:           readSysfsString(filepath.Join(dev.path, "product_name")),
+			UsagePercent:   float64(readSysfsUint(filepath.Join(dev.path, "gpu_busy_percent"))),
+			VRAMUsedBytes:  readSysfsUint(filepath.Join(dev.path, "mem_info_vram_used")),
+			VRAMTotalBytes: readSysfsUint(filepath.Join(dev.path, "mem_info_vram_total")),
+		}
+		if state.Name == "" {
+			state.Name = "AMD GPU (card" + strconv.Itoa(dev.card) + ")"
+		}
+
+		// hwmon reports temperature in millidegrees and power in microwatts
+		if hwmon := findHwmonDir(dev.path); hwmon != "" {
+			state.Temperature = int(readSysfsUint(filepath.Join(hwmon, "temp1_input")) / 1000)
+			state.PowerWatts = float64(readSysfsUint(filepath.Join(hwmon, "power1_average"))) / 1e6
+		}
+
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+
+func (m *AMDGPUMonitor) Available() bool {
+	return len(m.devices) > 0
+}
+
+func (m *AMDGPUMonitor) Close() error {
+	return nil
+}
+
+// discoverAMDDevices finds AMD cards under <sysfsRoot>/class/drm, ordered by card number.
+func discoverAMDDevices(sysfsRoot string) []amdDevice {
+	drmDir := filepath.Join(sysfsRoot, "class", "drm")
+
+	entries, err := os.ReadDir(drmDir)
+	if err != nil {
+		return nil
+	}
+
+	var devices []amdDevice
+	for _, entry := range entries {
+		match := drmCardRegex.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+
+		devicePath := filepath.Join(drmDir, entry.Name(), "device")
+		if readSysfsString(filepath.Join(devicePath, "vendor")) != amdVendorID {
+			continue
+		}
+
+		card, _ := strconv.Atoi(match[1])
+		devices = append(devices, amdDevice{card: card, path: devicePath})
+	}
+
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].card < devices[j].card
+	})
+
+	return devices
+}
+
+// findHwmonDir returns the first hwmon directory of a device, or "" if none.
+func findHwmonDir(devicePath string) string {
+	matches, err := filepath.Glob(filepath.Join(devicePath, "hwmon", "hwmon*"))
+	if err != nil || len(matches) == 0 {
+		return ""
+	}
+	sort.Strings(matches)
+	return matches[0]
+}
+
+// readSysfsString reads a sysfs attribute, returning "" if it cannot be read.
+func readSysfsString(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readSysfsUint reads a numeric sysfs attribute, returning 0 if it cannot be read.
+func readSysfsUint(path string) uint64 {
+	v, err := strconv.ParseUint(readSysfsString(path), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
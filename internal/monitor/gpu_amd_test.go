@@ -0,0 +1,209 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSysfsFile creates a sysfs attribute file with the given content.
+func writeSysfsFile(t *testing.T, path, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content+"\n"), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
+// fakeAMDSysfs builds a sysfs tree with one AMD card (card1), one non-AMD
+// card (card0) and a connector entry that must be ignored.
+func fakeAMDSysfs(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	drm := filepath.Join(root, "class", "drm")
+
+	intel := filepath.Join(drm, "card0", "device")
+	writeSysfsFile(t, filepath.Join(intel, "vendor"), "0x8086")
+
+	amd := filepath.Join(drm, "card1", "device")
+	writeSysfsFile(t, filepath.Join(amd, "vendor"), "0x1002")
+	writeSysfsFile(t, filepath.Join(amd, "product_name"), "Radeon RX 7900 XTX")
+	writeSysfsFile(t, filepath.Join(amd, "gpu_busy_percent"), "42")
+	writeSysfsFile(t, filepath.Join(amd, "mem_info_vram_used"), "4294967296")
+	writeSysfsFile(t, filepath.Join(amd, "mem_info_vram_total"), "25769803776")
+	writeSysfsFile(t, filepath.Join(amd, "hwmon", "hwmon3", "temp1_input"), "61000")
+	writeSysfsFile(t, filepath.Join(amd, "hwmon", "hwmon3", "power1_average"), "215000000")
+
+	writeSysfsFile(t, filepath.Join(drm, "card1-DP-1", "device", "vendor"), "0x1002")
+
+	return root
+}
+
+func TestAMDGPUMonitor_Name(t *testing.T) {
+	m := NewAMDGPUMonitor(t.TempDir())
+	if m.Name() != "gpu" {
+		t.Errorf("expected name 'gpu', got %s", m.Name())
+	}
+}
+
+func TestAMDGPUMonitor_Collect(t *testing.T) {
+	m := NewAMDGPUMonitor(fakeAMDSysfs(t))
+
+	if !m.Available() {
+		t.Fatal("expected monitor to be available")
+	}
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	states, ok := data.(GPUStates)
+	if !ok {
+		t.Fatalf("expected GPUStates, got %T", data)
+	}
+
+	if len(states) != 1 {
+		t.Fatalf("expected 1 GPU, got %d", len(states))
+	}
+
+	gpu := states[0]
+	if gpu.Index != 0 {
+		t.Errorf("expected index 0, got %d", gpu.Index)
+	}
+	if gpu.Name != "Radeon RX 7900 XTX" {
+		t.Errorf("expected name 'Radeon RX 7900 XTX', got %s", gpu.Name)
+	}
+	if gpu.UsagePercent != 42 {
+		t.Errorf("expected usage 42, got %f", gpu.UsagePercent)
+	}
+	if gpu.VRAMUsedBytes != 4294967296 {
+		t.Errorf("expected 4294967296 VRAM used, got %d", gpu.VRAMUsedBytes)
+	}
+	if gpu.VRAMTotalBytes != 25769803776 {
+		t.Errorf("expected 25769803776 VRAM total, got %d", gpu.VRAMTotalBytes)
+	}
+	if gpu.Temperature != 61 {
+		t.Errorf("expected temperature 61, got %d", gpu.Temperature)
+	}
+	if gpu.PowerWatts != 215 {
+		t.Errorf("expected power 215W, got %f", gpu.PowerWatts)
+	}
+}
+
+func TestAMDGPUMonitor_MissingAttributes(t *testing.T) {
+	root := t.TempDir()
+	writeSysfsFile(t, filepath.Join(root, "class", "drm", "card2", "device", "vendor"), "0x1002")
+
+	m := NewAMDGPUMonitor(root)
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	states := data.(GPUStates)
+	if len(states) != 1 {
+		t.Fatalf("expected 1 GPU, got %d", len(states))
+	}
+
+	if states[0].Name != "AMD GPU (card2)" {
+		t.Errorf("expected fallback name, got %s", states[0].Name)
+	}
+	if states[0].UsagePercent != 0 || states[0].Temperature != 0 {
+		t.Errorf("expected zero metrics, got %+v", states[0])
+	}
+}
+
+func TestAMDGPUMonitor_GracefulDegradation(t *testing.T) {
+	m := NewAMDGPUMonitor("/nonexistent/sys")
+
+	if m.Available() {
+		t.Fatal("expected monitor to be unavailable without sysfs")
+	}
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("collect should not fail: %v", err)
+	}
+
+	if states := data.(GPUStates); len(states) != 0 {
+		t.Errorf("expected empty slice, got %d", len(states))
+	}
+}
+
+func TestNewGPUBackend(t *testing.T) {
+	sysfs := fakeAMDSysfs(t)
+	noSMI := "/nonexistent/nvidia-smi"
+
+	tests := []struct {
+		name     string
+		cfg      GPUBackendConfig
+		wantNil  bool
+		wantType string
+	}{
+		{"none", GPUBackendConfig{Backend: GPUBackendNone}, true, ""},
+		{"nvidia", GPUBackendConfig{Backend: GPUBackendNvidia, NvidiaSMIPath: noSMI}, false, "nvidia"},
+		{"amd", GPUBackendConfig{Backend: GPUBackendAMD, SysfsRoot: sysfs}, false, "amd"},
+		{"auto picks amd", GPUBackendConfig{Backend: GPUBackendAuto, NvidiaSMIPath: noSMI, SysfsRoot: sysfs}, false, "amd"},
+		{"auto falls back to nvidia", GPUBackendConfig{Backend: GPUBackendAuto, NvidiaSMIPath: noSMI, SysfsRoot: t.TempDir()}, false, "nvidia"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := NewGPUBackend(tt.cfg)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if tt.wantNil {
+				if m != nil {
+					t.Errorf("expected nil monitor, got %T", m)
+				}
+				return
+			}
+
+			switch m.(type) {
+			case *GPUMonitor:
+				if tt.wantType != "nvidia" {
+					t.Errorf("expected %s backend, got nvidia", tt.wantType)
+				}
+			case *AMDGPUMonitor:
+				if tt.wantType != "amd" {
+					t.Errorf("expected %s backend, got amd", tt.wantType)
+				}
+			default:
+				t.Errorf("unexpected monitor type %T", m)
+			}
+		})
+	}
+}
+
+func TestNewGPUBackend_AutoPrefersNvidia(t *testing.T) {
+	smi := fakeNvidiaSMI(t, `echo "0, Tesla T4, 10, 100, 15360, 40, 30.0"`)
+
+	m, err := NewGPUBackend(GPUBackendConfig{
+		Backend:       GPUBackendAuto,
+		NvidiaSMIPath: smi,
+		Timeout:       time.Second,
+		SysfsRoot:     fakeAMDSysfs(t),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := m.(*GPUMonitor); !ok {
+		t.Errorf("expected nvidia backend, got %T", m)
+	}
+}
+
+func TestNewGPUBackend_Unknown(t *testing.T) {
+	if _, err := NewGPUBackend(GPUBackendConfig{Backend: "intel"}); err == nil {
+		t.Error("expected error for unknown backend")
+	}
+}
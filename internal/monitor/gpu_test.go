@@ -1,7 +1,10 @@
 package monitor
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestGPUMonitor_Name(t *testing.T) {
@@ -12,22 +15,25 @@ func TestGPUMonitor_Name(t *testing.T) {
 }
 
 func TestGPUMonitor_GracefulDegradation(t *testing.T) {
-	m := NewGPUMonitor()
+	m := NewGPUMonitorWithSMI("/nonexistent/nvidia-smi", time.Second)
+
+	if m.Available() {
+		t.Fatal("expected monitor to be unavailable without nvidia-smi")
+	}
 
-	// Even without NVML, should not fail
+	// Even without nvidia-smi, should not fail
 	data, err := m.Collect()
 	if err != nil {
 		t.Fatalf("collect should not fail: %v", err)
 	}
 
-	states, ok := data.([]GPUState)
+	states, ok := data.(GPUStates)
 	if !ok {
-		t.Fatalf("expected []GPUState, got %T", data)
+		t.Fatalf("expected GPUStates, got %T", data)
 	}
 
-	// Without NVML, should return empty slice
-	if !m.Available() && len(states) != 0 {
-		t.Errorf("expected empty slice when NVML not available, got %d", len(states))
+	if len(states) != 0 {
+		t.Errorf("expected empty slice when nvidia-smi not available, got %d", len(states))
 	}
 }
 
@@ -39,3 +45,120 @@ func TestGPUMonitor_Close(t *testing.T) {
 		t.Errorf("close should not fail: %v", err)
 	}
 }
+
+// fakeNvidiaSMI writes an executable script that prints the given output.
+func fakeNvidiaSMI(t *testing.T, script string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "nvidia-smi")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+script+"\n"), 0755); err != nil {
+		t.Fatalf("failed to write fake nvidia-smi: %v", err)
+	}
+	return path
+}
+
+func TestGPUMonitor_CollectFromNvidiaSMI(t *testing.T) {
+	path := fakeNvidiaSMI(t, `cat <<'CSV'
+0, 45, 8192, 24564, 61, 215.30, NVIDIA GeForce RTX 4090
+1, 0, 0, 81920, 34, [N/A], NVIDIA A100-SXM4-80GB
+CSV`)
+
+	m := NewGPUMonitorWithSMI(path, time.Second)
+	if !m.Available() {
+		t.Fatal("expected monitor to be available")
+	}
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	states := data.(GPUStates)
+	if len(states) != 2 {
+		t.Fatalf("expected 2 GPUs, got %d", len(states))
+	}
+
+	gpu := states[0]
+	if gpu.Index != 0 || gpu.Name != "NVIDIA GeForce RTX 4090" {
+		t.Errorf("unexpected GPU identity: %d %q", gpu.Index, gpu.Name)
+	}
+	if gpu.UsagePercent != 45 {
+		t.Errorf("expected usage 45, got %f", gpu.UsagePercent)
+	}
+	if gpu.VRAMUsedBytes != 8192*1024*1024 {
+		t.Errorf("expected 8192 MiB used, got %d bytes", gpu.VRAMUsedBytes)
+	}
+	if gpu.VRAMTotalBytes != 24564*1024*1024 {
+		t.Errorf("expected 24564 MiB total, got %d bytes", gpu.VRAMTotalBytes)
+	}
+	if gpu.Temperature != 61 {
+		t.Errorf("expected temperature 61, got %d", gpu.Temperature)
+	}
+	if gpu.PowerWatts != 215.30 {
+		t.Errorf("expected power 215.30, got %f", gpu.PowerWatts)
+	}
+
+	// Unsupported fields are reported as zero
+	if states[1].Index != 1 || states[1].PowerWatts != 0 {
+		t.Errorf("unexpected second GPU: %+v", states[1])
+	}
+}
+
+func TestGPUMonitor_NvidiaSMIFailure(t *testing.T) {
+	path := fakeNvidiaSMI(t, `echo "NVIDIA-SMI has failed" >&2; exit 9`)
+
+	m := NewGPUMonitorWithSMI(path, time.Second)
+
+	if _, err := m.Collect(); err == nil {
+		t.Error("expected error when nvidia-smi exits non-zero")
+	}
+}
+
+func TestGPUMonitor_NvidiaSMITimeout(t *testing.T) {
+	path := fakeNvidiaSMI(t, `sleep 5`)
+
+	m := NewGPUMonitorWithSMI(path, 100*time.Millisecond)
+
+	start := time.Now()
+	_, err := m.Collect()
+	if err == nil {
+		t.Fatal("expected timeout error")
+	}
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("collect took %s, expected to be bounded by timeout", elapsed)
+	}
+}
+
+func TestParseNvidiaSMIOutput_Malformed(t *testing.T) {
+	if _, err := parseNvidiaSMIOutput("0, 10, GPU\n"); err == nil {
+		t.Error("expected error for wrong field count")
+	}
+
+	if _, err := parseNvidiaSMIOutput("x, 1, 2, 3, 4, 5, GPU\n"); err == nil {
+		t.Error("expected error for invalid index")
+	}
+
+	states, err := parseNvidiaSMIOutput("\n")
+	if err != nil || len(states) != 0 {
+		t.Errorf("expected empty result for blank output, got %v, %v", states, err)
+	}
+}
+
+func TestParseNvidiaSMIOutput_CommaInName(t *testing.T) {
+	states, err := parseNvidiaSMIOutput("0, 30, 1024, 16384, 55, 70.5, NVIDIA RTX A4000, Laptop GPU\n")
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if len(states) != 1 {
+		t.Fatalf("expected 1 GPU, got %d", len(states))
+	}
+
+	gpu := states[0]
+	if gpu.Name != "NVIDIA RTX A4000, Laptop GPU" {
+		t.Errorf("expected the full name, got %q", gpu.Name)
+	}
+	if gpu.UsagePercent != 30 || gpu.Temperature != 55 || gpu.PowerWatts != 70.5 {
+		t.Errorf("expected fields before the name to be intact, got %+v", gpu)
+	}
+}
@@ -0,0 +1,54 @@
+package monitor
+
+import (
+	"sort"
+	"time"
+)
+
+// DefaultCollectTimeout bounds a single Collect call when no timeout is configured.
+const DefaultCollectTimeout = 5 * time.Second
+
+// MonitorHealth tracks the collection status of one monitor.
+type MonitorHealth struct {
+	// Section is the part of the state the monitor populates
+	Section string `json:"section,omitempty"`
+	// Stale is set when the latest collection failed or timed out and the
+	// monitor's section holds the last good value, or nothing if there is none
+	Stale               bool      `json:"stale"`
+	ConsecutiveFailures int       `json:"consecutive_failures"`
+	LastError           string    `json:"last_error,omitempty"`
+	LastSuccess         time.Time `json:"last_success,omitempty"`
+}
+
+// HealthState maps a monitor name to its health.
+type HealthState map[string]MonitorHealth
+
+// Failing returns the sorted names of monitors with at least the given
+// number of consecutive failures.
+func (h HealthState) Failing(failures int) []string {
+	var names []string
+	for name, health := range h {
+		if health.ConsecutiveFailures >= failures && health.ConsecutiveFailures > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// collectResult is the outcome of one Collect call.
+type collectResult struct {
+	data Sample
+	err  error
+}
+
+// startCollect runs Collect in its own goroutine. The buffered channel lets
+// a monitor that outlives its deadline finish without blocking.
+func startCollect(m Monitor) chan collectResult {
+	ch := make(chan collectResult, 1)
+	go func() {
+		data, err := m.Collect()
+		ch <- collectResult{data: data, err: err}
+	}()
+	return ch
+}
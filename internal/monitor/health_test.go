@@ -0,0 +1,141 @@
+package monitor
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// scriptedMonitor returns the results of collect, which may block.
+type scriptedMonitor struct {
+	name    string
+	calls   atomic.Int32
+	collect func(call int) (Sample, error)
+}
+
+func (m *scriptedMonitor) Name() string {
+	return m.name
+}
+
+func (m *scriptedMonitor) Section() string {
+	return m.name
+}
+
+func (m *scriptedMonitor) Collect() (Sample, error) {
+	return m.collect(int(m.calls.Add(1)))
+}
+
+func TestHealthState_Failing(t *testing.T) {
+	health := HealthState{
+		"cpu":     {},
+		"storage": {Stale: true, ConsecutiveFailures: 3},
+		"gpu":     {Stale: true, ConsecutiveFailures: 1},
+	}
+
+	if got := health.Failing(1); len(got) != 2 || got[0] != "gpu" || got[1] != "storage" {
+		t.Errorf("expected [gpu storage], got %v", got)
+	}
+
+	if got := health.Failing(3); len(got) != 1 || got[0] != "storage" {
+		t.Errorf("expected [storage], got %v", got)
+	}
+
+	if got := health.Failing(0); len(got) != 2 {
+		t.Errorf("expected healthy monitors to be excluded, got %v", got)
+	}
+}
+
+func TestAggregator_CollectTimeout(t *testing.T) {
+	release := make(chan struct{})
+	var once sync.Once
+	defer once.Do(func() { close(release) })
+
+	storage := &scriptedMonitor{
+		name: "storage",
+		collect: func(call int) (Sample, error) {
+			if call > 1 {
+				// Hangs like disk.Usage on a stale NFS mount
+				<-release
+			}
+			return StorageState{"/": {UsedBytes: 10, TotalBytes: 100}}, nil
+		},
+	}
+	monitors := []Monitor{
+		&mockMonitor{name: "cpu", data: &CPUState{UsagePercent: 50}},
+		storage,
+	}
+
+	agg := NewAggregator(monitors, time.Second, testLogger())
+	agg.SetTimeouts(time.Second, map[string]time.Duration{"storage": 20 * time.Millisecond})
+
+	agg.collect()
+	if health := agg.Health()["storage"]; health.Stale || health.LastSuccess.IsZero() {
+		t.Fatalf("expected healthy storage after first collection, got %+v", health)
+	}
+
+	for range 2 {
+		started := time.Now()
+		agg.collect()
+		if elapsed := time.Since(started); elapsed > 500*time.Millisecond {
+			t.Fatalf("collection blocked on hung monitor for %s", elapsed)
+		}
+	}
+
+	state := agg.GetState()
+	if state.CPU.UsagePercent != 50 {
+		t.Errorf("expected fresh CPU data, got %f", state.CPU.UsagePercent)
+	}
+	if state.Storage["/"].TotalBytes != 100 {
+		t.Errorf("expected last good storage data, got %+v", state.Storage)
+	}
+
+	health := state.Health["storage"]
+	if !health.Stale || health.ConsecutiveFailures != 2 {
+		t.Errorf("expected stale storage with 2 failures, got %+v", health)
+	}
+	if health.LastError != "collection timed out after 20ms" {
+		t.Errorf("unexpected last error: %q", health.LastError)
+	}
+	if state.Health["cpu"].Stale {
+		t.Errorf("expected healthy cpu, got %+v", state.Health["cpu"])
+	}
+
+	// The hung call is awaited instead of starting another one
+	if calls := storage.calls.Load(); calls != 2 {
+		t.Errorf("expected 2 Collect calls while hung, got %d", calls)
+	}
+
+	once.Do(func() { close(release) })
+	agg.collect()
+	if health := agg.Health()["storage"]; health.Stale || health.ConsecutiveFailures != 0 || health.LastError != "" {
+		t.Errorf("expected storage to recover, got %+v", health)
+	}
+}
+
+func TestAggregator_CollectError(t *testing.T) {
+	gpu := &scriptedMonitor{
+		name: "gpu",
+		collect: func(call int) (Sample, error) {
+			if call == 1 {
+				return GPUStates{{Index: 0, UsagePercent: 30}}, nil
+			}
+			return nil, errors.New("nvidia-smi: exit status 9")
+		},
+	}
+
+	agg := NewAggregator([]Monitor{gpu}, time.Second, testLogger())
+	agg.collect()
+	agg.collect()
+
+	state := agg.GetState()
+	if len(state.GPUs) != 1 || state.GPUs[0].UsagePercent != 30 {
+		t.Errorf("expected last good GPU data, got %+v", state.GPUs)
+	}
+
+	health := state.Health["gpu"]
+	if !health.Stale || health.ConsecutiveFailures != 1 || health.LastError != "nvidia-smi: exit status 9" {
+		t.Errorf("unexpected gpu health: %+v", health)
+	}
+}
@@ -0,0 +1,242 @@
+package monitor
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// historyMetric extracts one scalar series from a snapshot.
+type historyMetric struct {
+	name  string
+	value func(s *SystemState) float64
+}
+
+// historyMetrics are the series recorded by History, in output order.
+var historyMetrics = []historyMetric{
+	{"cpu", func(s *SystemState) float64 { return s.CPU.UsagePercent }},
+	{"memory", func(s *SystemState) float64 { return s.Memory.UsagePercent }},
+	{"memory_used_from_available", func(s *SystemState) float64 { return s.Memory.UsedPercentFromAvailable() }},
+	{"swap", func(s *SystemState) float64 { return s.Swap.UsagePercent }},
+	{"swap_pages", func(s *SystemState) float64 { return s.Swap.PagesPerSec() }},
+	{"gpu", func(s *SystemState) float64 {
+		var busiest float64
+		for _, gpu := range s.GPUs {
+			busiest = max(busiest, gpu.UsagePercent)
+		}
+		return busiest
+	}},
+	{"vram", func(s *SystemState) float64 {
+		var fullest float64
+		for _, gpu := range s.GPUs {
+			fullest = max(fullest, gpu.VRAMPercent())
+		}
+		return fullest
+	}},
+	{"io_util", func(s *SystemState) float64 { return s.IO.MaxUtilPercent() }},
+	{"network", func(s *SystemState) float64 { return s.Network.MaxPercent() }},
+	{"temperature", func(s *SystemState) float64 { return s.Thermal.MaxCelsius() }},
+	{"cpu_pressure", func(s *SystemState) float64 { return s.Pressure.CPU.Some.Avg10 }},
+	{"memory_pressure", func(s *SystemState) float64 { return s.Pressure.Memory.Some.Avg10 }},
+	{"io_pressure", func(s *SystemState) float64 { return s.Pressure.IO.Some.Avg10 }},
+	{"procs_running", func(s *SystemState) float64 { return float64(s.ProcsRunning) }},
+	{"procs_blocked", func(s *SystemState) float64 { return float64(s.ProcsBlocked) }},
+}
+
+// HistoryMetricNames returns the names of the recorded series.
+func HistoryMetricNames() []string {
+	names := make([]string, len(historyMetrics))
+	for i, m := range historyMetrics {
+		names[i] = m.name
+	}
+	return names
+}
+
+// History is a fixed-size ring buffer of past snapshots. Instead of cloning
+// SystemState, each sample is reduced to a timestamp and one float32 per
+// metric stored column-wise, under 100 bytes per sample.
+type History struct {
+	mu     sync.RWMutex
+	times  []int64     // unix milliseconds
+	values [][]float32 // values[metric][slot]
+	next   int
+	size   int
+}
+
+// NewHistory creates a history holding up to capacity samples.
+func NewHistory(capacity int) *History {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	values := make([][]float32, len(historyMetrics))
+	for i := range values {
+		values[i] = make([]float32, capacity)
+	}
+
+	return &History{
+		times:  make([]int64, capacity),
+		values: values,
+	}
+}
+
+// Capacity returns the maximum number of samples kept.
+func (h *History) Capacity() int {
+	return len(h.times)
+}
+
+// Len returns the number of samples currently kept.
+func (h *History) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return h.size
+}
+
+// Record appends a snapshot, overwriting the oldest sample when full.
+func (h *History) Record(state *SystemState) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.times[h.next] = state.Timestamp.UnixMilli()
+	for i, m := range historyMetrics {
+		h.values[i][h.next] = float32(m.value(state))
+	}
+
+	h.next = (h.next + 1) % len(h.times)
+	if h.size < len(h.times) {
+		h.size++
+	}
+}
+
+// HistoryQuery selects samples from a History.
+type HistoryQuery struct {
+	// From and To bound the samples, inclusive. Zero means unbounded.
+	From time.Time
+	To   time.Time
+	// Step averages samples into buckets of this width starting at From
+	// (or the first sample). Zero returns raw samples.
+	Step time.Duration
+	// Metrics selects series by name. Empty means all.
+	Metrics []string
+}
+
+// HistoryResult holds series sharing one timestamp axis.
+type HistoryResult struct {
+	// Timestamps are unix milliseconds, the bucket start when downsampled
+	Timestamps []int64              `json:"timestamps"`
+	StepMS     int64                `json:"step_ms,omitempty"`
+	Series     map[string][]float64 `json:"series"`
+}
+
+// Query returns the samples within the query range. Returns an error for
+// unknown metric names.
+func (h *History) Query(q HistoryQuery) (*HistoryResult, error) {
+	indices, err := metricIndices(q.Metrics)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	result := &HistoryResult{
+		Timestamps: []int64{},
+		StepMS:     q.Step.Milliseconds(),
+		Series:     make(map[string][]float64, len(indices)),
+	}
+	for _, idx := range indices {
+		result.Series[historyMetrics[idx].name] = []float64{}
+	}
+
+	var from, to int64 = 0, 1<<63 - 1
+	if !q.From.IsZero() {
+		from = q.From.UnixMilli()
+	}
+	if !q.To.IsZero() {
+		to = q.To.UnixMilli()
+	}
+	step := q.Step.Milliseconds()
+
+	// Accumulates the samples of the current bucket
+	var bucket int64
+	var count int
+	anchored := !q.From.IsZero()
+	sums := make([]float64, len(indices))
+	flush := func() {
+		if count == 0 {
+			return
+		}
+		result.Timestamps = append(result.Timestamps, bucket)
+		for i, idx := range indices {
+			name := historyMetrics[idx].name
+			result.Series[name] = append(result.Series[name], compactFloat(sums[i]/float64(count)))
+			sums[i] = 0
+		}
+		count = 0
+	}
+
+	start := (h.next - h.size + len(h.times)) % len(h.times)
+	for n := 0; n < h.size; n++ {
+		slot := (start + n) % len(h.times)
+		ts := h.times[slot]
+		if ts < from || ts > to {
+			continue
+		}
+
+		if step > 0 {
+			if !anchored {
+				from, anchored = ts, true
+			}
+			if b := from + (ts-from)/step*step; b != bucket {
+				flush()
+				bucket = b
+			}
+		} else {
+			flush()
+			bucket = ts
+		}
+
+		for i, idx := range indices {
+			sums[i] += float64(h.values[idx][slot])
+		}
+		count++
+	}
+	flush()
+
+	return result, nil
+}
+
+// metricIndices resolves metric names to historyMetrics indices.
+func metricIndices(names []string) ([]int, error) {
+	if len(names) == 0 {
+		indices := make([]int, len(historyMetrics))
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices, nil
+	}
+
+	indices := make([]int, 0, len(names))
+	for _, name := range names {
+		found := false
+		for i, m := range historyMetrics {
+			if m.name == name {
+				indices = append(indices, i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown metric: %s", name)
+		}
+	}
+	return indices, nil
+}
+
+// compactFloat rounds to float32 precision, so values read back from the
+// buffer serialize as 45.3 rather than 45.29999923706055.
+func compactFloat(v float64) float64 {
+	f, _ := strconv.ParseFloat(strconv.FormatFloat(v, 'g', -1, 32), 64)
+	return f
+}
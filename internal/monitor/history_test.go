@@ -0,0 +1,99 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+// recordCPU records one sample per second starting at base with the given CPU usage.
+func recordCPU(h *History, base time.Time, values ...float64) {
+	for i, v := range values {
+		h.Record(&SystemState{
+			CPU:       CPUState{UsagePercent: v},
+			Memory:    MemoryState{UsagePercent: 50},
+			Timestamp: base.Add(time.Duration(i) * time.Second),
+		})
+	}
+}
+
+func TestHistory_RingBuffer(t *testing.T) {
+	h := NewHistory(3)
+	base := time.Unix(1000, 0)
+	recordCPU(h, base, 10, 20, 30, 40, 50)
+
+	if h.Len() != 3 || h.Capacity() != 3 {
+		t.Fatalf("expected 3 of 3 samples, got %d of %d", h.Len(), h.Capacity())
+	}
+
+	result, err := h.Query(HistoryQuery{Metrics: []string{"cpu"}})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+
+	// Oldest samples are overwritten, order is chronological
+	want := []float64{30, 40, 50}
+	if len(result.Series["cpu"]) != len(want) {
+		t.Fatalf("expected %v, got %v", want, result.Series["cpu"])
+	}
+	for i, v := range want {
+		if result.Series["cpu"][i] != v {
+			t.Errorf("sample %d: expected %f, got %f", i, v, result.Series["cpu"][i])
+		}
+	}
+	if result.Timestamps[0] != base.Add(2*time.Second).UnixMilli() {
+		t.Errorf("unexpected first timestamp %d", result.Timestamps[0])
+	}
+	if _, ok := result.Series["memory"]; ok {
+		t.Error("expected only the requested metric")
+	}
+}
+
+func TestHistory_QueryRangeAndStep(t *testing.T) {
+	h := NewHistory(100)
+	base := time.Unix(1000, 0)
+	recordCPU(h, base, 10, 20, 30, 40, 50, 60, 70)
+
+	result, err := h.Query(HistoryQuery{
+		From:    base.Add(time.Second),
+		To:      base.Add(5 * time.Second),
+		Step:    2 * time.Second,
+		Metrics: []string{"cpu", "memory"},
+	})
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+
+	// Buckets start at From: [20, 30], [40, 50], [60]
+	want := []float64{25, 45, 60}
+	got := result.Series["cpu"]
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i, v := range want {
+		if got[i] != v {
+			t.Errorf("bucket %d: expected %f, got %f", i, v, got[i])
+		}
+	}
+	if result.Timestamps[1] != base.Add(3*time.Second).UnixMilli() {
+		t.Errorf("expected bucket start at +3s, got %d", result.Timestamps[1])
+	}
+	if len(result.Series["memory"]) != 3 || result.StepMS != 2000 {
+		t.Errorf("unexpected result %+v", result)
+	}
+}
+
+func TestHistory_CompactValues(t *testing.T) {
+	h := NewHistory(1)
+	recordCPU(h, time.Unix(1000, 0), 45.3)
+
+	result, _ := h.Query(HistoryQuery{Metrics: []string{"cpu"}})
+	if result.Series["cpu"][0] != 45.3 {
+		t.Errorf("expected 45.3, got %v", result.Series["cpu"][0])
+	}
+}
+
+func TestHistory_UnknownMetric(t *testing.T) {
+	if _, err := NewHistory(1).Query(HistoryQuery{Metrics: []string{"bogus"}}); err == nil {
+		t.Error("expected error for unknown metric")
+	}
+}
@@ -14,15 +14,25 @@ func (m *MemoryMonitor) Name() string {
 	return "memory"
 }
 
-func (m *MemoryMonitor) Collect() (any, error) {
+func (m *MemoryMonitor) Section() string {
+	return SectionMemory
+}
+
+func (m *MemoryMonitor) Collect() (Sample, error) {
 	v, err := mem.VirtualMemory()
 	if err != nil {
 		return nil, err
 	}
 
 	return &MemoryState{
-		UsedBytes:    v.Used,
-		TotalBytes:   v.Total,
-		UsagePercent: v.UsedPercent,
+		UsedBytes:            v.Used,
+		TotalBytes:           v.Total,
+		UsagePercent:         v.UsedPercent,
+		AvailableBytes:       v.Available,
+		AvailableKnown:       true,
+		CachedBytes:          v.Cached,
+		BuffersBytes:         v.Buffers,
+		ShmemBytes:           v.Shared,
+		SlabReclaimableBytes: v.Sreclaimable,
 	}, nil
 }
@@ -36,3 +36,51 @@ func TestMemoryMonitor_Collect(t *testing.T) {
 		t.Errorf("invalid memory usage percent: %f", state.UsagePercent)
 	}
 }
+
+func TestMemoryMonitor_CollectAvailable(t *testing.T) {
+	data, err := NewMemoryMonitor().Collect()
+	if err != nil {
+		t.Fatalf("failed to collect memory data: %v", err)
+	}
+
+	state := data.(*MemoryState)
+	if !state.AvailableKnown {
+		t.Error("expected available memory to be known")
+	}
+	if state.AvailableBytes > state.TotalBytes {
+		t.Errorf("available bytes (%d) should not exceed total (%d)", state.AvailableBytes, state.TotalBytes)
+	}
+
+	if p := state.Percent(MemoryModeAvailable); p < 0 || p > 100 {
+		t.Errorf("invalid available-mode percent: %f", p)
+	}
+}
+
+func TestMemoryState_Percent(t *testing.T) {
+	state := MemoryState{
+		TotalBytes:     1000,
+		UsedBytes:      300,
+		UsagePercent:   30,
+		AvailableBytes: 400, // e.g. shmem and unreclaimable slab are not in used
+		AvailableKnown: true,
+	}
+
+	if got := state.Percent(MemoryModeUsed); got != 30 {
+		t.Errorf("expected 30%% in used mode, got %f", got)
+	}
+	if got := state.Percent(MemoryModeAvailable); got != 60 {
+		t.Errorf("expected 60%% in available mode, got %f", got)
+	}
+
+	// A full host has nothing available, it is not unknown
+	state.AvailableBytes = 0
+	if got := state.Percent(MemoryModeAvailable); got != 100 {
+		t.Errorf("expected 100%% with nothing available, got %f", got)
+	}
+
+	// Without MemAvailable, available mode falls back to used
+	state.AvailableKnown = false
+	if got := state.Percent(MemoryModeAvailable); got != 30 {
+		t.Errorf("expected fallback to 30%%, got %f", got)
+	}
+}
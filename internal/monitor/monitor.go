@@ -2,9 +2,13 @@ package monitor
 
 import "time"
 
+// Monitor collects one resource. Section names the part of SystemState its
+// samples populate (see the Section constants); monitors of one resource,
+// such as the host and cgroup CPU monitors, share a section.
 type Monitor interface {
 	Name() string
-	Collect() (any, error)
+	Section() string
+	Collect() (Sample, error)
 }
 
 // Closer is an optional interface for monitors that need cleanup.
@@ -15,12 +19,65 @@ type Closer interface {
 type CPUState struct {
 	UsagePercent float64   `json:"usage_percent"`
 	Cores        []float64 `json:"cores"`
+	// LimitCores is the cgroup CPU quota in cores (cgroup mode only)
+	LimitCores float64 `json:"limit_cores,omitempty"`
 }
 
+// Memory accounting modes accepted by MemoryState.Percent.
+const (
+	// MemoryModeUsed counts memory as reported by the kernel's used figure
+	MemoryModeUsed = "used"
+	// MemoryModeAvailable counts everything not in MemAvailable as used,
+	// so reclaimable page cache and slab are treated as free
+	MemoryModeAvailable = "available"
+)
+
 type MemoryState struct {
 	UsedBytes    uint64  `json:"used_bytes"`
 	TotalBytes   uint64  `json:"total_bytes"`
 	UsagePercent float64 `json:"usage_percent"`
+	// AvailableBytes estimates memory that can be allocated without
+	// swapping (MemAvailable)
+	AvailableBytes uint64 `json:"available_bytes"`
+	// AvailableKnown is set by collectors that report AvailableBytes, which
+	// is then 0 on a full host rather than unknown
+	AvailableKnown       bool   `json:"available_known"`
+	CachedBytes          uint64 `json:"cached_bytes"`
+	BuffersBytes         uint64 `json:"buffers_bytes"`
+	ShmemBytes           uint64 `json:"shmem_bytes"`
+	SlabReclaimableBytes uint64 `json:"slab_reclaimable_bytes"`
+}
+
+// UsedPercentFromAvailable returns the share of memory in use according to
+// MemAvailable, or UsagePercent if availability is unknown.
+func (s MemoryState) UsedPercentFromAvailable() float64 {
+	if s.TotalBytes == 0 || !s.AvailableKnown {
+		return s.UsagePercent
+	}
+	return float64(s.TotalBytes-min(s.AvailableBytes, s.TotalBytes)) / float64(s.TotalBytes) * 100
+}
+
+// Percent returns memory usage in the given accounting mode (used or
+// available). Unknown modes fall back to used.
+func (s MemoryState) Percent(mode string) float64 {
+	if mode == MemoryModeAvailable {
+		return s.UsedPercentFromAvailable()
+	}
+	return s.UsagePercent
+}
+
+type SwapState struct {
+	UsedBytes         uint64  `json:"used_bytes"`
+	TotalBytes        uint64  `json:"total_bytes"`
+	UsagePercent      float64 `json:"usage_percent"`
+	PagesInPerSec     float64 `json:"pages_in_per_sec"`
+	PagesOutPerSec    float64 `json:"pages_out_per_sec"`
+	MajorFaultsPerSec float64 `json:"major_faults_per_sec"`
+}
+
+// PagesPerSec returns combined swap-in and swap-out paging.
+func (s SwapState) PagesPerSec() float64 {
+	return s.PagesInPerSec + s.PagesOutPerSec
 }
 
 type GPUState struct {
@@ -30,31 +87,267 @@ type GPUState struct {
 	Temperature    int     `json:"temperature"`
 	VRAMUsedBytes  uint64  `json:"vram_used_bytes"`
 	VRAMTotalBytes uint64  `json:"vram_total_bytes"`
+	PowerWatts     float64 `json:"power_watts,omitempty"`
+}
+
+// VRAMPercent returns VRAM usage, or 0 if the total is unknown.
+func (g GPUState) VRAMPercent() float64 {
+	if g.VRAMTotalBytes == 0 {
+		return 0
+	}
+	return float64(g.VRAMUsedBytes) / float64(g.VRAMTotalBytes) * 100
 }
 
 type DiskState struct {
 	UsedBytes    uint64  `json:"used_bytes"`
 	TotalBytes   uint64  `json:"total_bytes"`
 	UsagePercent float64 `json:"usage_percent"`
+	// InodesTotal is 0 on filesystems without a fixed inode table (btrfs, some FUSE)
+	InodesUsed  uint64 `json:"inodes_used"`
+	InodesTotal uint64 `json:"inodes_total"`
+	// ReadOnly is set when the mount or the filesystem itself is read-only
+	ReadOnly bool `json:"read_only"`
+	// Error is set when the path could not be queried, e.g. a missing mount
+	Error string `json:"error,omitempty"`
+}
+
+// FreeInodesPercent returns the share of free inodes, or 100 if the
+// filesystem does not report an inode count.
+func (d DiskState) FreeInodesPercent() float64 {
+	if d.InodesTotal == 0 {
+		return 100
+	}
+	return float64(d.InodesTotal-min(d.InodesUsed, d.InodesTotal)) / float64(d.InodesTotal) * 100
 }
 
 type StorageState map[string]DiskState
 
+// DeviceIOState holds throughput and utilization of one block device.
+type DeviceIOState struct {
+	ReadBytesPerSec  float64 `json:"read_bytes_per_sec"`
+	WriteBytesPerSec float64 `json:"write_bytes_per_sec"`
+	ReadIOPS         float64 `json:"read_iops"`
+	WriteIOPS        float64 `json:"write_iops"`
+	// UtilPercent is the share of time the device had I/O in flight
+	UtilPercent float64 `json:"util_percent"`
+}
+
+type IOState struct {
+	IOWaitPercent float64                  `json:"iowait_percent"`
+	Devices       map[string]DeviceIOState `json:"devices"`
+	// Paths maps each monitored path to the device it is mounted from
+	Paths map[string]string `json:"paths"`
+}
+
+// PathUtil returns the utilization of the device backing each monitored path.
+func (s IOState) PathUtil() map[string]float64 {
+	util := make(map[string]float64, len(s.Paths))
+	for path, name := range s.Paths {
+		util[path] = s.Devices[name].UtilPercent
+	}
+	return util
+}
+
+// MaxUtilPercent returns the utilization of the busiest monitored device.
+func (s IOState) MaxUtilPercent() float64 {
+	var busiest float64
+	for _, name := range s.Paths {
+		busiest = max(busiest, s.Devices[name].UtilPercent)
+	}
+	return busiest
+}
+
+// Throughput returns read and write bytes/s summed over the monitored
+// devices, counting a device once even if it backs several paths.
+func (s IOState) Throughput() (read, write float64) {
+	seen := make(map[string]bool, len(s.Paths))
+	for _, name := range s.Paths {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		read += s.Devices[name].ReadBytesPerSec
+		write += s.Devices[name].WriteBytesPerSec
+	}
+	return read, write
+}
+
+// InterfaceState holds throughput of one network interface.
+type InterfaceState struct {
+	RxBytesPerSec float64 `json:"rx_bytes_per_sec"`
+	TxBytesPerSec float64 `json:"tx_bytes_per_sec"`
+	// CapacityMbps is the configured capacity or link speed, 0 if unknown
+	CapacityMbps float64 `json:"capacity_mbps,omitempty"`
+	// RxPercent and TxPercent are throughput relative to capacity
+	RxPercent float64 `json:"rx_percent,omitempty"`
+	TxPercent float64 `json:"tx_percent,omitempty"`
+}
+
+// UsagePercent returns the busier direction, since links are full-duplex.
+func (s InterfaceState) UsagePercent() float64 {
+	return max(s.RxPercent, s.TxPercent)
+}
+
+type NetworkState map[string]InterfaceState
+
+// MaxPercent returns the usage of the busiest interface.
+func (s NetworkState) MaxPercent() float64 {
+	var busiest float64
+	for _, iface := range s {
+		busiest = max(busiest, iface.UsagePercent())
+	}
+	return busiest
+}
+
+// Throughput returns rx and tx bytes/s summed over all interfaces.
+func (s NetworkState) Throughput() (rx, tx float64) {
+	for _, iface := range s {
+		rx += iface.RxBytesPerSec
+		tx += iface.TxBytesPerSec
+	}
+	return rx, tx
+}
+
+// CoreFreqState holds the current and maximum frequency of one CPU.
+type CoreFreqState struct {
+	CPU    int     `json:"cpu"`
+	CurMHz float64 `json:"cur_mhz"`
+	MaxMHz float64 `json:"max_mhz"`
+}
+
+// ThermalState holds temperatures, CPU frequencies and throttle activity.
+type ThermalState struct {
+	// Sensors maps a sensor name to its temperature in Celsius
+	Sensors map[string]float64 `json:"sensors,omitempty"`
+	Cores   []CoreFreqState    `json:"cores,omitempty"`
+	// ThrottleCount is the cumulative number of thermal throttle events
+	ThrottleCount        uint64  `json:"throttle_count"`
+	ThrottleEventsPerSec float64 `json:"throttle_events_per_sec"`
+}
+
+// MaxCelsius returns the temperature of the hottest sensor.
+func (s ThermalState) MaxCelsius() float64 {
+	var hottest float64
+	for _, celsius := range s.Sensors {
+		hottest = max(hottest, celsius)
+	}
+	return hottest
+}
+
+// FreqPercent returns the average current frequency relative to the
+// maximum over all cores, or 0 if frequencies are unknown.
+func (s ThermalState) FreqPercent() float64 {
+	var cur, maxFreq float64
+	for _, core := range s.Cores {
+		if core.MaxMHz > 0 {
+			cur += core.CurMHz
+			maxFreq += core.MaxMHz
+		}
+	}
+	if maxFreq == 0 {
+		return 0
+	}
+	return cur / maxFreq * 100
+}
+
+// NUMANodeState holds the CPUs and memory of one NUMA node.
+type NUMANodeState struct {
+	ID   int   `json:"id"`
+	CPUs []int `json:"cpus"`
+	// UsedBytes excludes page cache and reclaimable slab, like MemoryState
+	UsedBytes  uint64 `json:"used_bytes"`
+	FreeBytes  uint64 `json:"free_bytes"`
+	TotalBytes uint64 `json:"total_bytes"`
+	// AvailableBytes estimates reclaimable plus free memory on the node
+	AvailableBytes uint64 `json:"available_bytes"`
+}
+
+// MemoryPercent returns node memory usage in the given accounting mode
+// (used or available), or 0 if the node has no memory.
+func (n NUMANodeState) MemoryPercent(mode string) float64 {
+	if n.TotalBytes == 0 {
+		return 0
+	}
+	used := n.UsedBytes
+	if mode == MemoryModeAvailable && n.AvailableBytes > 0 {
+		used = n.TotalBytes - min(n.AvailableBytes, n.TotalBytes)
+	}
+	return float64(used) / float64(n.TotalBytes) * 100
+}
+
+// NUMAState holds the NUMA topology and per-node memory.
+type NUMAState struct {
+	Nodes []NUMANodeState `json:"nodes,omitempty"`
+	// CoreNode maps a CPU index to its node, -1 if the CPU is not in any node
+	CoreNode []int `json:"core_node,omitempty"`
+}
+
 type ProcessState struct {
 	Processes             int   `json:"processes"`
 	Threads               int   `json:"threads"`
 	ContextSwitchesPerSec int64 `json:"context_switches_per_sec"`
+	InterruptsPerSec      int64 `json:"interrupts_per_sec"`
+	ProcsRunning          int   `json:"procs_running"`
+	ProcsBlocked          int   `json:"procs_blocked"`
 }
 
+// PressureStats holds PSI stall percentages averaged over 10s and 60s.
+type PressureStats struct {
+	Avg10 float64 `json:"avg10"`
+	Avg60 float64 `json:"avg60"`
+}
+
+// PressureResource holds PSI for one resource.
+// Some is the share of time at least one task stalled, Full is the share
+// of time all non-idle tasks stalled at once.
+type PressureResource struct {
+	Some PressureStats `json:"some"`
+	Full PressureStats `json:"full"`
+}
+
+// Window returns some/full pressure for the given averaging window
+// (avg10 or avg60). Unknown windows fall back to avg10.
+func (r PressureResource) Window(window string) (some, full float64) {
+	if window == PressureWindowAvg60 {
+		return r.Some.Avg60, r.Full.Avg60
+	}
+	return r.Some.Avg10, r.Full.Avg10
+}
+
+type PressureState struct {
+	CPU    PressureResource `json:"cpu"`
+	Memory PressureResource `json:"memory"`
+	IO     PressureResource `json:"io"`
+}
+
+// CustomState maps a plugin metric name to its latest value.
+type CustomState map[string]float64
+
+// Extensions holds the sections of monitors registered from outside this
+// package, keyed by section. Values are shared between clones.
+type Extensions map[string]any
+
 type SystemState struct {
-	CPU                   CPUState     `json:"cpu"`
-	Memory                MemoryState  `json:"memory"`
-	GPUs                  []GPUState   `json:"gpus"`
-	Storage               StorageState `json:"storage"`
-	Processes             int          `json:"processes"`
-	Threads               int          `json:"threads"`
-	ContextSwitchesPerSec int64        `json:"context_switches_per_sec"`
-	Timestamp             time.Time    `json:"timestamp"`
+	CPU                   CPUState      `json:"cpu"`
+	Memory                MemoryState   `json:"memory"`
+	Swap                  SwapState     `json:"swap"`
+	GPUs                  []GPUState    `json:"gpus"`
+	Storage               StorageState  `json:"storage"`
+	Processes             int           `json:"processes"`
+	Threads               int           `json:"threads"`
+	ContextSwitchesPerSec int64         `json:"context_switches_per_sec"`
+	InterruptsPerSec      int64         `json:"interrupts_per_sec"`
+	ProcsRunning          int           `json:"procs_running"`
+	ProcsBlocked          int           `json:"procs_blocked"`
+	Pressure              PressureState `json:"pressure"`
+	IO                    IOState       `json:"io"`
+	Network               NetworkState  `json:"network"`
+	Thermal               ThermalState  `json:"thermal"`
+	NUMA                  NUMAState     `json:"numa"`
+	Custom                CustomState   `json:"custom,omitempty"`
+	Health                HealthState   `json:"health,omitempty"`
+	Extensions            Extensions    `json:"extensions,omitempty"`
+	Timestamp             time.Time     `json:"timestamp"`
 }
 
 func (s *SystemState) Clone() *SystemState {
@@ -67,5 +360,74 @@ func (s *SystemState) Clone() *SystemState {
 	for k, v := range s.Storage {
 		clone.Storage[k] = v
 	}
+	if s.Network != nil {
+		clone.Network = make(NetworkState, len(s.Network))
+		for k, v := range s.Network {
+			clone.Network[k] = v
+		}
+	}
+	if s.Thermal.Sensors != nil {
+		clone.Thermal.Sensors = make(map[string]float64, len(s.Thermal.Sensors))
+		for k, v := range s.Thermal.Sensors {
+			clone.Thermal.Sensors[k] = v
+		}
+	}
+	clone.Thermal.Cores = append([]CoreFreqState(nil), s.Thermal.Cores...)
+	if s.NUMA.Nodes != nil {
+		clone.NUMA.Nodes = make([]NUMANodeState, len(s.NUMA.Nodes))
+		for i, node := range s.NUMA.Nodes {
+			node.CPUs = append([]int(nil), node.CPUs...)
+			clone.NUMA.Nodes[i] = node
+		}
+	}
+	clone.NUMA.CoreNode = append([]int(nil), s.NUMA.CoreNode...)
+	if s.Custom != nil {
+		clone.Custom = make(CustomState, len(s.Custom))
+		for k, v := range s.Custom {
+			clone.Custom[k] = v
+		}
+	}
+	if s.Health != nil {
+		clone.Health = make(HealthState, len(s.Health))
+		for k, v := range s.Health {
+			clone.Health[k] = v
+		}
+	}
+	if s.Extensions != nil {
+		clone.Extensions = make(Extensions, len(s.Extensions))
+		for k, v := range s.Extensions {
+			clone.Extensions[k] = v
+		}
+	}
+	if s.IO.Devices != nil {
+		clone.IO.Devices = make(map[string]DeviceIOState, len(s.IO.Devices))
+		for k, v := range s.IO.Devices {
+			clone.IO.Devices[k] = v
+		}
+	}
+	if s.IO.Paths != nil {
+		clone.IO.Paths = make(map[string]string, len(s.IO.Paths))
+		for k, v := range s.IO.Paths {
+			clone.IO.Paths[k] = v
+		}
+	}
 	return &clone
 }
+
+// NodeCPUPercent returns the average usage of the CPUs in a NUMA node.
+// Falls back to overall CPU usage if per-core usage does not cover the node.
+func (s *SystemState) NodeCPUPercent(node NUMANodeState) float64 {
+	var sum float64
+	var n int
+	for _, cpu := range node.CPUs {
+		if cpu < 0 || cpu >= len(s.CPU.Cores) {
+			return s.CPU.UsagePercent
+		}
+		sum += s.CPU.Cores[cpu]
+		n++
+	}
+	if n == 0 {
+		return s.CPU.UsagePercent
+	}
+	return sum / float64(n)
+}
@@ -0,0 +1,191 @@
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NetworkMonitorConfig holds network monitor settings.
+type NetworkMonitorConfig struct {
+	ProcRoot  string
+	SysfsRoot string
+	// Interfaces limits collection to the named interfaces.
+	// Empty means all physical interfaces.
+	Interfaces []string
+	// CapacityMbps overrides the link speed reported by sysfs per interface
+	CapacityMbps map[string]float64
+}
+
+// NetworkMonitor derives per-interface rx/tx throughput from /proc/net/dev.
+// Utilization is reported against the configured capacity, or the link
+// speed from /sys/class/net/<iface>/speed when none is configured.
+// Graceful degradation: if /proc/net/dev is missing, returns an empty state.
+type NetworkMonitor struct {
+	procRoot     string
+	sysfsRoot    string
+	interfaces   map[string]bool
+	capacityMbps map[string]float64
+
+	prev     map[string]netCounters
+	prevTime time.Time
+	mu       sync.Mutex
+}
+
+// netCounters holds the cumulative byte counters of one interface.
+type netCounters struct {
+	rxBytes uint64
+	txBytes uint64
+}
+
+// NewNetworkMonitor creates a network monitor.
+func NewNetworkMonitor(cfg NetworkMonitorConfig) *NetworkMonitor {
+	if cfg.ProcRoot == "" {
+		cfg.ProcRoot = DefaultProcRoot
+	}
+	if cfg.SysfsRoot == "" {
+		cfg.SysfsRoot = DefaultSysfsRoot
+	}
+
+	var interfaces map[string]bool
+	if len(cfg.Interfaces) > 0 {
+		interfaces = make(map[string]bool, len(cfg.Interfaces))
+		for _, name := range cfg.Interfaces {
+			interfaces[name] = true
+		}
+	}
+
+	return &NetworkMonitor{
+		procRoot:     cfg.ProcRoot,
+		sysfsRoot:    cfg.SysfsRoot,
+		interfaces:   interfaces,
+		capacityMbps: cfg.CapacityMbps,
+	}
+}
+
+func (m *NetworkMonitor) Name() string {
+	return "network"
+}
+
+func (m *NetworkMonitor) Section() string {
+	return SectionNetwork
+}
+
+func (m *NetworkMonitor) Collect() (Sample, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := make(NetworkState)
+
+	counters, err := readNetDev(filepath.Join(m.procRoot, "net", "dev"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	elapsed := now.Sub(m.prevTime).Seconds()
+	hasPrev := !m.prevTime.IsZero() && elapsed > 0
+
+	for name, cur := range counters {
+		if !m.monitored(name) {
+			continue
+		}
+
+		iface := InterfaceState{CapacityMbps: m.capacity(name)}
+		if prev, ok := m.prev[name]; ok && hasPrev {
+			iface.RxBytesPerSec = float64(counterRate(prev.rxBytes, cur.rxBytes, elapsed))
+			iface.TxBytesPerSec = float64(counterRate(prev.txBytes, cur.txBytes, elapsed))
+		}
+		if iface.CapacityMbps > 0 {
+			// Mbit/s to bytes/s
+			capacityBytes := iface.CapacityMbps * 1e6 / 8
+			iface.RxPercent = clampPercent(iface.RxBytesPerSec / capacityBytes * 100)
+			iface.TxPercent = clampPercent(iface.TxBytesPerSec / capacityBytes * 100)
+		}
+		state[name] = iface
+	}
+
+	m.prev = counters
+	m.prevTime = now
+
+	return state, nil
+}
+
+// monitored reports whether an interface is collected. Without an explicit
+// list, only physical interfaces (those with a device link in sysfs) are
+// collected, so traffic through bridges and veth pairs is not counted twice.
+// If sysfs is unavailable, every interface except loopback is collected.
+func (m *NetworkMonitor) monitored(name string) bool {
+	if m.interfaces != nil {
+		return m.interfaces[name]
+	}
+	if name == "lo" {
+		return false
+	}
+
+	classDir := filepath.Join(m.sysfsRoot, "class", "net")
+	if _, err := os.Stat(classDir); err != nil {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(classDir, name, "device"))
+	return err == nil
+}
+
+// capacity returns the interface capacity in Mbit/s, or 0 if unknown.
+// sysfs reports -1 or fails to read speed for links that are down.
+func (m *NetworkMonitor) capacity(name string) float64 {
+	if mbps, ok := m.capacityMbps[name]; ok {
+		return mbps
+	}
+	return float64(readSysfsUint(filepath.Join(m.sysfsRoot, "class", "net", name, "speed")))
+}
+
+// readNetDev parses rx/tx byte counters per interface from /proc/net/dev.
+func readNetDev(path string) (map[string]netCounters, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	counters := make(map[string]netCounters)
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		// "  eth0: rx_bytes rx_packets ... (8 rx fields) tx_bytes tx_packets ..."
+		name, rest, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(rest)
+		if len(fields) < 16 {
+			continue
+		}
+
+		name = strings.TrimSpace(name)
+		rx, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rx bytes for %s: %w", name, err)
+		}
+		tx, err := strconv.ParseUint(fields[8], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid tx bytes for %s: %w", name, err)
+		}
+
+		counters[name] = netCounters{rxBytes: rx, txBytes: tx}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return counters, nil
+}
@@ -0,0 +1,182 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeNetDev writes /proc/net/dev where eth0 and docker0 have the given
+// cumulative byte counters and lo is idle.
+func writeNetDev(t *testing.T, procRoot string, rx, tx uint64) {
+	t.Helper()
+
+	line := func(name string) string {
+		// rx: bytes packets errs drop fifo frame compressed multicast
+		// tx: bytes packets errs drop fifo colls carrier compressed
+		return fmt.Sprintf("%6s: %d 10 0 0 0 0 0 0 %d 10 0 0 0 0 0 0\n", name, rx, tx)
+	}
+
+	content := "Inter-|   Receive                            |  Transmit\n" +
+		" face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed\n" +
+		"    lo: 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0 0\n" +
+		line("eth0") + line("docker0")
+
+	writeSysfsFile(t, filepath.Join(procRoot, "net", "dev"), content)
+}
+
+// fakeSysfsNet builds a sysfs tree where eth0 is a physical 1 Gbit/s link
+// and docker0 is a bridge without a device link.
+func fakeSysfsNet(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	writeSysfsFile(t, filepath.Join(root, "class", "net", "eth0", "speed"), "1000\n")
+	if err := os.MkdirAll(filepath.Join(root, "class", "net", "eth0", "device"), 0755); err != nil {
+		t.Fatalf("failed to create device dir: %v", err)
+	}
+	writeSysfsFile(t, filepath.Join(root, "class", "net", "docker0", "speed"), "-1\n")
+
+	return root
+}
+
+func TestNetworkMonitor_Name(t *testing.T) {
+	m := NewNetworkMonitor(NetworkMonitorConfig{ProcRoot: t.TempDir()})
+	if m.Name() != "network" {
+		t.Errorf("expected name 'network', got %s", m.Name())
+	}
+}
+
+func TestNetworkMonitor_Collect(t *testing.T) {
+	procRoot := t.TempDir()
+	writeNetDev(t, procRoot, 0, 0)
+
+	m := NewNetworkMonitor(NetworkMonitorConfig{ProcRoot: procRoot, SysfsRoot: fakeSysfsNet(t)})
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("first collect failed: %v", err)
+	}
+
+	state, ok := data.(NetworkState)
+	if !ok {
+		t.Fatalf("expected NetworkState, got %T", data)
+	}
+	if len(state) != 1 {
+		t.Fatalf("expected only the physical interface, got %v", state)
+	}
+	if state["eth0"].CapacityMbps != 1000 {
+		t.Errorf("expected 1000 Mbit/s link speed, got %f", state["eth0"].CapacityMbps)
+	}
+	if state["eth0"].RxBytesPerSec != 0 {
+		t.Errorf("expected zero rate on first call, got %f", state["eth0"].RxBytesPerSec)
+	}
+
+	// Pretend the previous sample was taken one second ago:
+	// 62.5 MB received (50% of 1 Gbit/s), 12.5 MB sent (10%)
+	m.prevTime = time.Now().Add(-time.Second)
+	writeNetDev(t, procRoot, 62_500_000, 12_500_000)
+
+	data, err = m.Collect()
+	if err != nil {
+		t.Fatalf("second collect failed: %v", err)
+	}
+
+	eth0 := data.(NetworkState)["eth0"]
+	if eth0.RxBytesPerSec < 0.9*62_500_000 || eth0.RxBytesPerSec > 62_500_000 {
+		t.Errorf("expected ~62.5 MB/s rx, got %f", eth0.RxBytesPerSec)
+	}
+	if eth0.RxPercent < 45 || eth0.RxPercent > 50 {
+		t.Errorf("expected ~50%% rx, got %f", eth0.RxPercent)
+	}
+	if eth0.TxPercent < 9 || eth0.TxPercent > 10 {
+		t.Errorf("expected ~10%% tx, got %f", eth0.TxPercent)
+	}
+	if eth0.UsagePercent() != eth0.RxPercent {
+		t.Errorf("expected usage to follow the busier direction, got %f", eth0.UsagePercent())
+	}
+}
+
+func TestNetworkMonitor_ExplicitInterfaces(t *testing.T) {
+	procRoot := t.TempDir()
+	writeNetDev(t, procRoot, 0, 0)
+
+	m := NewNetworkMonitor(NetworkMonitorConfig{
+		ProcRoot:     procRoot,
+		SysfsRoot:    fakeSysfsNet(t),
+		Interfaces:   []string{"docker0"},
+		CapacityMbps: map[string]float64{"docker0": 100},
+	})
+
+	m.Collect()
+	m.prevTime = time.Now().Add(-time.Second)
+	writeNetDev(t, procRoot, 6_250_000, 0)
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	state := data.(NetworkState)
+	if _, ok := state["eth0"]; ok {
+		t.Error("expected eth0 to be skipped when not listed")
+	}
+
+	docker0, ok := state["docker0"]
+	if !ok {
+		t.Fatal("expected listed interface to be collected")
+	}
+	if docker0.CapacityMbps != 100 {
+		t.Errorf("expected configured capacity 100, got %f", docker0.CapacityMbps)
+	}
+	if docker0.RxPercent < 45 || docker0.RxPercent > 50 {
+		t.Errorf("expected ~50%% rx of configured capacity, got %f", docker0.RxPercent)
+	}
+}
+
+func TestNetworkMonitor_WithoutSysfs(t *testing.T) {
+	procRoot := t.TempDir()
+	writeNetDev(t, procRoot, 0, 0)
+
+	data, err := NewNetworkMonitor(NetworkMonitorConfig{ProcRoot: procRoot, SysfsRoot: t.TempDir()}).Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	state := data.(NetworkState)
+	if len(state) != 2 {
+		t.Errorf("expected all interfaces except lo, got %v", state)
+	}
+	if state["eth0"].CapacityMbps != 0 || state["eth0"].RxPercent != 0 {
+		t.Errorf("expected unknown capacity, got %+v", state["eth0"])
+	}
+}
+
+func TestNetworkMonitor_GracefulDegradation(t *testing.T) {
+	data, err := NewNetworkMonitor(NetworkMonitorConfig{ProcRoot: t.TempDir()}).Collect()
+	if err != nil {
+		t.Fatalf("collect should not fail without procfs: %v", err)
+	}
+
+	if state := data.(NetworkState); len(state) != 0 {
+		t.Errorf("expected empty network state, got %v", state)
+	}
+}
+
+func TestNetworkState_Aggregates(t *testing.T) {
+	state := NetworkState{
+		"eth0": {RxBytesPerSec: 100, TxBytesPerSec: 10, RxPercent: 30, TxPercent: 5},
+		"eth1": {RxBytesPerSec: 200, TxBytesPerSec: 20, RxPercent: 10, TxPercent: 70},
+	}
+
+	if got := state.MaxPercent(); got != 70 {
+		t.Errorf("expected max percent 70, got %f", got)
+	}
+
+	rx, tx := state.Throughput()
+	if rx != 300 || tx != 30 {
+		t.Errorf("expected 300/30 bytes/s, got %f/%f", rx, tx)
+	}
+}
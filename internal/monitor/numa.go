@@ -0,0 +1,148 @@
+package monitor
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// numaNodeRegex matches NUMA node directories (node0, node1, ...).
+var numaNodeRegex = regexp.MustCompile(`^node(\d+)$`)
+
+// NUMAMonitor reads per-node memory and CPU lists from
+// /sys/devices/system/node.
+// Graceful degradation: without NUMA support, returns an empty state.
+type NUMAMonitor struct {
+	sysfsRoot string
+}
+
+// NewNUMAMonitor creates a NUMA monitor reading from the given sysfs root.
+func NewNUMAMonitor(sysfsRoot string) *NUMAMonitor {
+	if sysfsRoot == "" {
+		sysfsRoot = DefaultSysfsRoot
+	}
+
+	return &NUMAMonitor{sysfsRoot: sysfsRoot}
+}
+
+func (m *NUMAMonitor) Name() string {
+	return "numa"
+}
+
+func (m *NUMAMonitor) Section() string {
+	return SectionNUMA
+}
+
+func (m *NUMAMonitor) Collect() (Sample, error) {
+	state := &NUMAState{}
+
+	dir := filepath.Join(m.sysfsRoot, "devices", "system", "node")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return state, nil
+	}
+
+	maxCPU := -1
+	for _, entry := range entries {
+		match := numaNodeRegex.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		id, _ := strconv.Atoi(match[1])
+		nodeDir := filepath.Join(dir, entry.Name())
+
+		node := NUMANodeState{
+			ID:   id,
+			CPUs: parseCPUList(readSysfsString(filepath.Join(nodeDir, "cpulist"))),
+		}
+		fillNodeMemory(&node, filepath.Join(nodeDir, "meminfo"))
+
+		for _, cpu := range node.CPUs {
+			maxCPU = max(maxCPU, cpu)
+		}
+		state.Nodes = append(state.Nodes, node)
+	}
+
+	sort.Slice(state.Nodes, func(i, j int) bool { return state.Nodes[i].ID < state.Nodes[j].ID })
+
+	if maxCPU >= 0 {
+		state.CoreNode = make([]int, maxCPU+1)
+		for i := range state.CoreNode {
+			state.CoreNode[i] = -1
+		}
+		for _, node := range state.Nodes {
+			for _, cpu := range node.CPUs {
+				state.CoreNode[cpu] = node.ID
+			}
+		}
+	}
+
+	return state, nil
+}
+
+// fillNodeMemory parses a node meminfo file. Lines look like
+// "Node 0 MemTotal:       32823440 kB".
+func fillNodeMemory(node *NUMANodeState, path string) {
+	f, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 4 || fields[0] != "Node" {
+			continue
+		}
+		v, err := strconv.ParseUint(fields[3], 10, 64)
+		if err != nil {
+			continue
+		}
+		if len(fields) > 4 && fields[4] == "kB" {
+			v *= 1024
+		}
+		values[strings.TrimSuffix(fields[2], ":")] = v
+	}
+
+	total := values["MemTotal"]
+	free := min(values["MemFree"], total)
+	reclaimable := values["FilePages"] + values["SReclaimable"]
+
+	node.TotalBytes = total
+	node.FreeBytes = free
+	node.UsedBytes = total - free - min(reclaimable, total-free)
+	node.AvailableBytes = min(free+values["Inactive(file)"]+values["SReclaimable"], total)
+}
+
+// parseCPUList parses a kernel CPU list such as "0-3,8-11" into indices.
+// Malformed ranges are skipped.
+func parseCPUList(list string) []int {
+	var cpus []int
+	for _, part := range strings.Split(list, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		lo, hi, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil {
+			continue
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil || last < first {
+				continue
+			}
+		}
+		for cpu := first; cpu <= last; cpu++ {
+			cpus = append(cpus, cpu)
+		}
+	}
+	return cpus
+}
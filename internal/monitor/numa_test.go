@@ -0,0 +1,120 @@
+package monitor
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+)
+
+// writeNodeMeminfo writes the meminfo of a NUMA node with the given sizes in kB.
+func writeNodeMeminfo(t *testing.T, root string, id int, total, free, filePages, inactiveFile, slabReclaimable uint64) {
+	t.Helper()
+
+	content := fmt.Sprintf("Node %[1]d MemTotal:       %[2]d kB\n"+
+		"Node %[1]d MemFree:        %[3]d kB\n"+
+		"Node %[1]d MemUsed:        %[4]d kB\n"+
+		"Node %[1]d Inactive(file): %[5]d kB\n"+
+		"Node %[1]d FilePages:      %[6]d kB\n"+
+		"Node %[1]d SReclaimable:   %[7]d kB\n"+
+		"Node %[1]d HugePages_Total:     0\n",
+		id, total, free, total-free, inactiveFile, filePages, slabReclaimable)
+
+	writeSysfsFile(t, filepath.Join(root, "devices", "system", "node", fmt.Sprintf("node%d", id), "meminfo"), content)
+}
+
+func TestNUMAMonitor_Name(t *testing.T) {
+	m := NewNUMAMonitor(t.TempDir())
+	if m.Name() != "numa" {
+		t.Errorf("expected name 'numa', got %s", m.Name())
+	}
+}
+
+func TestNUMAMonitor_Collect(t *testing.T) {
+	root := t.TempDir()
+	nodeDir := filepath.Join(root, "devices", "system", "node")
+
+	// node0: 1 GiB, 256 MiB free, 256 MiB page cache (128 MiB inactive), 64 MiB reclaimable slab
+	writeNodeMeminfo(t, root, 0, 1048576, 262144, 262144, 131072, 65536)
+	writeSysfsFile(t, filepath.Join(nodeDir, "node0", "cpulist"), "0-1,4-5")
+	writeNodeMeminfo(t, root, 1, 1048576, 786432, 0, 0, 0)
+	writeSysfsFile(t, filepath.Join(nodeDir, "node1", "cpulist"), "2-3")
+	// Not a node directory
+	writeSysfsFile(t, filepath.Join(nodeDir, "possible"), "0-1")
+
+	data, err := NewNUMAMonitor(root).Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	state, ok := data.(*NUMAState)
+	if !ok {
+		t.Fatalf("expected *NUMAState, got %T", data)
+	}
+	if len(state.Nodes) != 2 {
+		t.Fatalf("expected 2 nodes, got %+v", state.Nodes)
+	}
+
+	node0 := state.Nodes[0]
+	if node0.ID != 0 || fmt.Sprint(node0.CPUs) != "[0 1 4 5]" {
+		t.Errorf("unexpected node0 topology: %+v", node0)
+	}
+	if node0.TotalBytes != 1<<30 || node0.FreeBytes != 256<<20 {
+		t.Errorf("unexpected node0 memory: %+v", node0)
+	}
+	// used = total - free - page cache - reclaimable slab = 448 MiB
+	if node0.UsedBytes != 448<<20 {
+		t.Errorf("expected 448 MiB used, got %d", node0.UsedBytes)
+	}
+	// available = free + inactive file + reclaimable slab = 448 MiB
+	if node0.AvailableBytes != 448<<20 {
+		t.Errorf("expected 448 MiB available, got %d", node0.AvailableBytes)
+	}
+	if got := node0.MemoryPercent(MemoryModeAvailable); got < 56 || got > 56.5 {
+		t.Errorf("expected ~56.25%% in available mode, got %f", got)
+	}
+
+	if fmt.Sprint(state.CoreNode) != "[0 0 1 1 0 0]" {
+		t.Errorf("unexpected core to node map: %v", state.CoreNode)
+	}
+}
+
+func TestNUMAMonitor_GracefulDegradation(t *testing.T) {
+	data, err := NewNUMAMonitor(t.TempDir()).Collect()
+	if err != nil {
+		t.Fatalf("collect should not fail without NUMA support: %v", err)
+	}
+
+	state := data.(*NUMAState)
+	if len(state.Nodes) != 0 || state.CoreNode != nil {
+		t.Errorf("expected empty NUMA state, got %+v", state)
+	}
+}
+
+func TestParseCPUList(t *testing.T) {
+	tests := []struct {
+		list string
+		want string
+	}{
+		{"", "[]"},
+		{"3", "[3]"},
+		{"0-3,8-9", "[0 1 2 3 8 9]"},
+		{"0-1,x,5-4,7", "[0 1 7]"},
+	}
+
+	for _, tt := range tests {
+		if got := fmt.Sprint(parseCPUList(tt.list)); got != tt.want {
+			t.Errorf("parseCPUList(%q) = %s, want %s", tt.list, got, tt.want)
+		}
+	}
+}
+
+func TestSystemState_NodeCPUPercent(t *testing.T) {
+	state := &SystemState{CPU: CPUState{UsagePercent: 50, Cores: []float64{10, 30, 90, 70}}}
+
+	if got := state.NodeCPUPercent(NUMANodeState{CPUs: []int{2, 3}}); got != 80 {
+		t.Errorf("expected 80%% node usage, got %f", got)
+	}
+	if got := state.NodeCPUPercent(NUMANodeState{CPUs: []int{3, 8}}); got != 50 {
+		t.Errorf("expected fallback to overall usage, got %f", got)
+	}
+}
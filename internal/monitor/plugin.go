@@ -0,0 +1,129 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// PluginMonitorPrefix starts the monitor name of every plugin, e.g. "plugin:render".
+	PluginMonitorPrefix = "plugin:"
+	// DefaultPluginTimeout bounds a single plugin command.
+	DefaultPluginTimeout = 5 * time.Second
+)
+
+// PluginConfig holds the settings of one exec-based plugin.
+type PluginConfig struct {
+	Name string
+	// Command is the executable and its arguments, run without a shell
+	Command []string
+	// Interval is the minimum time between runs; 0 runs on every collection
+	Interval time.Duration
+	// Timeout bounds a single run
+	Timeout time.Duration
+}
+
+// PluginMonitor runs an external command that prints a JSON object of
+// numeric metrics, e.g. {"render_backlog": 42, "license_seats_free": 3}.
+// Between runs the previous values are reported. A command that fails,
+// times out or prints anything else fails the collection.
+type PluginMonitor struct {
+	cfg PluginConfig
+
+	values  CustomState
+	lastRun time.Time
+	mu      sync.Mutex
+}
+
+// NewPluginMonitor creates a plugin monitor.
+func NewPluginMonitor(cfg PluginConfig) *PluginMonitor {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultPluginTimeout
+	}
+
+	return &PluginMonitor{cfg: cfg}
+}
+
+func (m *PluginMonitor) Name() string {
+	return PluginMonitorPrefix + m.cfg.Name
+}
+
+func (m *PluginMonitor) Section() string {
+	return SectionCustom
+}
+
+func (m *PluginMonitor) Collect() (Sample, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if m.values != nil && now.Sub(m.lastRun) < m.cfg.Interval {
+		return m.values, nil
+	}
+
+	values, err := m.run()
+	if err != nil {
+		return nil, err
+	}
+
+	m.values = values
+	m.lastRun = now
+	return values, nil
+}
+
+// run executes the command and parses its output.
+func (m *PluginMonitor) run() (CustomState, error) {
+	if len(m.cfg.Command) == 0 {
+		return nil, fmt.Errorf("plugin %s: no command", m.cfg.Name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), m.cfg.Timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, m.cfg.Command[0], m.cfg.Command[1:]...)
+	// Don't wait for orphaned children holding stdout after the timeout
+	cmd.WaitDelay = m.cfg.Timeout
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	out, err := cmd.Output()
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("plugin %s timed out after %s", m.cfg.Name, m.cfg.Timeout)
+		}
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("plugin %s failed: %w: %s", m.cfg.Name, err, msg)
+		}
+		return nil, fmt.Errorf("plugin %s failed: %w", m.cfg.Name, err)
+	}
+
+	values, err := parsePluginOutput(out)
+	if err != nil {
+		return nil, fmt.Errorf("plugin %s: %w", m.cfg.Name, err)
+	}
+	return values, nil
+}
+
+// parsePluginOutput parses a JSON object of named numbers.
+func parsePluginOutput(out []byte) (CustomState, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return nil, fmt.Errorf("output is not a JSON object: %w", err)
+	}
+
+	values := make(CustomState, len(raw))
+	for name, v := range raw {
+		var value float64
+		if err := json.Unmarshal(v, &value); err != nil {
+			return nil, fmt.Errorf("metric %s is not a number: %s", name, v)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
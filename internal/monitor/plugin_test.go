@@ -0,0 +1,91 @@
+package monitor
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func shellPlugin(name, script string, interval time.Duration) *PluginMonitor {
+	return NewPluginMonitor(PluginConfig{
+		Name:     name,
+		Command:  []string{"sh", "-c", script},
+		Interval: interval,
+		Timeout:  time.Second,
+	})
+}
+
+func TestPluginMonitor_Collect(t *testing.T) {
+	m := shellPlugin("render", `echo '{"render_backlog": 42, "render_workers": 3.5}'`, 0)
+
+	if m.Name() != "plugin:render" {
+		t.Errorf("expected name 'plugin:render', got %s", m.Name())
+	}
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	values := data.(CustomState)
+	if values["render_backlog"] != 42 || values["render_workers"] != 3.5 {
+		t.Errorf("unexpected values: %v", values)
+	}
+}
+
+func TestPluginMonitor_Interval(t *testing.T) {
+	counter := filepath.Join(t.TempDir(), "runs")
+	m := shellPlugin("seats", `echo x >> `+counter+`; echo "{\"runs\": $(wc -l < `+counter+`)}"`, time.Hour)
+
+	for range 3 {
+		if _, err := m.Collect(); err != nil {
+			t.Fatalf("collect failed: %v", err)
+		}
+	}
+
+	data, _ := m.Collect()
+	if runs := data.(CustomState)["runs"]; runs != 1 {
+		t.Errorf("expected the command to run once within the interval, ran %v times", runs)
+	}
+}
+
+func TestPluginMonitor_Failures(t *testing.T) {
+	tests := []struct {
+		name    string
+		script  string
+		wantErr string
+	}{
+		{"exit status", `echo "license server unreachable" >&2; exit 2`, "license server unreachable"},
+		{"not json", `echo 42`, "not a JSON object"},
+		{"not a number", `echo '{"seats": "three"}'`, "metric seats is not a number"},
+		{"timeout", `sleep 5`, "timed out after"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := shellPlugin("seats", tt.script, 0)
+			m.cfg.Timeout = 100 * time.Millisecond
+
+			_, err := m.Collect()
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error containing %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAggregator_PluginMetrics(t *testing.T) {
+	monitors := []Monitor{
+		shellPlugin("render", `echo '{"render_backlog": 42}'`, 0),
+		shellPlugin("licenses", `echo '{"license_seats_free": 3}'`, 0),
+	}
+
+	agg := NewAggregator(monitors, time.Second, testLogger())
+	agg.collect()
+
+	custom := agg.GetState().Custom
+	if custom["render_backlog"] != 42 || custom["license_seats_free"] != 3 {
+		t.Errorf("expected metrics of both plugins, got %v", custom)
+	}
+}
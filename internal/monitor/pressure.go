@@ -0,0 +1,136 @@
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// PSI averaging windows accepted by PressureResource.Window.
+const (
+	PressureWindowAvg10 = "avg10"
+	PressureWindowAvg60 = "avg60"
+)
+
+// PressureMonitor collects Linux Pressure Stall Information from
+// <procRoot>/pressure/{cpu,memory,io}.
+// Graceful degradation: on kernels without PSI, returns zero pressure.
+type PressureMonitor struct {
+	procRoot string
+}
+
+// NewPressureMonitor creates a PSI monitor reading from the given procfs root.
+func NewPressureMonitor(procRoot string) *PressureMonitor {
+	if procRoot == "" {
+		procRoot = DefaultProcRoot
+	}
+
+	return &PressureMonitor{procRoot: procRoot}
+}
+
+func (m *PressureMonitor) Name() string {
+	return "pressure"
+}
+
+func (m *PressureMonitor) Section() string {
+	return SectionPressure
+}
+
+func (m *PressureMonitor) Collect() (Sample, error) {
+	state := &PressureState{}
+
+	resources := []struct {
+		file   string
+		target *PressureResource
+	}{
+		{"cpu", &state.CPU},
+		{"memory", &state.Memory},
+		{"io", &state.IO},
+	}
+
+	for _, r := range resources {
+		res, err := readPressureFile(filepath.Join(m.procRoot, "pressure", r.file))
+		if err != nil {
+			// PSI is disabled or not supported by this kernel
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		*r.target = *res
+	}
+
+	return state, nil
+}
+
+// Available returns true if the kernel exposes PSI.
+func (m *PressureMonitor) Available() bool {
+	_, err := os.Stat(filepath.Join(m.procRoot, "pressure"))
+	return err == nil
+}
+
+// readPressureFile parses a PSI file:
+//
+//	some avg10=1.23 avg60=0.50 avg300=0.10 total=123456
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+//
+// The full line is absent for cpu on older kernels and is reported as zero.
+func readPressureFile(path string) (*PressureResource, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	res := &PressureResource{}
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		var target *PressureStats
+		switch fields[0] {
+		case "some":
+			target = &res.Some
+		case "full":
+			target = &res.Full
+		default:
+			continue
+		}
+
+		for _, field := range fields[1:] {
+			key, value, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+
+			var dst *float64
+			switch key {
+			case PressureWindowAvg10:
+				dst = &target.Avg10
+			case PressureWindowAvg60:
+				dst = &target.Avg60
+			default:
+				continue
+			}
+
+			v, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s in %s: %w", key, path, err)
+			}
+			*dst = v
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
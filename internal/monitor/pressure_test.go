@@ -0,0 +1,130 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// fakeProcPressure writes PSI fixture files under <root>/pressure.
+func fakeProcPressure(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	dir := filepath.Join(root, "pressure")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("failed to create pressure dir: %v", err)
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("failed to write %s: %v", name, err)
+		}
+	}
+
+	return root
+}
+
+func TestPressureMonitor_Name(t *testing.T) {
+	m := NewPressureMonitor(t.TempDir())
+	if m.Name() != "pressure" {
+		t.Errorf("expected name 'pressure', got %s", m.Name())
+	}
+}
+
+func TestPressureMonitor_Collect(t *testing.T) {
+	root := fakeProcPressure(t, map[string]string{
+		"cpu": "some avg10=12.50 avg60=8.25 avg300=3.00 total=123456\n" +
+			"full avg10=0.00 avg60=0.00 avg300=0.00 total=0\n",
+		"memory": "some avg10=4.00 avg60=2.00 avg300=1.00 total=5000\n" +
+			"full avg10=1.50 avg60=0.75 avg300=0.20 total=2000\n",
+		"io": "some avg10=30.10 avg60=22.00 avg300=10.00 total=90000\n" +
+			"full avg10=25.00 avg60=18.40 avg300=8.00 total=80000\n",
+	})
+
+	m := NewPressureMonitor(root)
+	if !m.Available() {
+		t.Fatal("expected PSI to be available")
+	}
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	state, ok := data.(*PressureState)
+	if !ok {
+		t.Fatalf("expected *PressureState, got %T", data)
+	}
+
+	if state.CPU.Some.Avg10 != 12.5 || state.CPU.Some.Avg60 != 8.25 {
+		t.Errorf("unexpected cpu some: %+v", state.CPU.Some)
+	}
+	if state.Memory.Full.Avg10 != 1.5 || state.Memory.Full.Avg60 != 0.75 {
+		t.Errorf("unexpected memory full: %+v", state.Memory.Full)
+	}
+	if state.IO.Some.Avg10 != 30.1 || state.IO.Full.Avg60 != 18.4 {
+		t.Errorf("unexpected io: %+v", state.IO)
+	}
+}
+
+func TestPressureMonitor_CPUWithoutFullLine(t *testing.T) {
+	// Kernels before 5.13 report only "some" for cpu
+	root := fakeProcPressure(t, map[string]string{
+		"cpu": "some avg10=5.00 avg60=4.00 avg300=3.00 total=100\n",
+	})
+
+	data, err := NewPressureMonitor(root).Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	state := data.(*PressureState)
+	if state.CPU.Some.Avg10 != 5 {
+		t.Errorf("expected cpu some avg10 5, got %f", state.CPU.Some.Avg10)
+	}
+	if state.CPU.Full.Avg10 != 0 {
+		t.Errorf("expected cpu full avg10 0, got %f", state.CPU.Full.Avg10)
+	}
+}
+
+func TestPressureMonitor_GracefulDegradation(t *testing.T) {
+	m := NewPressureMonitor(t.TempDir())
+
+	if m.Available() {
+		t.Error("expected PSI to be unavailable")
+	}
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("collect should not fail without PSI: %v", err)
+	}
+
+	if state := data.(*PressureState); *state != (PressureState{}) {
+		t.Errorf("expected zero pressure, got %+v", state)
+	}
+}
+
+func TestPressureMonitor_Malformed(t *testing.T) {
+	root := fakeProcPressure(t, map[string]string{
+		"io": "some avg10=abc avg60=0.00 avg300=0.00 total=0\n",
+	})
+
+	if _, err := NewPressureMonitor(root).Collect(); err == nil {
+		t.Error("expected error for malformed PSI value")
+	}
+}
+
+func TestPressureResource_Window(t *testing.T) {
+	res := PressureResource{
+		Some: PressureStats{Avg10: 10, Avg60: 6},
+		Full: PressureStats{Avg10: 2, Avg60: 1},
+	}
+
+	if some, full := res.Window(PressureWindowAvg10); some != 10 || full != 2 {
+		t.Errorf("avg10: expected 10/2, got %f/%f", some, full)
+	}
+	if some, full := res.Window(PressureWindowAvg60); some != 6 || full != 1 {
+		t.Errorf("avg60: expected 6/1, got %f/%f", some, full)
+	}
+}
@@ -1,21 +1,42 @@
 package monitor
 
 import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
-	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/shirou/gopsutil/v4/process"
 )
 
+// DefaultProcRoot is the mount point of procfs.
+const DefaultProcRoot = "/proc"
+
 type ProcessMonitor struct {
+	procRoot        string
 	prevCtxSwitches uint64
+	prevInterrupts  uint64
 	prevTime        time.Time
 	mu              sync.Mutex
 }
 
 func NewProcessMonitor() *ProcessMonitor {
+	return NewProcessMonitorWithProcRoot(DefaultProcRoot)
+}
+
+// NewProcessMonitorWithProcRoot creates a process monitor reading /proc/stat
+// from a custom procfs root.
+func NewProcessMonitorWithProcRoot(procRoot string) *ProcessMonitor {
+	if procRoot == "" {
+		procRoot = DefaultProcRoot
+	}
+
 	return &ProcessMonitor{
+		procRoot: procRoot,
 		prevTime: time.Now(),
 	}
 }
@@ -24,7 +45,41 @@ func (m *ProcessMonitor) Name() string {
 	return "process"
 }
 
-func (m *ProcessMonitor) Collect() (any, error) {
+func (m *ProcessMonitor) Section() string {
+	return SectionProcess
+}
+
+// Reasons of the run queue thresholds contributed by the process monitor.
+const (
+	ReasonRunQueueFull = "run_queue_saturated"
+	ReasonProcsBlocked = "procs_blocked"
+)
+
+// Thresholds contributes the run queue limits.
+func (m *ProcessMonitor) Thresholds() []Threshold {
+	return ProcessThresholds()
+}
+
+// ProcessThresholds returns the run queue limits, for the process monitor
+// and for monitors replacing the whole state.
+func ProcessThresholds() []Threshold {
+	return []Threshold{
+		{
+			Reason: ReasonRunQueueFull,
+			Exceeded: func(state *SystemState, limits Limits) bool {
+				return limits.Process.MaxRunning > 0 && state.ProcsRunning > limits.Process.MaxRunning
+			},
+		},
+		{
+			Reason: ReasonProcsBlocked,
+			Exceeded: func(state *SystemState, limits Limits) bool {
+				return limits.Process.MaxBlocked > 0 && state.ProcsBlocked > limits.Process.MaxBlocked
+			},
+		},
+	}
+}
+
+func (m *ProcessMonitor) Collect() (Sample, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -44,35 +99,105 @@ func (m *ProcessMonitor) Collect() (any, error) {
 		}
 	}
 
-	// Get context switches
-	cpuTimes, err := cpu.Times(false)
-	if err != nil {
+	state := &ProcessState{
+		Processes: processCount,
+		Threads:   threadCount,
+	}
+
+	if err := m.collectStat(state); err != nil {
 		return nil, err
 	}
 
-	var ctxSwitchesPerSec int64
+	return state, nil
+}
+
+// collectStat fills scheduler counters from /proc/stat.
+// Rates are zero on the first call since they need a previous sample.
+func (m *ProcessMonitor) collectStat(state *ProcessState) error {
+	stat, err := readProcStat(filepath.Join(m.procRoot, "stat"))
+	if err != nil {
+		// /proc/stat only exists on Linux
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
 	now := time.Now()
 
-	if len(cpuTimes) > 0 {
-		// Use Idle + System as approximation for context switches
-		// This is a simplification - real ctx switches would need /proc/stat on Linux
-		currentCtx := uint64(cpuTimes[0].Idle + cpuTimes[0].System)
+	if m.prevCtxSwitches > 0 {
+		elapsed := now.Sub(m.prevTime).Seconds()
+		if elapsed > 0 {
+			state.ContextSwitchesPerSec = counterRate(m.prevCtxSwitches, stat.ctxt, elapsed)
+			state.InterruptsPerSec = counterRate(m.prevInterrupts, stat.intr, elapsed)
+		}
+	}
+
+	state.ProcsRunning = stat.procsRunning
+	state.ProcsBlocked = stat.procsBlocked
+
+	m.prevCtxSwitches = stat.ctxt
+	m.prevInterrupts = stat.intr
+	m.prevTime = now
+
+	return nil
+}
+
+// counterRate returns the per-second rate of a monotonic counter.
+// A counter that went backwards (e.g. after a reset) yields 0.
+func counterRate(prev, cur uint64, elapsed float64) int64 {
+	if cur < prev {
+		return 0
+	}
+	return int64(float64(cur-prev) / elapsed)
+}
+
+// procStat holds the /proc/stat fields used by ProcessMonitor.
+type procStat struct {
+	ctxt         uint64
+	intr         uint64
+	procsRunning int
+	procsBlocked int
+}
+
+// readProcStat parses the ctxt, intr, procs_running and procs_blocked lines of /proc/stat.
+func readProcStat(path string) (*procStat, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	stat := &procStat{}
+	scanner := bufio.NewScanner(f)
+	// The intr line lists a counter per IRQ and can be very long
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 
-		if m.prevCtxSwitches > 0 {
-			elapsed := now.Sub(m.prevTime).Seconds()
-			if elapsed > 0 {
-				ctxSwitchesPerSec = int64(float64(currentCtx-m.prevCtxSwitches) / elapsed)
-			}
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
 		}
 
-		m.prevCtxSwitches = currentCtx
+		switch fields[0] {
+		case "ctxt":
+			stat.ctxt, err = strconv.ParseUint(fields[1], 10, 64)
+		case "intr":
+			// First value is the total, the rest are per-IRQ counts
+			stat.intr, err = strconv.ParseUint(fields[1], 10, 64)
+		case "procs_running":
+			stat.procsRunning, err = strconv.Atoi(fields[1])
+		case "procs_blocked":
+			stat.procsBlocked, err = strconv.Atoi(fields[1])
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in %s: %w", fields[0], path, err)
+		}
 	}
 
-	m.prevTime = now
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
 
-	return &ProcessState{
-		Processes:             processCount,
-		Threads:               threadCount,
-		ContextSwitchesPerSec: ctxSwitchesPerSec,
-	}, nil
+	return stat, nil
 }
@@ -1,7 +1,12 @@
 package monitor
 
 import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
 	"testing"
+	"time"
 )
 
 func TestProcessMonitor_Name(t *testing.T) {
@@ -63,3 +68,137 @@ func TestProcessMonitor_CollectTwice(t *testing.T) {
 		t.Error("expected at least one process")
 	}
 }
+
+// writeProcStat writes a minimal /proc/stat under root.
+func writeProcStat(t *testing.T, root string, ctxt, intr uint64, running, blocked int) {
+	t.Helper()
+
+	content := "cpu  10132153 290696 3084719 46828483 16683 0 25195 0 0 0\n" +
+		"cpu0 1393280 32966 572056 13343292 6130 0 17875 0 0 0\n" +
+		"intr " + strconv.FormatUint(intr, 10) + " 9 0 0 0 0 0 0 0 1 0\n" +
+		"ctxt " + strconv.FormatUint(ctxt, 10) + "\n" +
+		"btime 1769000000\n" +
+		"processes 86031\n" +
+		"procs_running " + strconv.Itoa(running) + "\n" +
+		"procs_blocked " + strconv.Itoa(blocked) + "\n"
+
+	if err := os.WriteFile(filepath.Join(root, "stat"), []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write stat: %v", err)
+	}
+}
+
+func TestProcessMonitor_ProcStat(t *testing.T) {
+	root := t.TempDir()
+	writeProcStat(t, root, 1000, 500, 3, 1)
+
+	m := NewProcessMonitorWithProcRoot(root)
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("first collect failed: %v", err)
+	}
+
+	state := data.(*ProcessState)
+	if state.ProcsRunning != 3 {
+		t.Errorf("expected 3 running, got %d", state.ProcsRunning)
+	}
+	if state.ProcsBlocked != 1 {
+		t.Errorf("expected 1 blocked, got %d", state.ProcsBlocked)
+	}
+	if state.ContextSwitchesPerSec != 0 || state.InterruptsPerSec != 0 {
+		t.Errorf("expected zero rates on first call, got ctxt=%d intr=%d",
+			state.ContextSwitchesPerSec, state.InterruptsPerSec)
+	}
+
+	// Pretend the previous sample was taken one second ago
+	m.prevTime = time.Now().Add(-time.Second)
+	writeProcStat(t, root, 11000, 2500, 7, 2)
+
+	data, err = m.Collect()
+	if err != nil {
+		t.Fatalf("second collect failed: %v", err)
+	}
+
+	state = data.(*ProcessState)
+	if state.ContextSwitchesPerSec < 9000 || state.ContextSwitchesPerSec > 10000 {
+		t.Errorf("expected ~10000 ctxt/s, got %d", state.ContextSwitchesPerSec)
+	}
+	if state.InterruptsPerSec < 1800 || state.InterruptsPerSec > 2000 {
+		t.Errorf("expected ~2000 intr/s, got %d", state.InterruptsPerSec)
+	}
+	if state.ProcsRunning != 7 || state.ProcsBlocked != 2 {
+		t.Errorf("expected 7 running / 2 blocked, got %d / %d", state.ProcsRunning, state.ProcsBlocked)
+	}
+}
+
+func TestProcessMonitor_ProcStatCounterReset(t *testing.T) {
+	root := t.TempDir()
+	writeProcStat(t, root, 5000, 5000, 1, 0)
+
+	m := NewProcessMonitorWithProcRoot(root)
+	if _, err := m.Collect(); err != nil {
+		t.Fatalf("first collect failed: %v", err)
+	}
+
+	writeProcStat(t, root, 100, 100, 1, 0)
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("second collect failed: %v", err)
+	}
+
+	state := data.(*ProcessState)
+	if state.ContextSwitchesPerSec != 0 || state.InterruptsPerSec != 0 {
+		t.Errorf("expected zero rates after counter reset, got ctxt=%d intr=%d",
+			state.ContextSwitchesPerSec, state.InterruptsPerSec)
+	}
+}
+
+func TestProcessMonitor_ProcStatMissing(t *testing.T) {
+	m := NewProcessMonitorWithProcRoot(t.TempDir())
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("collect should not fail without /proc/stat: %v", err)
+	}
+
+	if state := data.(*ProcessState); state.ProcsRunning != 0 {
+		t.Errorf("expected 0 running without /proc/stat, got %d", state.ProcsRunning)
+	}
+}
+
+func TestProcessMonitor_Thresholds(t *testing.T) {
+	state := &SystemState{ProcsRunning: 17, ProcsBlocked: 5}
+	thresholds := NewProcessMonitor().Thresholds()
+
+	tests := []struct {
+		name   string
+		limits ProcessLimits
+		want   []string
+	}{
+		{"disabled", ProcessLimits{}, nil},
+		{"within limits", ProcessLimits{MaxRunning: 17, MaxBlocked: 5}, nil},
+		{"run queue saturated", ProcessLimits{MaxRunning: 16}, []string{ReasonRunQueueFull}},
+		{"both", ProcessLimits{MaxRunning: 16, MaxBlocked: 4}, []string{ReasonRunQueueFull, ReasonProcsBlocked}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := checkThresholds(thresholds, state, Limits{Process: tt.limits})
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestReadProcStat_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stat")
+	if err := os.WriteFile(path, []byte("ctxt abc\n"), 0644); err != nil {
+		t.Fatalf("failed to write stat: %v", err)
+	}
+
+	if _, err := readProcStat(path); err == nil {
+		t.Error("expected error for malformed ctxt")
+	}
+}
@@ -0,0 +1,241 @@
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// clockTicks is USER_HZ, the unit of CPU times in /proc/<pid>/stat. It is
+// 100 on every mainstream Linux architecture.
+const clockTicks = 100
+
+// ProcessUsage is the resource usage of a process tree, as measured by
+// ProcessTracker.
+type ProcessUsage struct {
+	// WallSeconds is the time since tracking started
+	WallSeconds float64 `json:"wall_seconds"`
+	// CPUSeconds is user plus system time of all processes in the tree
+	CPUSeconds float64 `json:"cpu_seconds"`
+	// PeakRSSBytes is the highest combined resident memory seen
+	PeakRSSBytes uint64 `json:"peak_rss_bytes"`
+	// ReadBytes and WriteBytes are storage I/O of all processes in the tree
+	ReadBytes  uint64 `json:"read_bytes"`
+	WriteBytes uint64 `json:"write_bytes"`
+	// PeakThreads is the highest combined thread count seen
+	PeakThreads int `json:"peak_threads"`
+}
+
+// procKey identifies a process across PID reuse.
+type procKey struct {
+	pid       int
+	startTime uint64
+}
+
+// procCounters are the cumulative counters of one process.
+type procCounters struct {
+	cpuTicks   uint64
+	readBytes  uint64
+	writeBytes uint64
+}
+
+// pidStat holds the /proc/<pid>/stat fields used by ProcessTracker.
+type pidStat struct {
+	ppid      int
+	cpuTicks  uint64 // utime + stime
+	threads   int
+	startTime uint64
+	rssPages  uint64
+}
+
+// ProcessTracker follows a process and its descendants through procfs.
+// Each Sample adds processes that appeared since the last one; counters
+// of processes that exited keep their last sampled values.
+type ProcessTracker struct {
+	procRoot string
+	root     int
+	started  time.Time
+	pageSize uint64
+
+	mu          sync.Mutex
+	counters    map[procKey]procCounters
+	peakRSS     uint64
+	peakThreads int
+	finalCPU    float64 // from the root's rusage, see Finish
+}
+
+// NewProcessTracker creates a tracker for the process tree rooted at pid.
+func NewProcessTracker(procRoot string, pid int) *ProcessTracker {
+	if procRoot == "" {
+		procRoot = DefaultProcRoot
+	}
+
+	return &ProcessTracker{
+		procRoot: procRoot,
+		root:     pid,
+		started:  time.Now(),
+		pageSize: uint64(os.Getpagesize()),
+		counters: make(map[procKey]procCounters),
+	}
+}
+
+// Sample reads the current usage of the tree. Returns an error if the root
+// process is gone.
+func (t *ProcessTracker) Sample() error {
+	stats, err := t.readStats()
+	if err != nil {
+		return err
+	}
+	if _, ok := stats[t.root]; !ok {
+		return fmt.Errorf("process %d not found", t.root)
+	}
+
+	children := make(map[int][]int, len(stats))
+	for pid, stat := range stats {
+		children[stat.ppid] = append(children[stat.ppid], pid)
+	}
+
+	var rss uint64
+	var threads int
+	queue := []int{t.root}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for len(queue) > 0 {
+		pid := queue[0]
+		queue = append(queue[1:], children[pid]...)
+
+		stat := stats[pid]
+		rss += stat.rssPages * t.pageSize
+		threads += stat.threads
+
+		counters := procCounters{cpuTicks: stat.cpuTicks}
+		// Not readable for processes that changed credentials, e.g. setuid
+		counters.readBytes, counters.writeBytes, _ = readProcIO(filepath.Join(t.procRoot, strconv.Itoa(pid), "io"))
+		t.counters[procKey{pid: pid, startTime: stat.startTime}] = counters
+	}
+
+	t.peakRSS = max(t.peakRSS, rss)
+	t.peakThreads = max(t.peakThreads, threads)
+	return nil
+}
+
+// Finish records the CPU time reported by the kernel for the exited root
+// process, which includes descendants it waited for, even those that lived
+// and died between samples.
+func (t *ProcessTracker) Finish(state *os.ProcessState) {
+	if state == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.finalCPU = (state.UserTime() + state.SystemTime()).Seconds()
+}
+
+// Usage returns the usage measured so far.
+func (t *ProcessTracker) Usage() ProcessUsage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	usage := ProcessUsage{
+		WallSeconds:  time.Since(t.started).Seconds(),
+		PeakRSSBytes: t.peakRSS,
+		PeakThreads:  t.peakThreads,
+	}
+
+	var ticks uint64
+	for _, c := range t.counters {
+		ticks += c.cpuTicks
+		usage.ReadBytes += c.readBytes
+		usage.WriteBytes += c.writeBytes
+	}
+	usage.CPUSeconds = max(float64(ticks)/clockTicks, t.finalCPU)
+
+	return usage
+}
+
+// readStats reads /proc/<pid>/stat of every process. Processes exiting
+// during the walk are skipped.
+func (t *ProcessTracker) readStats() (map[int]pidStat, error) {
+	entries, err := os.ReadDir(t.procRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make(map[int]pidStat, len(entries))
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(t.procRoot, entry.Name(), "stat"))
+		if err != nil {
+			continue
+		}
+		if stat, err := parsePidStat(string(data)); err == nil {
+			stats[pid] = stat
+		}
+	}
+	return stats, nil
+}
+
+// parsePidStat parses /proc/<pid>/stat. The command name may contain
+// spaces and parentheses, so fields are counted from its closing paren.
+func parsePidStat(data string) (pidStat, error) {
+	end := strings.LastIndexByte(data, ')')
+	if end < 0 {
+		return pidStat{}, fmt.Errorf("invalid stat: missing command")
+	}
+
+	// fields[0] is the state, field 3 in proc(5)
+	fields := strings.Fields(data[end+1:])
+	if len(fields) < 22 {
+		return pidStat{}, fmt.Errorf("invalid stat: %d fields", len(fields))
+	}
+
+	field := func(n int) uint64 {
+		v, _ := strconv.ParseUint(fields[n-3], 10, 64)
+		return v
+	}
+
+	return pidStat{
+		ppid:      int(field(4)),
+		cpuTicks:  field(14) + field(15),
+		threads:   int(field(20)),
+		startTime: field(22),
+		rssPages:  field(24),
+	}, nil
+}
+
+// readProcIO returns read_bytes and write_bytes of /proc/<pid>/io.
+func readProcIO(path string) (read, write uint64, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		v, _ := strconv.ParseUint(strings.TrimSpace(value), 10, 64)
+		switch key {
+		case "read_bytes":
+			read = v
+		case "write_bytes":
+			write = v
+		}
+	}
+	return read, write, scanner.Err()
+}
@@ -0,0 +1,97 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFakeProc writes /proc/<pid>/stat and io for a fake process.
+func writeFakeProc(t *testing.T, root string, pid, ppid int, cpuTicks, rssPages uint64, threads int, readBytes uint64) {
+	t.Helper()
+
+	dir := filepath.Join(root, fmt.Sprint(pid))
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// utime and stime split the ticks; starttime is the pid
+	stat := fmt.Sprintf("%d (worker (%d)) S %d 1 1 0 -1 4194304 100 0 0 0 %d %d 0 0 20 0 %d 0 %d 10000000 %d 18446744073709551615\n",
+		pid, pid, ppid, cpuTicks/2, cpuTicks-cpuTicks/2, threads, pid, rssPages)
+	if err := os.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	io := fmt.Sprintf("rchar: 100\nwchar: 100\nsyscr: 1\nsyscw: 1\nread_bytes: %d\nwrite_bytes: 4096\ncancelled_write_bytes: 0\n", readBytes)
+	if err := os.WriteFile(filepath.Join(dir, "io"), []byte(io), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestParsePidStat(t *testing.T) {
+	stat, err := parsePidStat("4242 (my (odd) cmd) R 4241 4242 4242 0 -1 4194560 500 0 0 0 250 50 0 0 20 0 8 0 123456 204800000 2560 18446744073709551615")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if stat.ppid != 4241 || stat.cpuTicks != 300 || stat.threads != 8 || stat.startTime != 123456 || stat.rssPages != 2560 {
+		t.Errorf("unexpected stat: %+v", stat)
+	}
+
+	if _, err := parsePidStat("4242 (truncated) R 1"); err == nil {
+		t.Error("expected error for truncated stat")
+	}
+}
+
+func TestProcessTracker_Tree(t *testing.T) {
+	root := t.TempDir()
+	writeFakeProc(t, root, 100, 1, 200, 100, 2, 1000)
+	writeFakeProc(t, root, 101, 100, 100, 50, 4, 2000)
+	writeFakeProc(t, root, 102, 101, 50, 10, 1, 0)
+	writeFakeProc(t, root, 200, 1, 9999, 9999, 99, 9999) // not in the tree
+
+	tracker := NewProcessTracker(root, 100)
+	if err := tracker.Sample(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	usage := tracker.Usage()
+	pageSize := uint64(os.Getpagesize())
+	if usage.CPUSeconds != 3.5 {
+		t.Errorf("expected 3.5 cpu seconds, got %v", usage.CPUSeconds)
+	}
+	if usage.PeakRSSBytes != 160*pageSize {
+		t.Errorf("expected %d rss bytes, got %d", 160*pageSize, usage.PeakRSSBytes)
+	}
+	if usage.PeakThreads != 7 {
+		t.Errorf("expected 7 threads, got %d", usage.PeakThreads)
+	}
+	if usage.ReadBytes != 3000 || usage.WriteBytes != 3*4096 {
+		t.Errorf("expected 3000 read and %d written bytes, got %d and %d", 3*4096, usage.ReadBytes, usage.WriteBytes)
+	}
+
+	// An exited child keeps its counters, the peak stays
+	if err := os.RemoveAll(filepath.Join(root, "101")); err != nil {
+		t.Fatal(err)
+	}
+	writeFakeProc(t, root, 100, 1, 300, 20, 1, 1000)
+	if err := tracker.Sample(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	usage = tracker.Usage()
+	if usage.CPUSeconds != 4.5 {
+		t.Errorf("expected 4.5 cpu seconds after child exit, got %v", usage.CPUSeconds)
+	}
+	if usage.PeakRSSBytes != 160*pageSize {
+		t.Errorf("expected peak rss to stay %d, got %d", 160*pageSize, usage.PeakRSSBytes)
+	}
+}
+
+func TestProcessTracker_RootGone(t *testing.T) {
+	tracker := NewProcessTracker(t.TempDir(), 100)
+	if err := tracker.Sample(); err == nil {
+		t.Error("expected error for missing root process")
+	}
+}
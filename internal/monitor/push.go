@@ -0,0 +1,94 @@
+package monitor
+
+import "time"
+
+// PushedMetric is a custom metric reported through the push API. It is
+// merged into SystemState.Custom until it expires.
+type PushedMetric struct {
+	Name      string    `json:"name"`
+	Value     float64   `json:"value"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PushMetrics stores pushed metrics, replacing earlier values of the same
+// name. They are visible in the state immediately and on every collection
+// until they expire. A pushed metric overrides a plugin metric of the same name.
+func (a *Aggregator) PushMetrics(metrics []PushedMetric) {
+	now := time.Now()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, m := range metrics {
+		a.pushed[m.Name] = m
+	}
+	a.pruneExpired(now)
+
+	// Published snapshots are read without the lock, so the push goes
+	// into copies that replace them
+	state := withPushed(a.state, metrics, now)
+	if a.smoothed == a.state {
+		a.smoothed = state
+	} else {
+		a.smoothed = withPushed(a.smoothed, metrics, now)
+	}
+	a.state = state
+}
+
+// withPushed returns a copy of state with the live metrics added.
+func withPushed(state *SystemState, metrics []PushedMetric, now time.Time) *SystemState {
+	clone := state.Clone()
+	for _, m := range metrics {
+		if m.ExpiresAt.After(now) {
+			if clone.Custom == nil {
+				clone.Custom = make(CustomState, len(metrics))
+			}
+			clone.Custom[m.Name] = m.Value
+		}
+	}
+	return clone
+}
+
+// PushedMetrics returns the pushed metrics that have not expired.
+func (a *Aggregator) PushedMetrics() []PushedMetric {
+	now := time.Now()
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	metrics := make([]PushedMetric, 0, len(a.pushed))
+	for _, m := range a.pushed {
+		if m.ExpiresAt.After(now) {
+			metrics = append(metrics, m)
+		}
+	}
+	return metrics
+}
+
+// mergePushed adds the live pushed metrics to a new snapshot and forgets
+// expired ones.
+func (a *Aggregator) mergePushed(state *SystemState) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.pruneExpired(state.Timestamp)
+	if len(a.pushed) == 0 {
+		return
+	}
+
+	if state.Custom == nil {
+		state.Custom = make(CustomState, len(a.pushed))
+	}
+	for name, m := range a.pushed {
+		state.Custom[name] = m.Value
+	}
+}
+
+// pruneExpired removes pushed metrics expired at now. Requires a.mu.
+func (a *Aggregator) pruneExpired(now time.Time) {
+	for name, m := range a.pushed {
+		if !m.ExpiresAt.After(now) {
+			delete(a.pushed, name)
+		}
+	}
+}
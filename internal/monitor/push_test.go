@@ -0,0 +1,88 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAggregator_PushMetrics(t *testing.T) {
+	monitors := []Monitor{
+		&mockMonitor{name: "plugin:inference", data: CustomState{"inference_inflight": 10, "inference_queue": 2}},
+	}
+
+	agg := NewAggregator(monitors, time.Second, testLogger())
+	agg.collect()
+
+	now := time.Now()
+	agg.PushMetrics([]PushedMetric{
+		{Name: "inference_inflight", Value: 37, ExpiresAt: now.Add(time.Minute)},
+		{Name: "batch_jobs", Value: 4, ExpiresAt: now.Add(50 * time.Millisecond)},
+	})
+
+	// Visible before the next collection, for status and decisions
+	for _, state := range []*SystemState{agg.GetState(), agg.GetSmoothedState()} {
+		if state.Custom["inference_inflight"] != 37 || state.Custom["batch_jobs"] != 4 {
+			t.Errorf("expected pushed metrics in state, got %v", state.Custom)
+		}
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	agg.collect()
+
+	custom := agg.GetState().Custom
+	if custom["inference_inflight"] != 37 {
+		t.Errorf("expected pushed value to override the plugin, got %v", custom["inference_inflight"])
+	}
+	if custom["inference_queue"] != 2 {
+		t.Errorf("expected plugin metric to be kept, got %v", custom)
+	}
+	if _, ok := custom["batch_jobs"]; ok {
+		t.Errorf("expected expired metric to be dropped, got %v", custom)
+	}
+
+	if pushed := agg.PushedMetrics(); len(pushed) != 1 || pushed[0].Name != "inference_inflight" {
+		t.Errorf("expected only inference_inflight to be live, got %+v", pushed)
+	}
+}
+
+func TestAggregator_PushMetrics_KeepsPublishedSnapshots(t *testing.T) {
+	monitors := []Monitor{
+		&mockMonitor{name: "plugin:inference", data: CustomState{"inference_queue": 2}},
+	}
+
+	agg := NewAggregator(monitors, time.Second, testLogger())
+	var observed *SystemState
+	var sum float64
+	agg.SetObserver(func(state *SystemState) {
+		observed = state
+		sum += state.Custom["inference_queue"]
+	})
+	agg.collect()
+
+	// The observer and history read the snapshot after collect releases
+	// the lock, so a push must not change it
+	agg.PushMetrics([]PushedMetric{
+		{Name: "inference_queue", Value: 9, ExpiresAt: time.Now().Add(time.Minute)},
+	})
+
+	if observed.Custom["inference_queue"] != 2 {
+		t.Errorf("expected the published snapshot to be unchanged, got %v", observed.Custom)
+	}
+	if custom := agg.GetState().Custom; custom["inference_queue"] != 9 {
+		t.Errorf("expected the pushed value in the current state, got %v", custom)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			agg.collect()
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		agg.PushMetrics([]PushedMetric{
+			{Name: "inference_queue", Value: float64(i), ExpiresAt: time.Now().Add(time.Minute)},
+		})
+	}
+	<-done
+}
@@ -0,0 +1,114 @@
+package monitor
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sections of SystemState populated by the built-in monitors, as named in
+// the /status response.
+const (
+	SectionCPU      = "cpu"
+	SectionMemory   = "memory"
+	SectionSwap     = "swap"
+	SectionGPU      = "gpus"
+	SectionStorage  = "storage"
+	SectionProcess  = "process"
+	SectionPressure = "pressure"
+	SectionIO       = "io"
+	SectionNetwork  = "network"
+	SectionThermal  = "thermal"
+	SectionNUMA     = "numa"
+	SectionCustom   = "custom"
+	// SectionAll is declared by monitors that replace the whole state, such
+	// as trace replay
+	SectionAll = "all"
+)
+
+// Threshold is a limit contributed by a monitor. Exceeded is called with
+// the state a decision is made on and the configured limits; Reason is
+// reported when it returns true.
+type Threshold struct {
+	Reason   string
+	Exceeded func(state *SystemState, limits Limits) bool
+}
+
+// Limits are the configured limits contributed thresholds are checked
+// against, resolved for the task and priority class of a decision.
+type Limits struct {
+	Process ProcessLimits
+}
+
+// ProcessLimits are the run queue limits. Zero disables a check.
+type ProcessLimits struct {
+	MaxRunning int
+	MaxBlocked int
+}
+
+// ThresholdProvider is an optional interface for monitors that contribute
+// their own thresholds. They are checked against the current state by
+// /ask and by every decision strategy.
+type ThresholdProvider interface {
+	Thresholds() []Threshold
+}
+
+// Registry collects the monitors an aggregator runs. Each monitor declares
+// the section its samples populate and, through ThresholdProvider, the
+// thresholds it contributes, so a resource is added by registering it.
+type Registry struct {
+	monitors []Monitor
+	names    map[string]bool
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{names: make(map[string]bool)}
+}
+
+// Register adds a monitor. Returns an error if its name is empty or taken,
+// its section is empty, or a contributed threshold is incomplete.
+func (r *Registry) Register(m Monitor) error {
+	var errs []error
+
+	name := m.Name()
+	if name == "" {
+		errs = append(errs, errors.New("monitor name is required"))
+	} else if r.names[name] {
+		errs = append(errs, fmt.Errorf("monitor %s is already registered", name))
+	}
+	if m.Section() == "" {
+		errs = append(errs, fmt.Errorf("monitor %s: section is required", name))
+	}
+	if p, ok := m.(ThresholdProvider); ok {
+		for i, t := range p.Thresholds() {
+			if t.Reason == "" || t.Exceeded == nil {
+				errs = append(errs, fmt.Errorf("monitor %s: threshold %d needs a reason and a check", name, i))
+			}
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	r.names[name] = true
+	r.monitors = append(r.monitors, m)
+	return nil
+}
+
+// Monitors returns the registered monitors in registration order.
+func (r *Registry) Monitors() []Monitor {
+	return append([]Monitor(nil), r.monitors...)
+}
+
+// checkThresholds returns the reasons of the thresholds the state exceeds,
+// in monitor order.
+func checkThresholds(thresholds []Threshold, state *SystemState, limits Limits) []string {
+	var reasons []string
+	for _, t := range thresholds {
+		if t.Exceeded(state, limits) {
+			reasons = append(reasons, t.Reason)
+		}
+	}
+	return reasons
+}
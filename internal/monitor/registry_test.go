@@ -0,0 +1,106 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// queueMonitor stands in for a resource living in its own package: it
+// populates an extension section and contributes its own threshold.
+type queueMonitor struct {
+	depth float64
+}
+
+func (m *queueMonitor) Name() string {
+	return "queue"
+}
+
+func (m *queueMonitor) Section() string {
+	return "queue"
+}
+
+func (m *queueMonitor) Collect() (Sample, error) {
+	return ExtensionSample{Section: "queue", Value: m.depth}, nil
+}
+
+func (m *queueMonitor) Thresholds() []Threshold {
+	return []Threshold{{
+		Reason: "queue_depth_exceeded",
+		Exceeded: func(state *SystemState, limits Limits) bool {
+			depth, _ := state.Extensions["queue"].(float64)
+			return depth > 100
+		},
+	}}
+}
+
+type sectionlessMonitor struct{}
+
+func (sectionlessMonitor) Name() string             { return "broken" }
+func (sectionlessMonitor) Section() string          { return "" }
+func (sectionlessMonitor) Collect() (Sample, error) { return nil, nil }
+
+func TestRegistry_Register(t *testing.T) {
+	registry := NewRegistry()
+
+	if err := registry.Register(NewCPUMonitor()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := registry.Register(&queueMonitor{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	err := registry.Register(NewCPUMonitor())
+	if err == nil || !strings.Contains(err.Error(), "already registered") {
+		t.Errorf("expected duplicate name error, got %v", err)
+	}
+
+	err = registry.Register(sectionlessMonitor{})
+	if err == nil || !strings.Contains(err.Error(), "section is required") {
+		t.Errorf("expected section error, got %v", err)
+	}
+
+	monitors := registry.Monitors()
+	if len(monitors) != 2 || monitors[0].Name() != "cpu" || monitors[1].Name() != "queue" {
+		t.Errorf("expected [cpu queue] in registration order, got %d monitors", len(monitors))
+	}
+}
+
+func TestAggregator_AppliesSampleBySection(t *testing.T) {
+	// The name no longer decides where a sample goes
+	monitors := []Monitor{
+		&mockMonitor{name: "host-cpu", data: &CPUState{UsagePercent: 42}},
+		&queueMonitor{depth: 7},
+	}
+
+	agg := NewAggregator(monitors, time.Second, testLogger())
+	agg.collect()
+
+	state := agg.GetState()
+	if state.CPU.UsagePercent != 42 {
+		t.Errorf("expected cpu 42, got %v", state.CPU.UsagePercent)
+	}
+	if depth, _ := state.Extensions["queue"].(float64); depth != 7 {
+		t.Errorf("expected queue extension 7, got %v", state.Extensions["queue"])
+	}
+	if section := state.Health["queue"].Section; section != "queue" {
+		t.Errorf("expected health section queue, got %q", section)
+	}
+}
+
+func TestAggregator_Check(t *testing.T) {
+	queue := &queueMonitor{depth: 150}
+	agg := NewAggregator([]Monitor{queue}, time.Second, testLogger())
+	agg.collect()
+
+	reasons := agg.Check(agg.GetState(), Limits{})
+	if len(reasons) != 1 || reasons[0] != "queue_depth_exceeded" {
+		t.Errorf("expected [queue_depth_exceeded], got %v", reasons)
+	}
+
+	queue.depth = 10
+	agg.collect()
+	if reasons := agg.Check(agg.GetState(), Limits{}); len(reasons) != 0 {
+		t.Errorf("expected no reasons, got %v", reasons)
+	}
+}
@@ -0,0 +1,85 @@
+package monitor
+
+// Sample is the result of one Collect call. Apply stores it in the section
+// of the state its monitor declares, so the aggregator needs no knowledge
+// of the concrete type.
+type Sample interface {
+	Apply(state *SystemState)
+}
+
+// GPUStates is the sample of a GPU monitor, one entry per device.
+type GPUStates []GPUState
+
+func (c *CPUState) Apply(s *SystemState) {
+	s.CPU = *c
+}
+
+func (m *MemoryState) Apply(s *SystemState) {
+	s.Memory = *m
+}
+
+func (sw *SwapState) Apply(s *SystemState) {
+	s.Swap = *sw
+}
+
+func (g GPUStates) Apply(s *SystemState) {
+	s.GPUs = g
+}
+
+func (st StorageState) Apply(s *SystemState) {
+	s.Storage = st
+}
+
+func (p *ProcessState) Apply(s *SystemState) {
+	s.Processes = p.Processes
+	s.Threads = p.Threads
+	s.ContextSwitchesPerSec = p.ContextSwitchesPerSec
+	s.InterruptsPerSec = p.InterruptsPerSec
+	s.ProcsRunning = p.ProcsRunning
+	s.ProcsBlocked = p.ProcsBlocked
+}
+
+func (p *PressureState) Apply(s *SystemState) {
+	s.Pressure = *p
+}
+
+func (io *IOState) Apply(s *SystemState) {
+	s.IO = *io
+}
+
+func (n NetworkState) Apply(s *SystemState) {
+	s.Network = n
+}
+
+func (t *ThermalState) Apply(s *SystemState) {
+	s.Thermal = *t
+}
+
+func (n *NUMAState) Apply(s *SystemState) {
+	s.NUMA = *n
+}
+
+// Apply merges the values into the custom section. Plugins share one
+// namespace; a later plugin wins on a name clash.
+func (c CustomState) Apply(s *SystemState) {
+	if s.Custom == nil {
+		s.Custom = make(CustomState, len(c))
+	}
+	for metric, v := range c {
+		s.Custom[metric] = v
+	}
+}
+
+// ExtensionSample stores a value under a key of SystemState.Extensions, for
+// resources that live in their own package and have no built-in section.
+type ExtensionSample struct {
+	Section string
+	Value   any
+}
+
+func (e ExtensionSample) Apply(s *SystemState) {
+	if s.Extensions == nil {
+		s.Extensions = make(Extensions)
+	}
+	s.Extensions[e.Section] = e.Value
+}
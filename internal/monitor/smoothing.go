@@ -0,0 +1,170 @@
+package monitor
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Smoothing kinds accepted by ParseSmoothing.
+const (
+	// SmoothingInstant uses the latest sample as is
+	SmoothingInstant = "instant"
+	// SmoothingEWMA uses an exponentially weighted moving average
+	SmoothingEWMA = "ewma"
+	// SmoothingMax uses the highest sample within a window
+	SmoothingMax = "max"
+	// SmoothingPercentile uses a percentile of the samples within a window
+	SmoothingPercentile = "percentile"
+)
+
+// SmoothedResources are the resources whose decision inputs can be smoothed.
+var SmoothedResources = []string{"cpu", "memory", "swap", "gpu", "vram", "io", "network"}
+
+// smoothingRegex matches "kind(arg)" specs such as ewma(0.3) or p95(1m).
+var smoothingRegex = regexp.MustCompile(`^(ewma|max|p(\d+(?:\.\d+)?))\((.+)\)$`)
+
+// Smoothing describes how samples of a resource are aggregated before
+// they are compared against thresholds.
+type Smoothing struct {
+	Kind string
+	// Alpha is the weight of the newest sample (ewma)
+	Alpha float64
+	// Window is how far back samples are kept (max, percentile)
+	Window time.Duration
+	// Percentile is in (0, 100] (percentile)
+	Percentile float64
+
+	spec string
+}
+
+// ParseSmoothing parses instant, ewma(alpha), max(window) or pXX(window),
+// e.g. "ewma(0.3)", "max(30s)", "p95(1m)". Empty means instant.
+func ParseSmoothing(spec string) (Smoothing, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" || spec == SmoothingInstant {
+		return Smoothing{Kind: SmoothingInstant, spec: SmoothingInstant}, nil
+	}
+
+	match := smoothingRegex.FindStringSubmatch(spec)
+	if match == nil {
+		return Smoothing{}, fmt.Errorf("invalid smoothing %q (valid: instant, ewma(alpha), max(window), pXX(window))", spec)
+	}
+
+	s := Smoothing{spec: spec}
+	arg := strings.TrimSpace(match[3])
+
+	if match[1] == SmoothingEWMA {
+		alpha, err := strconv.ParseFloat(arg, 64)
+		if err != nil || alpha <= 0 || alpha > 1 {
+			return Smoothing{}, fmt.Errorf("invalid smoothing %q: alpha must be in (0, 1]", spec)
+		}
+		s.Kind = SmoothingEWMA
+		s.Alpha = alpha
+		return s, nil
+	}
+
+	window, err := time.ParseDuration(arg)
+	if err != nil || window <= 0 {
+		return Smoothing{}, fmt.Errorf("invalid smoothing %q: window must be a positive duration", spec)
+	}
+	s.Window = window
+
+	if match[1] == SmoothingMax {
+		s.Kind = SmoothingMax
+		return s, nil
+	}
+
+	percentile, _ := strconv.ParseFloat(match[2], 64)
+	if percentile <= 0 || percentile > 100 {
+		return Smoothing{}, fmt.Errorf("invalid smoothing %q: percentile must be in (0, 100]", spec)
+	}
+	s.Kind = SmoothingPercentile
+	s.Percentile = percentile
+	return s, nil
+}
+
+// ParseSmoothingSpecs parses smoothing specs keyed by resource.
+func ParseSmoothingSpecs(specs map[string]string) (map[string]Smoothing, error) {
+	parsed := make(map[string]Smoothing, len(specs))
+	for resource, spec := range specs {
+		s, err := ParseSmoothing(spec)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", resource, err)
+		}
+		parsed[resource] = s
+	}
+	return parsed, nil
+}
+
+// String returns the spec the smoothing was parsed from.
+func (s Smoothing) String() string {
+	if s.spec == "" {
+		return SmoothingInstant
+	}
+	return s.spec
+}
+
+// timedSample is one sample of a smoothed series.
+type timedSample struct {
+	at    time.Time
+	value float64
+}
+
+// smoother aggregates one series, e.g. the utilization of one device.
+type smoother struct {
+	smoothing Smoothing
+	value     float64 // ewma state
+	primed    bool
+	samples   []timedSample // max and percentile window
+}
+
+// add records a sample and returns the smoothed value.
+func (s *smoother) add(at time.Time, v float64) float64 {
+	switch s.smoothing.Kind {
+	case SmoothingEWMA:
+		if !s.primed {
+			s.value, s.primed = v, true
+		} else {
+			s.value = s.smoothing.Alpha*v + (1-s.smoothing.Alpha)*s.value
+		}
+		return s.value
+
+	case SmoothingMax, SmoothingPercentile:
+		s.samples = append(s.samples, timedSample{at: at, value: v})
+		cutoff := at.Add(-s.smoothing.Window)
+		drop := 0
+		for drop < len(s.samples)-1 && s.samples[drop].at.Before(cutoff) {
+			drop++
+		}
+		s.samples = s.samples[drop:]
+
+		if s.smoothing.Kind == SmoothingMax {
+			highest := s.samples[0].value
+			for _, sample := range s.samples[1:] {
+				highest = max(highest, sample.value)
+			}
+			return highest
+		}
+		return percentile(s.samples, s.smoothing.Percentile)
+
+	default:
+		return v
+	}
+}
+
+// percentile returns the nearest-rank percentile of the sample values.
+func percentile(samples []timedSample, p float64) float64 {
+	values := make([]float64, len(samples))
+	for i, sample := range samples {
+		values[i] = sample.value
+	}
+	sort.Float64s(values)
+
+	rank := int(math.Ceil(p / 100 * float64(len(values))))
+	return values[max(rank-1, 0)]
+}
@@ -0,0 +1,118 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseSmoothing(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    Smoothing
+		wantErr bool
+	}{
+		{spec: "", want: Smoothing{Kind: SmoothingInstant}},
+		{spec: "instant", want: Smoothing{Kind: SmoothingInstant}},
+		{spec: "ewma(0.3)", want: Smoothing{Kind: SmoothingEWMA, Alpha: 0.3}},
+		{spec: "max(30s)", want: Smoothing{Kind: SmoothingMax, Window: 30 * time.Second}},
+		{spec: "p95(1m)", want: Smoothing{Kind: SmoothingPercentile, Window: time.Minute, Percentile: 95}},
+		{spec: "p99.9(5m)", want: Smoothing{Kind: SmoothingPercentile, Window: 5 * time.Minute, Percentile: 99.9}},
+		{spec: "ewma(0)", wantErr: true},
+		{spec: "ewma(1.5)", wantErr: true},
+		{spec: "max(-1s)", wantErr: true},
+		{spec: "max(30)", wantErr: true},
+		{spec: "p0(1m)", wantErr: true},
+		{spec: "p101(1m)", wantErr: true},
+		{spec: "avg(1m)", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := ParseSmoothing(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSmoothing(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			got.spec = ""
+			if got != tt.want {
+				t.Errorf("ParseSmoothing(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSmoother(t *testing.T) {
+	base := time.Unix(1000, 0)
+	samples := []float64{10, 90, 20, 30, 40}
+
+	tests := []struct {
+		spec string
+		want float64
+	}{
+		{"instant", 40},
+		// 10 -> 58 -> 35.2 -> 32.08 -> 36.832
+		{"ewma(0.6)", 36.832},
+		// Window of 2s at +4s keeps the samples from +2s on
+		{"max(2s)", 40},
+		{"max(10s)", 90},
+		{"p50(10s)", 30},
+		{"p80(10s)", 40},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			smoothing, err := ParseSmoothing(tt.spec)
+			if err != nil {
+				t.Fatalf("parse failed: %v", err)
+			}
+
+			s := &smoother{smoothing: smoothing}
+			var got float64
+			for i, v := range samples {
+				got = s.add(base.Add(time.Duration(i)*time.Second), v)
+			}
+
+			if got < tt.want-1e-9 || got > tt.want+1e-9 {
+				t.Errorf("expected %f, got %f", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestAggregator_Smoothing(t *testing.T) {
+	cpu := &mockMonitor{name: "cpu", data: &CPUState{UsagePercent: 90, Cores: []float64{90}}}
+	io := &mockMonitor{name: "io", data: &IOState{Devices: map[string]DeviceIOState{"sda": {UtilPercent: 80}}}}
+
+	agg := NewAggregator([]Monitor{cpu, io}, time.Second, testLogger())
+
+	smoothing, err := ParseSmoothingSpecs(map[string]string{"cpu": "max(1m)", "io": "ewma(0.5)"})
+	if err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	agg.SetSmoothing(smoothing)
+
+	agg.collect()
+	cpu.data = &CPUState{UsagePercent: 20, Cores: []float64{20}}
+	io.data = &IOState{Devices: map[string]DeviceIOState{"sda": {UtilPercent: 20}}}
+	agg.collect()
+
+	raw := agg.GetState()
+	if raw.CPU.UsagePercent != 20 {
+		t.Errorf("expected raw state to keep the latest sample, got %f", raw.CPU.UsagePercent)
+	}
+
+	smoothed := agg.GetSmoothedState()
+	if smoothed.CPU.UsagePercent != 90 || smoothed.CPU.Cores[0] != 90 {
+		t.Errorf("expected the spike to be held by max(1m), got %+v", smoothed.CPU)
+	}
+	if got := smoothed.IO.Devices["sda"].UtilPercent; got != 50 {
+		t.Errorf("expected ewma(0.5) of 80 and 20 to be 50, got %f", got)
+	}
+
+	specs := agg.Smoothing()
+	if specs["cpu"] != "max(1m)" || specs["memory"] != "instant" {
+		t.Errorf("unexpected smoothing specs: %v", specs)
+	}
+}
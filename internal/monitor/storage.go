@@ -1,38 +1,64 @@
 package monitor
 
 import (
+	"path/filepath"
+
 	"github.com/shirou/gopsutil/v4/disk"
 )
 
+// StorageMonitor reports space, inodes and mount health of the monitored
+// paths. The read-only flag is taken from /proc/self/mountinfo.
 type StorageMonitor struct {
-	paths []string
+	procRoot string
+	paths    []string
 }
 
 func NewStorageMonitor(paths []string) *StorageMonitor {
+	return NewStorageMonitorWithProcRoot(DefaultProcRoot, paths)
+}
+
+// NewStorageMonitorWithProcRoot creates a storage monitor reading mountinfo
+// from a custom procfs root.
+func NewStorageMonitorWithProcRoot(procRoot string, paths []string) *StorageMonitor {
+	if procRoot == "" {
+		procRoot = DefaultProcRoot
+	}
 	if len(paths) == 0 {
 		paths = []string{"/"}
 	}
-	return &StorageMonitor{paths: paths}
+	return &StorageMonitor{procRoot: procRoot, paths: paths}
 }
 
 func (m *StorageMonitor) Name() string {
 	return "storage"
 }
 
-func (m *StorageMonitor) Collect() (any, error) {
+func (m *StorageMonitor) Section() string {
+	return SectionStorage
+}
+
+func (m *StorageMonitor) Collect() (Sample, error) {
 	state := make(StorageState)
 
+	mounts := readMountDevices(filepath.Join(m.procRoot, "self", "mountinfo"))
+
 	for _, path := range m.paths {
 		usage, err := disk.Usage(path)
 		if err != nil {
-			// Skip paths that are not accessible
+			// Reported rather than skipped, so a missing mount is not
+			// mistaken for a healthy one
+			state[path] = DiskState{Error: err.Error()}
 			continue
 		}
 
+		mnt, _ := mountFor(mounts, path)
 		state[path] = DiskState{
 			UsedBytes:    usage.Used,
 			TotalBytes:   usage.Total,
 			UsagePercent: usage.UsedPercent,
+			InodesUsed:   usage.InodesUsed,
+			InodesTotal:  usage.InodesTotal,
+			ReadOnly:     mnt.readOnly,
 		}
 	}
 
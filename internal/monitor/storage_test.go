@@ -1,6 +1,7 @@
 package monitor
 
 import (
+	"path/filepath"
 	"testing"
 )
 
@@ -70,8 +71,87 @@ func TestStorageMonitor_NonExistentPath(t *testing.T) {
 		t.Fatalf("expected StorageState, got %T", data)
 	}
 
-	// Non-existent path should be skipped
-	if len(state) != 0 {
-		t.Errorf("expected empty state for non-existent path, got %d entries", len(state))
+	// Non-existent path should be reported as unavailable
+	diskState, exists := state["/nonexistent/path/that/does/not/exist"]
+	if !exists {
+		t.Fatal("expected non-existent path in storage state")
+	}
+	if diskState.Error == "" {
+		t.Error("expected error for non-existent path")
+	}
+	if diskState.TotalBytes != 0 {
+		t.Errorf("expected no usage for non-existent path, got %d total bytes", diskState.TotalBytes)
+	}
+}
+
+func TestStorageMonitor_ReadOnly(t *testing.T) {
+	procRoot := t.TempDir()
+	dir := t.TempDir()
+	mountinfo := "22 1 259:2 / / rw,relatime shared:1 - ext4 /dev/nvme0n1p2 rw\n" +
+		"24 22 8:17 / " + dir + " ro,relatime shared:2 - xfs /dev/sdb1 rw\n"
+	writeSysfsFile(t, filepath.Join(procRoot, "self", "mountinfo"), mountinfo)
+
+	data, err := NewStorageMonitorWithProcRoot(procRoot, []string{"/", dir}).Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	state := data.(StorageState)
+	if state["/"].ReadOnly {
+		t.Error("expected / to be writable")
+	}
+	if !state[dir].ReadOnly {
+		t.Errorf("expected %s to be read-only", dir)
+	}
+	if state[dir].InodesTotal > 0 && state[dir].InodesUsed > state[dir].InodesTotal {
+		t.Errorf("used inodes (%d) should not exceed total (%d)", state[dir].InodesUsed, state[dir].InodesTotal)
+	}
+}
+
+func TestReadMountDevices_ReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mountinfo")
+	mountinfo := "22 1 259:2 / / rw,relatime shared:1 - ext4 /dev/nvme0n1p2 rw\n" +
+		"24 22 8:17 / /data rw,relatime shared:2 - ext4 /dev/sdb1 ro,errors=remount-ro\n" +
+		"25 22 8:33 / /backup ro,nosuid - ext4 /dev/sdc1 rw\n"
+	writeSysfsFile(t, path, mountinfo)
+
+	mounts := readMountDevices(path)
+
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/", false},
+		{"/data/x", true}, // filesystem remounted read-only after errors
+		{"/backup", true}, // read-only mount of a writable filesystem
+		{"/database", false},
+	}
+
+	for _, tt := range tests {
+		mnt, ok := mountFor(mounts, tt.path)
+		if !ok {
+			t.Fatalf("no mount found for %s", tt.path)
+		}
+		if mnt.readOnly != tt.want {
+			t.Errorf("%s: expected read-only %v, got %v", tt.path, tt.want, mnt.readOnly)
+		}
+	}
+}
+
+func TestDiskState_FreeInodesPercent(t *testing.T) {
+	tests := []struct {
+		used, total uint64
+		want        float64
+	}{
+		{25, 100, 75},
+		{100, 100, 0},
+		{0, 0, 100}, // no inode table
+	}
+
+	for _, tt := range tests {
+		disk := DiskState{InodesUsed: tt.used, InodesTotal: tt.total}
+		if got := disk.FreeInodesPercent(); got != tt.want {
+			t.Errorf("%d/%d: expected %f, got %f", tt.used, tt.total, tt.want, got)
+		}
 	}
 }
@@ -0,0 +1,132 @@
+package monitor
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SwapMonitor collects swap usage from /proc/meminfo and paging rates
+// (pswpin, pswpout, pgmajfault) from /proc/vmstat.
+// Graceful degradation: on systems without these files, returns zero values.
+type SwapMonitor struct {
+	procRoot string
+	prev     vmstatCounters
+	prevTime time.Time
+	mu       sync.Mutex
+}
+
+// vmstatCounters holds the /proc/vmstat counters used by SwapMonitor.
+type vmstatCounters struct {
+	pswpin     uint64
+	pswpout    uint64
+	pgmajfault uint64
+}
+
+// NewSwapMonitor creates a swap monitor reading from the given procfs root.
+func NewSwapMonitor(procRoot string) *SwapMonitor {
+	if procRoot == "" {
+		procRoot = DefaultProcRoot
+	}
+
+	return &SwapMonitor{procRoot: procRoot}
+}
+
+func (m *SwapMonitor) Name() string {
+	return "swap"
+}
+
+func (m *SwapMonitor) Section() string {
+	return SectionSwap
+}
+
+func (m *SwapMonitor) Collect() (Sample, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := &SwapState{}
+
+	meminfo, err := readKeyValueFile(filepath.Join(m.procRoot, "meminfo"))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+
+	// meminfo values are in kB
+	total := meminfo["SwapTotal"] * 1024
+	free := meminfo["SwapFree"] * 1024
+	if total > 0 {
+		state.TotalBytes = total
+		if free < total {
+			state.UsedBytes = total - free
+		}
+		state.UsagePercent = float64(state.UsedBytes) / float64(total) * 100
+	}
+
+	vmstat, err := readKeyValueFile(filepath.Join(m.procRoot, "vmstat"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return state, nil
+		}
+		return nil, err
+	}
+
+	cur := vmstatCounters{
+		pswpin:     vmstat["pswpin"],
+		pswpout:    vmstat["pswpout"],
+		pgmajfault: vmstat["pgmajfault"],
+	}
+	now := time.Now()
+
+	// Rates need a previous sample
+	if !m.prevTime.IsZero() {
+		elapsed := now.Sub(m.prevTime).Seconds()
+		if elapsed > 0 {
+			state.PagesInPerSec = float64(counterRate(m.prev.pswpin, cur.pswpin, elapsed))
+			state.PagesOutPerSec = float64(counterRate(m.prev.pswpout, cur.pswpout, elapsed))
+			state.MajorFaultsPerSec = float64(counterRate(m.prev.pgmajfault, cur.pgmajfault, elapsed))
+		}
+	}
+
+	m.prev = cur
+	m.prevTime = now
+
+	return state, nil
+}
+
+// readKeyValueFile parses files of "key value [unit]" or "key: value [unit]"
+// lines, such as /proc/meminfo and /proc/vmstat.
+func readKeyValueFile(path string) (map[string]uint64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	values := make(map[string]uint64)
+	scanner := bufio.NewScanner(f)
+
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		key := strings.TrimSuffix(fields[0], ":")
+		v, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s in %s: %w", key, path, err)
+		}
+		values[key] = v
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
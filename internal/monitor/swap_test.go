@@ -0,0 +1,108 @@
+package monitor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeSwapFixtures writes /proc/meminfo and /proc/vmstat fixtures under root.
+func writeSwapFixtures(t *testing.T, root string, swapTotalKB, swapFreeKB, pswpin, pswpout, pgmajfault uint64) {
+	t.Helper()
+
+	meminfo := fmt.Sprintf("MemTotal:       16384000 kB\nMemFree:         8192000 kB\n"+
+		"SwapTotal:      %d kB\nSwapFree:       %d kB\n", swapTotalKB, swapFreeKB)
+	vmstat := fmt.Sprintf("nr_free_pages 2048000\npgpgin 100\npgpgout 200\n"+
+		"pswpin %d\npswpout %d\npgfault 999999\npgmajfault %d\n", pswpin, pswpout, pgmajfault)
+
+	if err := os.WriteFile(filepath.Join(root, "meminfo"), []byte(meminfo), 0644); err != nil {
+		t.Fatalf("failed to write meminfo: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "vmstat"), []byte(vmstat), 0644); err != nil {
+		t.Fatalf("failed to write vmstat: %v", err)
+	}
+}
+
+func TestSwapMonitor_Name(t *testing.T) {
+	m := NewSwapMonitor(t.TempDir())
+	if m.Name() != "swap" {
+		t.Errorf("expected name 'swap', got %s", m.Name())
+	}
+}
+
+func TestSwapMonitor_Collect(t *testing.T) {
+	root := t.TempDir()
+	writeSwapFixtures(t, root, 4194304, 3145728, 1000, 2000, 50) // 4 GiB total, 1 GiB used
+
+	m := NewSwapMonitor(root)
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("first collect failed: %v", err)
+	}
+
+	state, ok := data.(*SwapState)
+	if !ok {
+		t.Fatalf("expected *SwapState, got %T", data)
+	}
+
+	if state.TotalBytes != 4294967296 {
+		t.Errorf("expected 4294967296 total, got %d", state.TotalBytes)
+	}
+	if state.UsedBytes != 1073741824 {
+		t.Errorf("expected 1073741824 used, got %d", state.UsedBytes)
+	}
+	if state.UsagePercent != 25 {
+		t.Errorf("expected 25%% usage, got %f", state.UsagePercent)
+	}
+	if state.PagesPerSec() != 0 {
+		t.Errorf("expected zero paging on first call, got %f", state.PagesPerSec())
+	}
+
+	// Pretend the previous sample was taken one second ago
+	m.prevTime = time.Now().Add(-time.Second)
+	writeSwapFixtures(t, root, 4194304, 3145728, 1500, 2300, 250)
+
+	data, err = m.Collect()
+	if err != nil {
+		t.Fatalf("second collect failed: %v", err)
+	}
+
+	state = data.(*SwapState)
+	if state.PagesInPerSec < 450 || state.PagesInPerSec > 500 {
+		t.Errorf("expected ~500 pages in/s, got %f", state.PagesInPerSec)
+	}
+	if state.PagesOutPerSec < 270 || state.PagesOutPerSec > 300 {
+		t.Errorf("expected ~300 pages out/s, got %f", state.PagesOutPerSec)
+	}
+	if state.MajorFaultsPerSec < 180 || state.MajorFaultsPerSec > 200 {
+		t.Errorf("expected ~200 major faults/s, got %f", state.MajorFaultsPerSec)
+	}
+}
+
+func TestSwapMonitor_NoSwap(t *testing.T) {
+	root := t.TempDir()
+	writeSwapFixtures(t, root, 0, 0, 0, 0, 0)
+
+	data, err := NewSwapMonitor(root).Collect()
+	if err != nil {
+		t.Fatalf("collect failed: %v", err)
+	}
+
+	if state := data.(*SwapState); state.UsagePercent != 0 || state.TotalBytes != 0 {
+		t.Errorf("expected zero swap, got %+v", state)
+	}
+}
+
+func TestSwapMonitor_GracefulDegradation(t *testing.T) {
+	data, err := NewSwapMonitor(t.TempDir()).Collect()
+	if err != nil {
+		t.Fatalf("collect should not fail without procfs: %v", err)
+	}
+
+	if state := data.(*SwapState); *state != (SwapState{}) {
+		t.Errorf("expected zero swap state, got %+v", state)
+	}
+}
@@ -0,0 +1,235 @@
+package monitor
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	// thermalZoneRegex matches thermal zone directories (thermal_zone0, ...).
+	thermalZoneRegex = regexp.MustCompile(`^thermal_zone\d+$`)
+	// hwmonTempRegex matches hwmon temperature inputs (temp1_input, ...).
+	hwmonTempRegex = regexp.MustCompile(`^temp(\d+)_input$`)
+	// cpuDirRegex matches per-CPU directories (cpu0, cpu1, ...).
+	cpuDirRegex = regexp.MustCompile(`^cpu(\d+)$`)
+)
+
+// gpuHwmonNames are hwmon drivers of GPUs, whose temperature is reported
+// in GPUState and checked against the GPU limit instead.
+var gpuHwmonNames = map[string]bool{
+	"amdgpu":  true,
+	"radeon":  true,
+	"nouveau": true,
+}
+
+// ThermalMonitor collects temperatures from /sys/class/thermal and
+// /sys/class/hwmon, per-core frequency from cpufreq and thermal throttle
+// counters (Intel only).
+// Graceful degradation: missing sysfs files are reported as empty values.
+type ThermalMonitor struct {
+	sysfsRoot string
+
+	prevThrottles uint64
+	prevTime      time.Time
+	mu            sync.Mutex
+}
+
+// NewThermalMonitor creates a thermal monitor reading from the given sysfs root.
+func NewThermalMonitor(sysfsRoot string) *ThermalMonitor {
+	if sysfsRoot == "" {
+		sysfsRoot = DefaultSysfsRoot
+	}
+
+	return &ThermalMonitor{sysfsRoot: sysfsRoot}
+}
+
+func (m *ThermalMonitor) Name() string {
+	return "thermal"
+}
+
+func (m *ThermalMonitor) Section() string {
+	return SectionThermal
+}
+
+func (m *ThermalMonitor) Collect() (Sample, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := &ThermalState{
+		Sensors: make(map[string]float64),
+	}
+
+	m.readThermalZones(state.Sensors)
+	m.readHwmon(state.Sensors)
+	state.Cores = m.readCoreFrequencies()
+
+	throttles, ok := m.readThrottleCount()
+	state.ThrottleCount = throttles
+
+	now := time.Now()
+	elapsed := now.Sub(m.prevTime).Seconds()
+	if ok && !m.prevTime.IsZero() && elapsed > 0 {
+		state.ThrottleEventsPerSec = float64(counterRate(m.prevThrottles, throttles, elapsed))
+	}
+
+	m.prevThrottles = throttles
+	m.prevTime = now
+
+	return state, nil
+}
+
+// readThermalZones adds thermal zone temperatures keyed by zone type.
+func (m *ThermalMonitor) readThermalZones(sensors map[string]float64) {
+	dir := filepath.Join(m.sysfsRoot, "class", "thermal")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		if !thermalZoneRegex.MatchString(entry.Name()) {
+			continue
+		}
+		zone := filepath.Join(dir, entry.Name())
+		celsius, ok := readMilliCelsius(filepath.Join(zone, "temp"))
+		if !ok {
+			continue
+		}
+
+		name := readSysfsString(filepath.Join(zone, "type"))
+		if name == "" {
+			name = entry.Name()
+		}
+		addSensor(sensors, name, entry.Name(), celsius)
+	}
+}
+
+// readHwmon adds hwmon temperatures keyed by "<driver>/<label>".
+func (m *ThermalMonitor) readHwmon(sensors map[string]float64) {
+	dir := filepath.Join(m.sysfsRoot, "class", "hwmon")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		hwmon := filepath.Join(dir, entry.Name())
+		driver := readSysfsString(filepath.Join(hwmon, "name"))
+		if gpuHwmonNames[driver] {
+			continue
+		}
+		if driver == "" {
+			driver = entry.Name()
+		}
+
+		inputs, err := os.ReadDir(hwmon)
+		if err != nil {
+			continue
+		}
+		for _, input := range inputs {
+			match := hwmonTempRegex.FindStringSubmatch(input.Name())
+			if match == nil {
+				continue
+			}
+			celsius, ok := readMilliCelsius(filepath.Join(hwmon, input.Name()))
+			if !ok {
+				continue
+			}
+
+			label := readSysfsString(filepath.Join(hwmon, "temp"+match[1]+"_label"))
+			if label == "" {
+				label = "temp" + match[1]
+			}
+			addSensor(sensors, driver+"/"+label, entry.Name(), celsius)
+		}
+	}
+}
+
+// readCoreFrequencies reads current and maximum frequency of each online CPU.
+func (m *ThermalMonitor) readCoreFrequencies() []CoreFreqState {
+	dir := filepath.Join(m.sysfsRoot, "devices", "system", "cpu")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var cores []CoreFreqState
+	for _, entry := range entries {
+		match := cpuDirRegex.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		cpufreq := filepath.Join(dir, entry.Name(), "cpufreq")
+		cur := readSysfsUint(filepath.Join(cpufreq, "scaling_cur_freq"))
+		if cur == 0 {
+			continue
+		}
+
+		index, _ := strconv.Atoi(match[1])
+		// cpufreq reports kHz
+		cores = append(cores, CoreFreqState{
+			CPU:    index,
+			CurMHz: float64(cur) / 1000,
+			MaxMHz: float64(readSysfsUint(filepath.Join(cpufreq, "cpuinfo_max_freq"))) / 1000,
+		})
+	}
+
+	sort.Slice(cores, func(i, j int) bool { return cores[i].CPU < cores[j].CPU })
+	return cores
+}
+
+// readThrottleCount sums core and package thermal throttle events over all
+// CPUs. Returns false if no throttle counters are exposed.
+func (m *ThermalMonitor) readThrottleCount() (uint64, bool) {
+	dir := filepath.Join(m.sysfsRoot, "devices", "system", "cpu")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, false
+	}
+
+	var total uint64
+	found := false
+	for _, entry := range entries {
+		if !cpuDirRegex.MatchString(entry.Name()) {
+			continue
+		}
+		throttle := filepath.Join(dir, entry.Name(), "thermal_throttle")
+		for _, name := range []string{"core_throttle_count", "package_throttle_count"} {
+			if v := readSysfsString(filepath.Join(throttle, name)); v != "" {
+				n, err := strconv.ParseUint(v, 10, 64)
+				if err != nil {
+					continue
+				}
+				total += n
+				found = true
+			}
+		}
+	}
+
+	return total, found
+}
+
+// addSensor stores a temperature, disambiguating duplicate names with the
+// sysfs directory they were read from.
+func addSensor(sensors map[string]float64, name, dir string, celsius float64) {
+	if _, exists := sensors[name]; exists {
+		name += "#" + dir
+	}
+	sensors[name] = celsius
+}
+
+// readMilliCelsius reads a temperature in millidegrees Celsius.
+// Sensors can report negative values, so the value is parsed as signed.
+func readMilliCelsius(path string) (float64, bool) {
+	v, err := strconv.ParseInt(strings.TrimSpace(readSysfsString(path)), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return float64(v) / 1000, true
+}
@@ -0,0 +1,127 @@
+package monitor
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// fakeThermalSysfs builds a sysfs tree with one thermal zone, coretemp and
+// amdgpu hwmon devices, and two CPUs with cpufreq and throttle counters.
+func fakeThermalSysfs(t *testing.T) string {
+	t.Helper()
+
+	root := t.TempDir()
+	zone := filepath.Join(root, "class", "thermal", "thermal_zone0")
+	writeSysfsFile(t, filepath.Join(zone, "type"), "x86_pkg_temp\n")
+	writeSysfsFile(t, filepath.Join(zone, "temp"), "71000\n")
+	// cooling devices live next to zones and must be ignored
+	writeSysfsFile(t, filepath.Join(root, "class", "thermal", "cooling_device0", "type"), "Processor\n")
+
+	coretemp := filepath.Join(root, "class", "hwmon", "hwmon1")
+	writeSysfsFile(t, filepath.Join(coretemp, "name"), "coretemp\n")
+	writeSysfsFile(t, filepath.Join(coretemp, "temp1_input"), "72000\n")
+	writeSysfsFile(t, filepath.Join(coretemp, "temp1_label"), "Package id 0\n")
+	writeSysfsFile(t, filepath.Join(coretemp, "temp2_input"), "68500\n")
+
+	amdgpu := filepath.Join(root, "class", "hwmon", "hwmon2")
+	writeSysfsFile(t, filepath.Join(amdgpu, "name"), "amdgpu\n")
+	writeSysfsFile(t, filepath.Join(amdgpu, "temp1_input"), "95000\n")
+
+	for cpu, cur := range []string{"2400000", "3600000"} {
+		dir := filepath.Join(root, "devices", "system", "cpu", fmt.Sprintf("cpu%d", cpu))
+		writeSysfsFile(t, filepath.Join(dir, "cpufreq", "scaling_cur_freq"), cur+"\n")
+		writeSysfsFile(t, filepath.Join(dir, "cpufreq", "cpuinfo_max_freq"), "4800000\n")
+	}
+	writeThrottleCounts(t, root, 10, 3)
+
+	return root
+}
+
+// writeThrottleCounts sets core and package throttle counters of cpu0.
+func writeThrottleCounts(t *testing.T, root string, core, pkg uint64) {
+	t.Helper()
+
+	dir := filepath.Join(root, "devices", "system", "cpu", "cpu0", "thermal_throttle")
+	writeSysfsFile(t, filepath.Join(dir, "core_throttle_count"), fmt.Sprintf("%d\n", core))
+	writeSysfsFile(t, filepath.Join(dir, "package_throttle_count"), fmt.Sprintf("%d\n", pkg))
+}
+
+func TestThermalMonitor_Name(t *testing.T) {
+	m := NewThermalMonitor(t.TempDir())
+	if m.Name() != "thermal" {
+		t.Errorf("expected name 'thermal', got %s", m.Name())
+	}
+}
+
+func TestThermalMonitor_Collect(t *testing.T) {
+	root := fakeThermalSysfs(t)
+	m := NewThermalMonitor(root)
+
+	data, err := m.Collect()
+	if err != nil {
+		t.Fatalf("first collect failed: %v", err)
+	}
+
+	state, ok := data.(*ThermalState)
+	if !ok {
+		t.Fatalf("expected *ThermalState, got %T", data)
+	}
+
+	want := map[string]float64{
+		"x86_pkg_temp":          71,
+		"coretemp/Package id 0": 72,
+		"coretemp/temp2":        68.5,
+	}
+	if len(state.Sensors) != len(want) {
+		t.Errorf("expected sensors %v, got %v", want, state.Sensors)
+	}
+	for name, celsius := range want {
+		if state.Sensors[name] != celsius {
+			t.Errorf("%s: expected %.1f, got %.1f", name, celsius, state.Sensors[name])
+		}
+	}
+	if state.MaxCelsius() != 72 {
+		t.Errorf("expected hottest 72 (GPU excluded), got %f", state.MaxCelsius())
+	}
+
+	if len(state.Cores) != 2 || state.Cores[1].CPU != 1 || state.Cores[1].CurMHz != 3600 || state.Cores[1].MaxMHz != 4800 {
+		t.Errorf("unexpected cores: %+v", state.Cores)
+	}
+	if state.FreqPercent() != 62.5 {
+		t.Errorf("expected 62.5%% of max frequency, got %f", state.FreqPercent())
+	}
+	if state.ThrottleCount != 13 || state.ThrottleEventsPerSec != 0 {
+		t.Errorf("expected 13 throttles and no rate on first call, got %+v", state)
+	}
+
+	// Pretend the previous sample was taken one second ago
+	m.prevTime = time.Now().Add(-time.Second)
+	writeThrottleCounts(t, root, 15, 5)
+
+	data, err = m.Collect()
+	if err != nil {
+		t.Fatalf("second collect failed: %v", err)
+	}
+
+	state = data.(*ThermalState)
+	if state.ThrottleEventsPerSec < 6 || state.ThrottleEventsPerSec > 7 {
+		t.Errorf("expected ~7 throttle events/s, got %f", state.ThrottleEventsPerSec)
+	}
+}
+
+func TestThermalMonitor_GracefulDegradation(t *testing.T) {
+	data, err := NewThermalMonitor(t.TempDir()).Collect()
+	if err != nil {
+		t.Fatalf("collect should not fail without sysfs: %v", err)
+	}
+
+	state := data.(*ThermalState)
+	if len(state.Sensors) != 0 || len(state.Cores) != 0 || state.ThrottleCount != 0 {
+		t.Errorf("expected empty thermal state, got %+v", state)
+	}
+	if state.MaxCelsius() != 0 || state.FreqPercent() != 0 {
+		t.Errorf("expected zero aggregates, got %f / %f", state.MaxCelsius(), state.FreqPercent())
+	}
+}
@@ -2,10 +2,16 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/haskel/capfox/internal/capacity"
+	"github.com/haskel/capfox/internal/decision"
 	"github.com/haskel/capfox/internal/learning"
+	"github.com/haskel/capfox/internal/monitor"
+	"github.com/haskel/capfox/internal/trace"
 )
 
 type InfoResponse struct {
@@ -41,13 +47,15 @@ func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
 
 // ReadyResponse is the response for /ready endpoint.
 type ReadyResponse struct {
-	Ready   bool   `json:"ready"`
-	Message string `json:"message,omitempty"`
+	Ready    bool                `json:"ready"`
+	Message  string              `json:"message,omitempty"`
+	Monitors monitor.HealthState `json:"monitors,omitempty"`
 }
 
 // handleReady returns readiness status.
 // Returns 200 OK when the system is ready to serve traffic,
-// 503 Service Unavailable when not ready.
+// 503 Service Unavailable when not ready or when a monitor has failed
+// thresholds.health.max_failures times in a row.
 func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
 	if !s.aggregator.IsReady() {
 		resp := ReadyResponse{
@@ -58,15 +66,39 @@ func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	health := s.aggregator.Health()
+	if maxFailures := s.capacityManager.GetThresholds().Health.MaxFailures; maxFailures > 0 {
+		if failing := health.Failing(maxFailures); len(failing) > 0 {
+			resp := ReadyResponse{
+				Ready:    false,
+				Message:  "stale metrics from monitors: " + strings.Join(failing, ", "),
+				Monitors: health,
+			}
+			s.writeJSON(w, http.StatusServiceUnavailable, resp)
+			return
+		}
+	}
+
 	resp := ReadyResponse{
-		Ready: true,
+		Ready:    true,
+		Monitors: health,
 	}
 	s.writeJSON(w, http.StatusOK, resp)
 }
 
+// StatusResponse is the response for /status: the system state with the
+// active leases of POST /v2/reserve.
+type StatusResponse struct {
+	*monitor.SystemState
+	Leases []decision.Lease `json:"leases,omitempty"`
+}
+
 func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
-	state := s.aggregator.GetState()
-	s.writeJSON(w, http.StatusOK, state)
+	resp := StatusResponse{SystemState: s.aggregator.GetState()}
+	if s.v2 != nil && s.v2.DecisionManager != nil {
+		resp.Leases = s.v2.DecisionManager.Leases().Active(time.Now())
+	}
+	s.writeJSON(w, http.StatusOK, resp)
 }
 
 func (s *Server) handleAsk(w http.ResponseWriter, r *http.Request) {
@@ -80,7 +112,13 @@ func (s *Server) handleAsk(w http.ResponseWriter, r *http.Request) {
 	// Check for reason flag in query param or header
 	withReasons := r.URL.Query().Get("reason") == "true" || r.Header.Get("X-Reason") == "true"
 
-	resp := s.capacityManager.Ask(req, withReasons)
+	var resp capacity.AskResponse
+	if s.currentConfig().Decision.RouteV1Ask && s.v2 != nil && s.v2.DecisionManager != nil {
+		resp = s.askDecisionEngine(req, withReasons)
+	} else {
+		resp = s.capacityManager.Ask(req, withReasons)
+	}
+	s.recorder.Record(trace.TypeAsk, req, resp)
 
 	if resp.Allowed {
 		s.writeJSON(w, http.StatusOK, resp)
@@ -89,6 +127,39 @@ func (s *Server) handleAsk(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// askDecisionEngine answers a V1 ask request using the V2 decision engine.
+// The response keeps the V1 shape so existing clients are unaffected.
+func (s *Server) askDecisionEngine(req capacity.AskRequest, withReasons bool) capacity.AskResponse {
+	var resources *decision.ResourceEstimate
+	if req.Resources != nil {
+		resources = &decision.ResourceEstimate{
+			CPU:    req.Resources.CPU,
+			GPU:    req.Resources.GPU,
+			Memory: req.Resources.Memory,
+		}
+	}
+
+	result := s.v2.DecisionManager.Decide(req.Task, req.Complexity, req.Priority, resources)
+
+	resp := capacity.AskResponse{
+		Allowed: result.Allowed,
+	}
+
+	if len(result.GPUs) > 0 {
+		resp.GPUs = result.GPUs
+		resp.VisibleDevices = monitor.VisibleDevices(result.GPUs)
+	}
+
+	if withReasons && !result.Allowed {
+		resp.Reasons = make([]string, len(result.Reasons))
+		for i, r := range result.Reasons {
+			resp.Reasons[i] = string(r)
+		}
+	}
+
+	return resp
+}
+
 func (s *Server) handleTaskStart(w http.ResponseWriter, r *http.Request) {
 	var req learning.TaskStartRequest
 
@@ -102,19 +173,79 @@ func (s *Server) handleTaskStart(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Notify learning engine about task start
-	if s.learningEngine != nil {
-		s.learningEngine.NotifyTaskStart(req.Task, req.Complexity)
-	}
+	s.recorder.Record(trace.TypeNotify, req, nil)
 
 	resp := learning.TaskStartResponse{
 		Received: true,
 		Task:     req.Task,
 	}
 
+	// Notify learning engine about task start
+	if s.learningEngine != nil {
+		if req.Attributed {
+			resp.TaskID = s.learningEngine.NotifyAttributedTaskStart(req.Task, req.Complexity)
+		} else {
+			resp.TaskID = s.learningEngine.NotifyTaskStart(req.Task, req.Complexity)
+		}
+		resp.ObserveAfterMS = s.learningEngine.ObservationDelay().Milliseconds()
+	}
+
+	// The task is pending load for queue_aware until it is observed
+	if resp.TaskID != "" && s.v2 != nil && s.v2.DecisionManager != nil {
+		pending := decision.PendingTask{
+			ID:         resp.TaskID,
+			Task:       req.Task,
+			Complexity: req.Complexity,
+			StartedAt:  time.Now(),
+		}
+		if s.v2.Model != nil {
+			pending.Predicted = s.v2.Model.Predict(req.Task, req.Complexity)
+		}
+		s.v2.DecisionManager.AddPendingTask(pending)
+	}
+
 	s.writeJSON(w, http.StatusOK, resp)
 }
 
+// handleTaskUsage handles POST /task/usage, the measured usage of a task
+// announced with attributed=true.
+func (s *Server) handleTaskUsage(w http.ResponseWriter, r *http.Request) {
+	var req learning.TaskUsageRequest
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if req.TaskID == "" {
+		http.Error(w, "task_id field is required", http.StatusBadRequest)
+		return
+	}
+	if req.WallSeconds <= 0 || req.CPUSeconds < 0 {
+		http.Error(w, "wall_seconds must be positive and cpu_seconds non-negative", http.StatusBadRequest)
+		return
+	}
+
+	if s.learningEngine == nil {
+		http.Error(w, "learning engine not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	if err := s.learningEngine.ReportTaskUsage(req.TaskID, req.ProcessUsage); err != nil {
+		if errors.Is(err, learning.ErrTaskNotPending) {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, learning.TaskUsageResponse{
+		Received: true,
+		TaskID:   req.TaskID,
+	})
+}
+
 func (s *Server) handleStats(w http.ResponseWriter, r *http.Request) {
 	// Check for specific task query
 	taskName := r.URL.Query().Get("task")
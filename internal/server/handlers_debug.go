@@ -55,7 +55,7 @@ func (s *Server) handleDebugStatus(w http.ResponseWriter, r *http.Request) {
 	state := s.aggregator.GetState()
 
 	resp := map[string]any{
-		"debug_enabled": s.config.Debug.Enabled,
+		"debug_enabled": s.currentConfig().Debug.Enabled,
 		"current_state": map[string]any{
 			"cpu":    state.CPU.UsagePercent,
 			"memory": state.Memory.UsagePercent,
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/haskel/capfox/internal/config"
+	"github.com/haskel/capfox/internal/decision"
+	"github.com/haskel/capfox/internal/trace"
+)
+
+// ReserveRequest is the request body for POST /v2/reserve.
+type ReserveRequest struct {
+	AskRequestV2
+	// TTLSec is how long the lease lasts, 0 uses decision.lease_ttl_sec
+	TTLSec int `json:"ttl_sec,omitempty"`
+}
+
+// ReserveResponse is the response for POST /v2/reserve.
+type ReserveResponse struct {
+	AskResponseV2
+	// Lease is the reservation, omitted when the task is denied
+	Lease *decision.Lease `json:"lease,omitempty"`
+}
+
+// HeartbeatRequest is the optional body for POST /v2/reserve/{id}/heartbeat.
+type HeartbeatRequest struct {
+	// TTLSec is how far the lease is extended, 0 uses the TTL it was
+	// created with
+	TTLSec int `json:"ttl_sec,omitempty"`
+}
+
+// handleReserve handles POST /v2/reserve: a /v2/ask decision that, when
+// allowed, leases the predicted impact of the task until it is released.
+func (s *Server) handleReserve(w http.ResponseWriter, r *http.Request) {
+	if s.v2 == nil || s.v2.DecisionManager == nil {
+		http.Error(w, "decision engine not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	var req ReserveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.TTLSec < 0 || req.TTLSec > config.MaxLeaseTTLSec {
+		http.Error(w, fmt.Sprintf("ttl_sec must be between 0 and %d", config.MaxLeaseTTLSec), http.StatusBadRequest)
+		return
+	}
+
+	ttl := s.currentConfig().Decision.LeaseTTL()
+	if req.TTLSec > 0 {
+		ttl = time.Duration(req.TTLSec) * time.Second
+	}
+
+	result, lease := s.v2.DecisionManager.Reserve(req.Task, req.Complexity, req.Priority, req.Resources, ttl)
+
+	resp := ReserveResponse{
+		AskResponseV2: newAskResponseV2(result),
+		Lease:         lease,
+	}
+	s.recorder.Record(trace.TypeDecision, req, resp)
+
+	if lease == nil {
+		s.writeJSON(w, http.StatusServiceUnavailable, resp)
+		return
+	}
+
+	s.saveLeases()
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+// handleRelease handles DELETE /v2/reserve/{id}.
+func (s *Server) handleRelease(w http.ResponseWriter, r *http.Request) {
+	if s.v2 == nil || s.v2.DecisionManager == nil {
+		http.Error(w, "decision engine not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	if !s.v2.DecisionManager.Leases().Release(r.PathValue("id"), time.Now()) {
+		http.Error(w, "lease not found", http.StatusNotFound)
+		return
+	}
+
+	s.saveLeases()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleHeartbeat handles POST /v2/reserve/{id}/heartbeat.
+func (s *Server) handleHeartbeat(w http.ResponseWriter, r *http.Request) {
+	if s.v2 == nil || s.v2.DecisionManager == nil {
+		http.Error(w, "decision engine not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	// The body is optional
+	var req HeartbeatRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.TTLSec < 0 || req.TTLSec > config.MaxLeaseTTLSec {
+		http.Error(w, fmt.Sprintf("ttl_sec must be between 0 and %d", config.MaxLeaseTTLSec), http.StatusBadRequest)
+		return
+	}
+
+	ttl := time.Duration(req.TTLSec) * time.Second
+	lease, ok := s.v2.DecisionManager.Leases().Heartbeat(r.PathValue("id"), ttl, time.Now())
+	if !ok {
+		http.Error(w, "lease not found", http.StatusNotFound)
+		return
+	}
+
+	s.saveLeases()
+	s.writeJSON(w, http.StatusOK, lease)
+}
+
+// saveLeases persists the leases after a change. Failures are logged, the
+// leases stay valid in memory.
+func (s *Server) saveLeases() {
+	if s.v2.LeaseStorage == nil {
+		return
+	}
+	if err := s.v2.LeaseStorage.SaveLeases(s.v2.DecisionManager.Leases()); err != nil {
+		s.logger.Error("failed to save leases", "error", err)
+	}
+}
@@ -4,17 +4,27 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"gopkg.in/yaml.v3"
+
 	"github.com/haskel/capfox/internal/capacity"
 	"github.com/haskel/capfox/internal/config"
+	"github.com/haskel/capfox/internal/decision"
+	"github.com/haskel/capfox/internal/decision/model"
+	"github.com/haskel/capfox/internal/decision/strategy"
 	"github.com/haskel/capfox/internal/learning"
 	"github.com/haskel/capfox/internal/monitor"
+	"github.com/haskel/capfox/internal/storage"
+	"github.com/haskel/capfox/internal/trace"
 )
 
 func testLogger() *slog.Logger {
@@ -81,15 +91,20 @@ func testServerOverloaded(t *testing.T) *Server {
 
 type mockMonitor struct {
 	name string
-	data any
+	data monitor.Sample
+	err  error
 }
 
 func (m *mockMonitor) Name() string {
 	return m.name
 }
 
-func (m *mockMonitor) Collect() (any, error) {
-	return m.data, nil
+func (m *mockMonitor) Section() string {
+	return m.name
+}
+
+func (m *mockMonitor) Collect() (monitor.Sample, error) {
+	return m.data, m.err
 }
 
 func TestHandleInfo(t *testing.T) {
@@ -208,6 +223,47 @@ func TestHandleAsk_Allowed(t *testing.T) {
 	}
 }
 
+func TestHandleAsk_RecordsTrace(t *testing.T) {
+	srv := testServer(t)
+
+	path := filepath.Join(t.TempDir(), "trace.ndjson")
+	recorder, err := trace.NewRecorder(path, testLogger())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	srv.SetRecorder(recorder)
+
+	req := httptest.NewRequest(http.MethodPost, "/ask", bytes.NewBufferString(`{"task": "render"}`))
+	srv.handleAsk(httptest.NewRecorder(), req)
+	req = httptest.NewRequest(http.MethodPost, "/task/notify", bytes.NewBufferString(`{"task": "render"}`))
+	srv.handleTaskStart(httptest.NewRecorder(), req)
+	_ = recorder.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read trace: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 entries, got %d: %s", len(lines), data)
+	}
+
+	var ask, notify trace.Entry
+	if err := json.Unmarshal([]byte(lines[0]), &ask); err != nil {
+		t.Fatalf("invalid entry: %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &notify); err != nil {
+		t.Fatalf("invalid entry: %v", err)
+	}
+
+	if ask.Type != trace.TypeAsk || !strings.Contains(string(ask.Request), `"render"`) || string(ask.Response) != `{"allowed":true}` {
+		t.Errorf("unexpected ask entry: %s %s %s", ask.Type, ask.Request, ask.Response)
+	}
+	if notify.Type != trace.TypeNotify {
+		t.Errorf("expected notify entry, got %s", notify.Type)
+	}
+}
+
 func TestHandleAsk_Denied(t *testing.T) {
 	srv := testServerOverloaded(t)
 
@@ -354,6 +410,79 @@ func TestHandleTaskStart_InvalidBody(t *testing.T) {
 	}
 }
 
+func TestHandleTaskUsage(t *testing.T) {
+	srv := testServer(t)
+
+	req := httptest.NewRequest(http.MethodPost, "/task/notify", bytes.NewBufferString(`{"task": "render", "complexity": 100, "attributed": true}`))
+	w := httptest.NewRecorder()
+	srv.handleTaskStart(w, req)
+
+	var started learning.TaskStartResponse
+	if err := json.NewDecoder(w.Body).Decode(&started); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if started.TaskID == "" || started.ObserveAfterMS != 1000 {
+		t.Fatalf("expected task id and observe_after_ms 1000, got %+v", started)
+	}
+
+	tests := []struct {
+		name   string
+		body   string
+		status int
+	}{
+		{"missing task id", `{"wall_seconds": 1}`, http.StatusBadRequest},
+		{"missing wall time", `{"task_id": "` + started.TaskID + `"}`, http.StatusBadRequest},
+		{"reported", `{"task_id": "` + started.TaskID + `", "wall_seconds": 1, "cpu_seconds": 0.5, "peak_rss_bytes": 1024}`, http.StatusOK},
+		{"already observed", `{"task_id": "` + started.TaskID + `", "wall_seconds": 1}`, http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/task/usage", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			srv.handleTaskUsage(w, req)
+
+			if w.Code != tt.status {
+				t.Errorf("expected status %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if stats := srv.learningEngine.GetTaskStats("render"); stats == nil || stats.Count != 1 {
+		t.Errorf("expected the report to be observed, got %+v", stats)
+	}
+}
+
+func TestHandleTaskStart_PendingForDecision(t *testing.T) {
+	srv := testServerWithDecision(t, 10.0)
+	dm := srv.v2.DecisionManager
+	srv.learningEngine.SetOnDone(dm.RemovePendingTask)
+
+	req := httptest.NewRequest(http.MethodPost, "/task/notify", bytes.NewBufferString(`{"task": "render", "complexity": 100, "attributed": true}`))
+	w := httptest.NewRecorder()
+	srv.handleTaskStart(w, req)
+
+	var started learning.TaskStartResponse
+	if err := json.NewDecoder(w.Body).Decode(&started); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if dm.PendingCount() != 1 {
+		t.Fatalf("expected 1 pending task, got %d", dm.PendingCount())
+	}
+
+	req = httptest.NewRequest(http.MethodPost, "/task/usage", bytes.NewBufferString(`{"task_id": "`+started.TaskID+`", "wall_seconds": 1}`))
+	w = httptest.NewRecorder()
+	srv.handleTaskUsage(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if dm.PendingCount() != 0 {
+		t.Errorf("expected no pending tasks after the report, got %d", dm.PendingCount())
+	}
+}
+
 func TestHandleStats(t *testing.T) {
 	srv := testServer(t)
 
@@ -481,3 +610,799 @@ func TestHandleReady_NotReady(t *testing.T) {
 		t.Error("expected non-empty message for not ready state")
 	}
 }
+
+// testServerWithFailingGPU returns a server whose gpu monitor always fails,
+// with thresholds.health.max_failures set to maxFailures.
+func testServerWithFailingGPU(t *testing.T, maxFailures int) *Server {
+	cfg := config.Default()
+	cfg.Thresholds.Health.MaxFailures = maxFailures
+
+	agg := monitor.NewAggregator([]monitor.Monitor{
+		&mockMonitor{
+			name: "cpu",
+			data: &monitor.CPUState{UsagePercent: 50.0, Cores: []float64{50.0}},
+		},
+		&mockMonitor{name: "gpu", err: errors.New("nvidia-smi: exit status 9")},
+	}, time.Second, testLogger())
+	_ = agg.Start(context.Background())
+
+	cm := capacity.NewManager(agg, cfg.Thresholds)
+	le := learning.NewEngine(learning.NewMovingAverageModel(0.2), agg, time.Second, testLogger())
+
+	srv := New(cfg, agg, cm, le, testLogger(), "0.1.0-test")
+
+	m := model.NewNoopModel()
+	dm := decision.NewManager(
+		strategy.NewThresholdStrategy(),
+		m,
+		agg,
+		decision.ManagerConfig{Thresholds: decision.ThresholdsFromConfig(cfg.Thresholds)},
+	)
+	srv.SetDecisionComponents(&V2Components{DecisionManager: dm, Model: m})
+
+	return srv
+}
+
+func TestHandleReady_StaleMetrics(t *testing.T) {
+	srv := testServerWithFailingGPU(t, 1)
+
+	req := httptest.NewRequest(http.MethodGet, "/ready", nil)
+	w := httptest.NewRecorder()
+
+	srv.handleReady(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", w.Code)
+	}
+
+	var resp ReadyResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if resp.Ready {
+		t.Error("expected ready=false")
+	}
+	if resp.Message != "stale metrics from monitors: gpu" {
+		t.Errorf("unexpected message: %s", resp.Message)
+	}
+	if resp.Monitors["gpu"].LastError != "nvidia-smi: exit status 9" {
+		t.Errorf("expected gpu last_error, got %+v", resp.Monitors["gpu"])
+	}
+
+	// With the check disabled a failing monitor does not affect readiness
+	srv = testServerWithFailingGPU(t, 0)
+	w = httptest.NewRecorder()
+	srv.handleReady(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 with health check disabled, got %d", w.Code)
+	}
+}
+
+func TestHandleHealthV2(t *testing.T) {
+	srv := testServerWithFailingGPU(t, 0)
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/health", nil)
+	w := httptest.NewRecorder()
+
+	srv.handleHealthV2(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200, got %d", w.Code)
+	}
+
+	var resp HealthResponseV2
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if resp.Status != "degraded" {
+		t.Errorf("expected status degraded, got %s", resp.Status)
+	}
+	if len(resp.Stale) != 1 || resp.Stale[0] != "gpu" {
+		t.Errorf("expected [gpu] stale, got %v", resp.Stale)
+	}
+	if resp.Monitors["cpu"].Stale || resp.Monitors["cpu"].LastSuccess.IsZero() {
+		t.Errorf("expected healthy cpu, got %+v", resp.Monitors["cpu"])
+	}
+	if gpu := resp.Monitors["gpu"]; !gpu.Stale || gpu.ConsecutiveFailures != 1 {
+		t.Errorf("expected stale gpu with 1 failure, got %+v", gpu)
+	}
+}
+
+func TestHandleAsk_StaleMetrics(t *testing.T) {
+	srv := testServerWithFailingGPU(t, 1)
+
+	w := httptest.NewRecorder()
+	srv.handleAsk(w, httptest.NewRequest(http.MethodPost, "/ask?reason=true", bytes.NewBufferString(`{"task": "test_task"}`)))
+
+	var resp capacity.AskResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Allowed || len(resp.Reasons) != 1 || resp.Reasons[0] != "metrics_stale" {
+		t.Errorf("expected denial with [metrics_stale], got %+v", resp)
+	}
+
+	w = httptest.NewRecorder()
+	srv.handleAskV2(w, httptest.NewRequest(http.MethodPost, "/v2/ask", bytes.NewBufferString(`{"task": "test_task"}`)))
+
+	var respV2 AskResponseV2
+	if err := json.NewDecoder(w.Body).Decode(&respV2); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if respV2.Allowed || len(respV2.Reasons) != 1 || respV2.Reasons[0] != "metrics_stale" {
+		t.Errorf("expected v2 denial with [metrics_stale], got %+v", respV2)
+	}
+}
+
+func TestHandleAsk_CustomMetrics(t *testing.T) {
+	maxBacklog := 100.0
+	cfg := config.Default()
+	cfg.Thresholds.Custom = map[string]config.CustomThreshold{"render_backlog": {Max: &maxBacklog}}
+
+	agg := monitor.NewAggregator([]monitor.Monitor{
+		&mockMonitor{
+			name: "cpu",
+			data: &monitor.CPUState{UsagePercent: 50.0, Cores: []float64{50.0}},
+		},
+		&mockMonitor{name: "plugin:render", data: monitor.CustomState{"render_backlog": 250}},
+	}, time.Second, testLogger())
+	_ = agg.Start(context.Background())
+
+	cm := capacity.NewManager(agg, cfg.Thresholds)
+	le := learning.NewEngine(learning.NewMovingAverageModel(0.2), agg, time.Second, testLogger())
+	srv := New(cfg, agg, cm, le, testLogger(), "0.1.0-test")
+
+	m := model.NewNoopModel()
+	dm := decision.NewManager(
+		strategy.NewThresholdStrategy(),
+		m,
+		agg,
+		decision.ManagerConfig{Thresholds: decision.ThresholdsFromConfig(cfg.Thresholds)},
+	)
+	srv.SetDecisionComponents(&V2Components{DecisionManager: dm, Model: m})
+
+	w := httptest.NewRecorder()
+	srv.handleAsk(w, httptest.NewRequest(http.MethodPost, "/ask?reason=true", bytes.NewBufferString(`{"task": "render"}`)))
+
+	var resp capacity.AskResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Allowed || len(resp.Reasons) != 1 || resp.Reasons[0] != "custom:render_backlog" {
+		t.Errorf("expected denial with [custom:render_backlog], got %+v", resp)
+	}
+
+	w = httptest.NewRecorder()
+	srv.handleAskV2(w, httptest.NewRequest(http.MethodPost, "/v2/ask", bytes.NewBufferString(`{"task": "render"}`)))
+
+	var respV2 AskResponseV2
+	if err := json.NewDecoder(w.Body).Decode(&respV2); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if respV2.Allowed || len(respV2.Reasons) != 1 || respV2.Reasons[0] != "custom:render_backlog" {
+		t.Errorf("expected v2 denial with [custom:render_backlog], got %+v", respV2)
+	}
+
+	w = httptest.NewRecorder()
+	srv.handleStatus(w, httptest.NewRequest(http.MethodGet, "/status", nil))
+
+	var state monitor.SystemState
+	if err := json.NewDecoder(w.Body).Decode(&state); err != nil {
+		t.Fatalf("failed to decode status: %v", err)
+	}
+	if state.Custom["render_backlog"] != 250 {
+		t.Errorf("expected custom metrics in /status, got %v", state.Custom)
+	}
+}
+
+func TestHandleMetricsPush(t *testing.T) {
+	maxInflight := 32.0
+	srv := testServer(t)
+	thresholds := srv.config.Thresholds
+	thresholds.Custom = map[string]config.CustomThreshold{"inference_inflight": {Max: &maxInflight}}
+	srv.capacityManager.UpdateThresholds(thresholds)
+
+	body := `{"metrics": [{"name": "inference_inflight", "value": 37, "ttl_sec": 30}, {"name": "batch_jobs", "value": 0}]}`
+	w := httptest.NewRecorder()
+	srv.handleMetricsPush(w, httptest.NewRequest(http.MethodPost, "/v2/metrics", bytes.NewBufferString(body)))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp MetricPushResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Accepted != 2 {
+		t.Errorf("expected 2 accepted metrics, got %d", resp.Accepted)
+	}
+	if ttl := time.Until(resp.Metrics[1].ExpiresAt); ttl < 50*time.Second || ttl > time.Minute {
+		t.Errorf("expected the default ttl of 60s, got %s", ttl)
+	}
+
+	state := srv.aggregator.GetState()
+	if state.Custom["inference_inflight"] != 37 || state.Custom["batch_jobs"] != 0 {
+		t.Errorf("expected pushed metrics in /status, got %v", state.Custom)
+	}
+
+	w = httptest.NewRecorder()
+	srv.handleAsk(w, httptest.NewRequest(http.MethodPost, "/ask?reason=true", bytes.NewBufferString(`{"task": "infer"}`)))
+
+	var askResp capacity.AskResponse
+	if err := json.NewDecoder(w.Body).Decode(&askResp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if askResp.Allowed || len(askResp.Reasons) != 1 || askResp.Reasons[0] != "custom:inference_inflight" {
+		t.Errorf("expected denial with [custom:inference_inflight], got %+v", askResp)
+	}
+}
+
+func TestHandleMetricsPush_Invalid(t *testing.T) {
+	srv := testServer(t)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"empty", `{"metrics": []}`},
+		{"missing value", `{"metrics": [{"name": "inflight"}]}`},
+		{"invalid name", `{"metrics": [{"name": "in flight", "value": 1}]}`},
+		{"negative ttl", `{"metrics": [{"name": "inflight", "value": 1, "ttl_sec": -1}]}`},
+		{"malformed", `{"metrics": `},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		srv.handleMetricsPush(w, httptest.NewRequest(http.MethodPost, "/v2/metrics", bytes.NewBufferString(tt.body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", tt.name, w.Code)
+		}
+	}
+
+	if custom := srv.aggregator.GetState().Custom; len(custom) != 0 {
+		t.Errorf("expected no metrics stored after invalid pushes, got %v", custom)
+	}
+}
+
+// testServerWithDecision returns a server whose capacity manager sees 50% CPU
+// while the decision engine sees cpuPercent.
+func testServerWithDecision(t *testing.T, cpuPercent float64) *Server {
+	srv := testServer(t)
+
+	agg := monitor.NewAggregator([]monitor.Monitor{
+		&mockMonitor{
+			name: "cpu",
+			data: &monitor.CPUState{UsagePercent: cpuPercent, Cores: []float64{cpuPercent}},
+		},
+	}, time.Second, testLogger())
+	_ = agg.Start(context.Background())
+
+	m := model.NewNoopModel()
+	dm := decision.NewManager(
+		strategy.NewThresholdStrategy(),
+		m,
+		agg,
+		decision.ManagerConfig{Thresholds: decision.ThresholdsFromConfig(srv.config.Thresholds)},
+	)
+	srv.SetDecisionComponents(&V2Components{DecisionManager: dm, Model: m})
+
+	return srv
+}
+
+func TestHandleAskV2_NotEnabled(t *testing.T) {
+	srv := testServer(t)
+
+	body := `{"task": "test_task"}`
+	req := httptest.NewRequest(http.MethodPost, "/v2/ask", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	srv.handleAskV2(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", w.Code)
+	}
+}
+
+func TestHandleAskV2_Denied(t *testing.T) {
+	srv := testServerWithDecision(t, 95.0)
+
+	body := `{"task": "test_task"}`
+	req := httptest.NewRequest(http.MethodPost, "/v2/ask", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	srv.handleAskV2(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", w.Code)
+	}
+
+	var resp AskResponseV2
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if resp.Allowed {
+		t.Error("expected allowed=false")
+	}
+	if resp.Strategy != "threshold" {
+		t.Errorf("expected strategy 'threshold', got %s", resp.Strategy)
+	}
+	if len(resp.Reasons) != 1 || resp.Reasons[0] != "cpu_overload" {
+		t.Errorf("expected [cpu_overload], got %v", resp.Reasons)
+	}
+	if resp.Aggregation["cpu"] != "instant" {
+		t.Errorf("expected instant cpu aggregation, got %v", resp.Aggregation)
+	}
+}
+
+func TestHandleAskV2_GPUPlacement(t *testing.T) {
+	srv := testServer(t)
+
+	agg := monitor.NewAggregator([]monitor.Monitor{
+		&mockMonitor{
+			name: "cpu",
+			data: &monitor.CPUState{UsagePercent: 20, Cores: []float64{20}},
+		},
+		&mockMonitor{
+			name: "gpu",
+			data: monitor.GPUStates{
+				{Index: 0, UsagePercent: 95},
+				{Index: 1, UsagePercent: 40},
+				{Index: 2, UsagePercent: 10},
+			},
+		},
+	}, time.Second, testLogger())
+	_ = agg.Start(context.Background())
+
+	m := model.NewNoopModel()
+	dm := decision.NewManager(
+		strategy.NewThresholdStrategy(),
+		m,
+		agg,
+		decision.ManagerConfig{Thresholds: decision.ThresholdsFromConfig(srv.config.Thresholds)},
+	)
+	srv.SetDecisionComponents(&V2Components{DecisionManager: dm, Model: m})
+
+	req := httptest.NewRequest(http.MethodPost, "/v2/ask", bytes.NewBufferString(`{"task": "train"}`))
+	w := httptest.NewRecorder()
+
+	srv.handleAskV2(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200 with GPUs 1 and 2 fitting, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp AskResponseV2
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(resp.GPUs) != 2 || resp.GPUs[0] != 2 || resp.GPUs[1] != 1 {
+		t.Errorf("expected GPUs [2 1], got %v", resp.GPUs)
+	}
+	if resp.VisibleDevices != "2,1" {
+		t.Errorf("expected visible devices 2,1, got %q", resp.VisibleDevices)
+	}
+}
+
+// processMonitor contributes the run queue thresholds like the built-in
+// process monitor.
+type processMonitor struct {
+	mockMonitor
+}
+
+func (m *processMonitor) Thresholds() []monitor.Threshold {
+	return monitor.ProcessThresholds()
+}
+
+func TestHandleAskV2_RunQueue(t *testing.T) {
+	srv := testServer(t)
+
+	agg := monitor.NewAggregator([]monitor.Monitor{
+		&mockMonitor{
+			name: "cpu",
+			data: &monitor.CPUState{UsagePercent: 20, Cores: []float64{20}},
+		},
+		&processMonitor{mockMonitor{
+			name: "process",
+			data: &monitor.ProcessState{ProcsRunning: 40, ProcsBlocked: 1},
+		}},
+	}, time.Second, testLogger())
+	_ = agg.Start(context.Background())
+
+	thresholds := srv.config.Thresholds
+	thresholds.Process.MaxRunning = 32
+
+	m := model.NewNoopModel()
+	dm := decision.NewManager(
+		strategy.NewThresholdStrategy(),
+		m,
+		agg,
+		decision.ManagerConfig{Thresholds: decision.ThresholdsFromConfig(thresholds)},
+	)
+	srv.SetDecisionComponents(&V2Components{DecisionManager: dm, Model: m})
+
+	req := httptest.NewRequest(http.MethodPost, "/v2/ask", bytes.NewBufferString(`{"task": "build"}`))
+	w := httptest.NewRecorder()
+
+	srv.handleAskV2(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp AskResponseV2
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(resp.Reasons) != 1 || resp.Reasons[0] != "run_queue_saturated" {
+		t.Errorf("expected [run_queue_saturated], got %v", resp.Reasons)
+	}
+}
+
+func TestHandleAskV2_TaskThresholds(t *testing.T) {
+	srv := testServerWithDecision(t, 90.0)
+
+	cfg := config.Default()
+	cfg.Tasks = map[string]config.TaskConfig{}
+	if err := yaml.Unmarshal([]byte(`
+"batch_*":
+  thresholds:
+    cpu:
+      max_percent: 95
+`), &cfg.Tasks); err != nil {
+		t.Fatalf("failed to parse tasks: %v", err)
+	}
+	srv.ReloadConfig(cfg)
+
+	ask := func(task string) (int, AskResponseV2) {
+		req := httptest.NewRequest(http.MethodPost, "/v2/ask", bytes.NewBufferString(`{"task": "`+task+`"}`))
+		w := httptest.NewRecorder()
+		srv.handleAskV2(w, req)
+
+		var resp AskResponseV2
+		if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return w.Code, resp
+	}
+
+	code, resp := ask("batch_nightly")
+	if code != http.StatusOK {
+		t.Errorf("expected status 200 under the batch_* cpu limit, got %d", code)
+	}
+	if resp.TaskPattern != "batch_*" {
+		t.Errorf("expected task pattern batch_*, got %q", resp.TaskPattern)
+	}
+	if resp.Thresholds == nil || resp.Thresholds.CPU.MaxPercent != 95 {
+		t.Errorf("expected effective cpu.max_percent 95, got %+v", resp.Thresholds)
+	}
+
+	code, resp = ask("encode")
+	if code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 under the global cpu limit, got %d", code)
+	}
+	if resp.TaskPattern != "" || resp.Thresholds == nil || resp.Thresholds.CPU.MaxPercent != 80 {
+		t.Errorf("expected global thresholds, got pattern %q and %+v", resp.TaskPattern, resp.Thresholds)
+	}
+}
+
+func TestHandleAskV2_Priority(t *testing.T) {
+	srv := testServerWithDecision(t, 70.0)
+
+	cfg := config.Default()
+	if err := yaml.Unmarshal([]byte(`
+priorities:
+  low:
+    thresholds:
+      cpu:
+        max_percent: 60
+`), cfg); err != nil {
+		t.Fatalf("failed to parse priorities: %v", err)
+	}
+	srv.ReloadConfig(cfg)
+
+	tests := []struct {
+		body       string
+		wantStatus int
+		wantReason string
+	}{
+		{`{"task": "report", "priority": "low"}`, http.StatusServiceUnavailable, "priority:cpu_overload"},
+		{`{"task": "report", "priority": -1}`, http.StatusServiceUnavailable, "priority:cpu_overload"},
+		{`{"task": "report", "priority": "high"}`, http.StatusOK, ""},
+		{`{"task": "report", "priority": "urgent"}`, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v2/ask", bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+
+			srv.handleAskV2(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantReason == "" {
+				return
+			}
+
+			var resp AskResponseV2
+			if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if len(resp.Reasons) != 1 || resp.Reasons[0] != tt.wantReason {
+				t.Errorf("expected reasons [%s], got %v", tt.wantReason, resp.Reasons)
+			}
+			if resp.Priority != config.PriorityLow {
+				t.Errorf("expected priority low, got %q", resp.Priority)
+			}
+		})
+	}
+}
+
+func TestHandleReserve_Lifecycle(t *testing.T) {
+	srv := testServerWithDecision(t, 50.0)
+	dir := t.TempDir()
+	srv.v2.LeaseStorage = storage.NewLeaseStorage(storage.New(dir, time.Hour, testLogger()))
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	w := do(http.MethodPost, "/v2/reserve", `{"task": "encode", "resources": {"cpu": 10}, "ttl_sec": 120}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp ReserveResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !resp.Allowed || resp.Lease == nil || resp.Lease.ID == "" || resp.Lease.TTLSec != 120 {
+		t.Fatalf("expected an allowed lease of 120s, got %+v", resp)
+	}
+	id := resp.Lease.ID
+
+	// Leases are persisted and shown in /status
+	if _, err := os.Stat(filepath.Join(dir, "capfox_leases.json")); err != nil {
+		t.Errorf("expected leases saved: %v", err)
+	}
+	var status StatusResponse
+	if err := json.NewDecoder(do(http.MethodGet, "/status", "").Body).Decode(&status); err != nil {
+		t.Fatalf("failed to decode status: %v", err)
+	}
+	if len(status.Leases) != 1 || status.Leases[0].ID != id || status.SystemState == nil {
+		t.Errorf("expected the lease in /status, got %+v", status.Leases)
+	}
+
+	w = do(http.MethodPost, "/v2/reserve/"+id+"/heartbeat", "")
+	if w.Code != http.StatusOK {
+		t.Errorf("expected heartbeat status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w = do(http.MethodDelete, "/v2/reserve/"+id, ""); w.Code != http.StatusNoContent {
+		t.Errorf("expected release status 204, got %d", w.Code)
+	}
+	if w = do(http.MethodDelete, "/v2/reserve/"+id, ""); w.Code != http.StatusNotFound {
+		t.Errorf("expected status 404 for a released lease, got %d", w.Code)
+	}
+	if w = do(http.MethodPost, "/v2/reserve/"+id+"/heartbeat", `{"ttl_sec": 60}`); w.Code != http.StatusNotFound {
+		t.Errorf("expected heartbeat status 404 for a released lease, got %d", w.Code)
+	}
+}
+
+func TestHandleReserve_InvalidTTL(t *testing.T) {
+	srv := testServerWithDecision(t, 10.0)
+
+	tests := []struct {
+		path string
+		body string
+	}{
+		{"/v2/reserve", `{"task": "encode", "ttl_sec": -1}`},
+		{"/v2/reserve", `{"task": "encode", "ttl_sec": 9223372036}`},
+		{"/v2/reserve/any/heartbeat", `{"ttl_sec": 9223372036}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path+" "+tt.body, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, bytes.NewBufferString(tt.body))
+			w := httptest.NewRecorder()
+			srv.httpServer.Handler.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("expected status 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+	if len(srv.v2.DecisionManager.Leases().Active(time.Now())) != 0 {
+		t.Error("expected no lease for an invalid ttl_sec")
+	}
+}
+
+func TestHandleReserve_Denied(t *testing.T) {
+	srv := testServerWithDecision(t, 95.0)
+
+	req := httptest.NewRequest(http.MethodPost, "/v2/reserve", bytes.NewBufferString(`{"task": "encode"}`))
+	w := httptest.NewRecorder()
+	srv.handleReserve(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status 503, got %d", w.Code)
+	}
+	var resp ReserveResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Lease != nil || len(srv.v2.DecisionManager.Leases().Active(time.Now())) != 0 {
+		t.Errorf("expected no lease on denial, got %+v", resp.Lease)
+	}
+}
+
+func TestHandleReserve_HoldsCapacity(t *testing.T) {
+	srv := testServer(t) // CPU at 50%, limit 80%
+
+	// The default strategy, falling back to thresholds without predictions
+	m := model.NewNoopModel()
+	dm := decision.NewManager(
+		strategy.NewPredictiveStrategy(m, 10, nil),
+		m,
+		srv.aggregator,
+		decision.ManagerConfig{Thresholds: decision.ThresholdsFromConfig(srv.config.Thresholds)},
+	)
+	srv.SetDecisionComponents(&V2Components{DecisionManager: dm, Model: m})
+	srv.capacityManager.SetStateSource(dm.ReservedState)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		srv.httpServer.Handler.ServeHTTP(w, req)
+		return w
+	}
+
+	body := `{"task": "encode", "resources": {"cpu": 20}}`
+	for i, want := range []int{http.StatusOK, http.StatusOK, http.StatusServiceUnavailable} {
+		if w := do(http.MethodPost, "/v2/reserve", body); w.Code != want {
+			t.Fatalf("reserve %d: expected status %d, got %d: %s", i+1, want, w.Code, w.Body.String())
+		}
+	}
+
+	// Two leases hold 40% CPU for /ask as well
+	w := do(http.MethodPost, "/ask?reason=true", `{"task": "report"}`)
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected /ask denied by the leases, got %d", w.Code)
+	}
+	var resp capacity.AskResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Reasons) != 1 || resp.Reasons[0] != string(capacity.ReasonCPUOverload) {
+		t.Errorf("expected [cpu_overload], got %v", resp.Reasons)
+	}
+}
+
+func TestHandleAsk_RouteV1ToDecisionEngine(t *testing.T) {
+	srv := testServerWithDecision(t, 95.0)
+
+	// Static thresholds see 50% CPU and would allow the task
+	body := `{"task": "test_task"}`
+	req := httptest.NewRequest(http.MethodPost, "/ask?reason=true", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+
+	srv.handleAsk(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status 200 without routing, got %d", w.Code)
+	}
+
+	// With routing enabled the decision engine sees 95% CPU
+	srv.config.Decision.RouteV1Ask = true
+
+	req = httptest.NewRequest(http.MethodPost, "/ask?reason=true", bytes.NewBufferString(body))
+	w = httptest.NewRecorder()
+
+	srv.handleAsk(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503 with routing, got %d", w.Code)
+	}
+
+	var resp capacity.AskResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	if len(resp.Reasons) != 1 || resp.Reasons[0] != "cpu_overload" {
+		t.Errorf("expected [cpu_overload], got %v", resp.Reasons)
+	}
+}
+
+func TestHandleAsk_ConcurrentReload(t *testing.T) {
+	srv := testServerWithDecision(t, 95.0)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 20; i++ {
+			cfg := config.Default()
+			cfg.Decision.RouteV1Ask = i%2 == 0
+			srv.ReloadConfig(cfg)
+		}
+	}()
+
+	for i := 0; i < 20; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/ask", bytes.NewBufferString(`{"task": "test_task"}`))
+		w := httptest.NewRecorder()
+		srv.handleAsk(w, req)
+
+		if w.Code != http.StatusOK && w.Code != http.StatusServiceUnavailable {
+			t.Errorf("unexpected status %d", w.Code)
+		}
+	}
+	<-done
+}
+
+func TestHandleHistory(t *testing.T) {
+	srv := testServer(t)
+
+	history := monitor.NewHistory(10)
+	for i, cpu := range []float64{20, 40, 60} {
+		history.Record(&monitor.SystemState{
+			CPU:       monitor.CPUState{UsagePercent: cpu},
+			Timestamp: time.Unix(1000+int64(i), 0),
+		})
+	}
+	srv.aggregator = monitor.NewAggregatorWithHistory(nil, time.Second, testLogger(), history)
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/history?from=1001&step=10s&metrics=cpu", nil)
+	w := httptest.NewRecorder()
+
+	srv.handleHistory(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp monitor.HistoryResult
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Timestamps) != 1 || resp.Series["cpu"][0] != 50 {
+		t.Errorf("expected one bucket averaging 40 and 60, got %+v", resp)
+	}
+}
+
+func TestHandleHistory_BadRequest(t *testing.T) {
+	srv := testServer(t)
+	srv.aggregator = monitor.NewAggregatorWithHistory(nil, time.Second, testLogger(), monitor.NewHistory(10))
+
+	for _, query := range []string{"metrics=bogus", "from=yesterday", "step=-5s"} {
+		req := httptest.NewRequest(http.MethodGet, "/v2/history?"+query, nil)
+		w := httptest.NewRecorder()
+
+		srv.handleHistory(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected status 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestHandleHistory_Disabled(t *testing.T) {
+	srv := testServer(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/history", nil)
+	w := httptest.NewRecorder()
+
+	srv.handleHistory(w, req)
+
+	if w.Code != http.StatusServiceUnavailable {
+		t.Errorf("expected status 503, got %d", w.Code)
+	}
+}
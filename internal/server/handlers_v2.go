@@ -2,17 +2,29 @@ package server
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/haskel/capfox/internal/config"
 	"github.com/haskel/capfox/internal/decision"
+	"github.com/haskel/capfox/internal/monitor"
+	"github.com/haskel/capfox/internal/trace"
 )
 
+// metricNameRegex matches names of pushed metrics, e.g. inference_inflight.
+var metricNameRegex = regexp.MustCompile(`^[A-Za-z0-9_.:-]{1,128}$`)
+
 // AskRequestV2 is the request body for POST /v2/ask.
 type AskRequestV2 struct {
 	Task       string                    `json:"task"`
 	Complexity int                       `json:"complexity,omitempty"`
 	Resources  *decision.ResourceEstimate `json:"resources,omitempty"`
+	// Priority is low, normal, high or a number, empty is normal
+	Priority config.Priority `json:"priority,omitempty"`
 }
 
 // AskResponseV2 is the response for POST /v2/ask.
@@ -23,6 +35,20 @@ type AskResponseV2 struct {
 	Confidence     float64               `json:"confidence"`
 	Strategy       string                `json:"strategy"`
 	Model          string                `json:"model"`
+	// NUMANode is the node with the most headroom, e.g. for numactl --cpunodebind
+	NUMANode *int `json:"numa_node,omitempty"`
+	// GPUs are the indices of GPUs that fit the predicted impact, most headroom first
+	GPUs []int `json:"gpus,omitempty"`
+	// VisibleDevices is GPUs as a CUDA_VISIBLE_DEVICES or HIP_VISIBLE_DEVICES value
+	VisibleDevices string `json:"visible_devices,omitempty"`
+	// TaskPattern is the tasks pattern whose thresholds applied, empty for the global ones
+	TaskPattern string `json:"task_pattern,omitempty"`
+	// Priority is the class the thresholds were resolved for
+	Priority config.Priority `json:"priority,omitempty"`
+	// Thresholds are the effective limits the task was checked against
+	Thresholds *decision.ThresholdsConfig `json:"thresholds,omitempty"`
+	// Aggregation is the smoothing applied to each resource, e.g. "cpu": "max(30s)"
+	Aggregation map[string]string `json:"aggregation,omitempty"`
 }
 
 // handleAskV2 handles POST /v2/ask using the new decision engine.
@@ -39,8 +65,20 @@ func (s *Server) handleAskV2(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Make decision using new engine
-	result := s.v2.DecisionManager.Decide(req.Task, req.Complexity, req.Resources)
+	result := s.v2.DecisionManager.Decide(req.Task, req.Complexity, req.Priority, req.Resources)
+
+	resp := newAskResponseV2(result)
+	s.recorder.Record(trace.TypeDecision, req, resp)
 
+	if resp.Allowed {
+		s.writeJSON(w, http.StatusOK, resp)
+	} else {
+		s.writeJSON(w, http.StatusServiceUnavailable, resp)
+	}
+}
+
+// newAskResponseV2 converts a decision to the /v2/ask response.
+func newAskResponseV2(result *decision.Result) AskResponseV2 {
 	// Convert reasons to strings
 	var reasons []string
 	if !result.Allowed {
@@ -50,19 +88,20 @@ func (s *Server) handleAskV2(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	resp := AskResponseV2{
+	return AskResponseV2{
 		Allowed:        result.Allowed,
 		Reasons:        reasons,
 		PredictedState: result.PredictedState,
 		Confidence:     result.Confidence,
 		Strategy:       result.Strategy,
 		Model:          result.Model,
-	}
-
-	if resp.Allowed {
-		s.writeJSON(w, http.StatusOK, resp)
-	} else {
-		s.writeJSON(w, http.StatusServiceUnavailable, resp)
+		NUMANode:       result.NUMANode,
+		GPUs:           result.GPUs,
+		VisibleDevices: monitor.VisibleDevices(result.GPUs),
+		TaskPattern:    result.TaskPattern,
+		Priority:       result.Priority,
+		Thresholds:     result.Thresholds,
+		Aggregation:    result.Aggregation,
 	}
 }
 
@@ -82,6 +121,18 @@ type TaskStatsV2 struct {
 	AvgMemDelta  float64      `json:"avg_mem_delta"`
 	AvgGPUDelta  float64      `json:"avg_gpu_delta,omitempty"`
 	AvgVRAMDelta float64      `json:"avg_vram_delta,omitempty"`
+
+	AvgSwapDelta      float64 `json:"avg_swap_delta,omitempty"`
+	AvgSwapPagesDelta float64 `json:"avg_swap_pages_delta,omitempty"`
+
+	AvgIOReadDelta  float64 `json:"avg_io_read_delta,omitempty"`
+	AvgIOWriteDelta float64 `json:"avg_io_write_delta,omitempty"`
+	AvgIOUtilDelta  float64 `json:"avg_io_util_delta,omitempty"`
+
+	AvgNetRxDelta   float64 `json:"avg_net_rx_delta,omitempty"`
+	AvgNetTxDelta   float64 `json:"avg_net_tx_delta,omitempty"`
+	AvgNetUtilDelta float64 `json:"avg_net_util_delta,omitempty"`
+
 	Coefficients *Coefficients `json:"coefficients,omitempty"`
 }
 
@@ -95,6 +146,25 @@ type Coefficients struct {
 	GPUB  float64 `json:"gpu_b,omitempty"`
 	VRAMA float64 `json:"vram_a,omitempty"`
 	VRAMB float64 `json:"vram_b,omitempty"`
+
+	SwapA      float64 `json:"swap_a,omitempty"`
+	SwapB      float64 `json:"swap_b,omitempty"`
+	SwapPagesA float64 `json:"swap_pages_a,omitempty"`
+	SwapPagesB float64 `json:"swap_pages_b,omitempty"`
+
+	IOReadA  float64 `json:"io_read_a,omitempty"`
+	IOReadB  float64 `json:"io_read_b,omitempty"`
+	IOWriteA float64 `json:"io_write_a,omitempty"`
+	IOWriteB float64 `json:"io_write_b,omitempty"`
+	IOUtilA  float64 `json:"io_util_a,omitempty"`
+	IOUtilB  float64 `json:"io_util_b,omitempty"`
+
+	NetRxA   float64 `json:"net_rx_a,omitempty"`
+	NetRxB   float64 `json:"net_rx_b,omitempty"`
+	NetTxA   float64 `json:"net_tx_a,omitempty"`
+	NetTxB   float64 `json:"net_tx_b,omitempty"`
+	NetUtilA float64 `json:"net_util_a,omitempty"`
+	NetUtilB float64 `json:"net_util_b,omitempty"`
 }
 
 // handleModelStats handles GET /v2/model/stats.
@@ -116,6 +186,17 @@ func (s *Server) handleModelStats(w http.ResponseWriter, r *http.Request) {
 			AvgMemDelta:  ts.AvgMemDelta,
 			AvgGPUDelta:  ts.AvgGPUDelta,
 			AvgVRAMDelta: ts.AvgVRAMDelta,
+
+			AvgSwapDelta:      ts.AvgSwapDelta,
+			AvgSwapPagesDelta: ts.AvgSwapPagesDelta,
+
+			AvgIOReadDelta:  ts.AvgIOReadDelta,
+			AvgIOWriteDelta: ts.AvgIOWriteDelta,
+			AvgIOUtilDelta:  ts.AvgIOUtilDelta,
+
+			AvgNetRxDelta:   ts.AvgNetRxDelta,
+			AvgNetTxDelta:   ts.AvgNetTxDelta,
+			AvgNetUtilDelta: ts.AvgNetUtilDelta,
 		}
 
 		if ts.Coefficients != nil {
@@ -128,6 +209,25 @@ func (s *Server) handleModelStats(w http.ResponseWriter, r *http.Request) {
 				GPUB:  ts.Coefficients.GPUB,
 				VRAMA: ts.Coefficients.VRAMA,
 				VRAMB: ts.Coefficients.VRAMB,
+
+				SwapA:      ts.Coefficients.SwapA,
+				SwapB:      ts.Coefficients.SwapB,
+				SwapPagesA: ts.Coefficients.SwapPagesA,
+				SwapPagesB: ts.Coefficients.SwapPagesB,
+
+				IOReadA:  ts.Coefficients.IOReadA,
+				IOReadB:  ts.Coefficients.IOReadB,
+				IOWriteA: ts.Coefficients.IOWriteA,
+				IOWriteB: ts.Coefficients.IOWriteB,
+				IOUtilA:  ts.Coefficients.IOUtilA,
+				IOUtilB:  ts.Coefficients.IOUtilB,
+
+				NetRxA:   ts.Coefficients.NetRxA,
+				NetRxB:   ts.Coefficients.NetRxB,
+				NetTxA:   ts.Coefficients.NetTxA,
+				NetTxB:   ts.Coefficients.NetTxB,
+				NetUtilA: ts.Coefficients.NetUtilA,
+				NetUtilB: ts.Coefficients.NetUtilB,
 			}
 		}
 
@@ -191,3 +291,162 @@ func (s *Server) handleSchedulerRetrain(w http.ResponseWriter, r *http.Request)
 
 	s.writeJSON(w, http.StatusOK, map[string]bool{"success": true})
 }
+
+// HealthResponseV2 is the response for GET /v2/health.
+type HealthResponseV2 struct {
+	// Status is "ok", or "degraded" while any monitor is stale
+	Status   string              `json:"status"`
+	Stale    []string            `json:"stale,omitempty"`
+	Monitors monitor.HealthState `json:"monitors"`
+}
+
+// handleHealthV2 handles GET /v2/health, reporting the collection health
+// of every monitor.
+func (s *Server) handleHealthV2(w http.ResponseWriter, r *http.Request) {
+	health := s.aggregator.Health()
+	resp := HealthResponseV2{
+		Status:   "ok",
+		Stale:    health.Failing(1),
+		Monitors: health,
+	}
+	if len(resp.Stale) > 0 {
+		resp.Status = "degraded"
+	}
+
+	s.writeJSON(w, http.StatusOK, resp)
+}
+
+// MetricPushRequest is the request body for POST /v2/metrics.
+type MetricPushRequest struct {
+	Metrics []PushedMetricRequest `json:"metrics"`
+}
+
+// PushedMetricRequest is one pushed custom metric.
+type PushedMetricRequest struct {
+	Name  string   `json:"name"`
+	Value *float64 `json:"value"`
+	// TTLSec is how long the value stays valid, 0 uses monitoring.push.default_ttl_sec
+	TTLSec int `json:"ttl_sec,omitempty"`
+}
+
+// MetricPushResponse is the response for POST /v2/metrics.
+type MetricPushResponse struct {
+	Accepted int                    `json:"accepted"`
+	Metrics  []monitor.PushedMetric `json:"metrics"`
+}
+
+// handleMetricsPush handles POST /v2/metrics. Pushed values are merged into
+// the custom metrics until their TTL expires.
+func (s *Server) handleMetricsPush(w http.ResponseWriter, r *http.Request) {
+	var req MetricPushRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	if len(req.Metrics) == 0 {
+		http.Error(w, "metrics cannot be empty", http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	defaultTTL := s.currentConfig().Monitoring.Push.DefaultTTL()
+	metrics := make([]monitor.PushedMetric, 0, len(req.Metrics))
+	for _, m := range req.Metrics {
+		if !metricNameRegex.MatchString(m.Name) {
+			http.Error(w, fmt.Sprintf("invalid metric name: %q", m.Name), http.StatusBadRequest)
+			return
+		}
+		if m.Value == nil {
+			http.Error(w, fmt.Sprintf("metric %s: value is required", m.Name), http.StatusBadRequest)
+			return
+		}
+		if m.TTLSec < 0 {
+			http.Error(w, fmt.Sprintf("metric %s: ttl_sec must be non-negative", m.Name), http.StatusBadRequest)
+			return
+		}
+
+		ttl := defaultTTL
+		if m.TTLSec > 0 {
+			ttl = time.Duration(m.TTLSec) * time.Second
+		}
+		metrics = append(metrics, monitor.PushedMetric{
+			Name:      m.Name,
+			Value:     *m.Value,
+			ExpiresAt: now.Add(ttl),
+		})
+	}
+
+	s.aggregator.PushMetrics(metrics)
+
+	s.writeJSON(w, http.StatusOK, MetricPushResponse{Accepted: len(metrics), Metrics: metrics})
+}
+
+// handleHistory handles GET /v2/history?from=&to=&step=&metrics=.
+// from and to are RFC 3339 or unix seconds, step is a duration ("30s") or
+// seconds, metrics is a comma-separated list.
+func (s *Server) handleHistory(w http.ResponseWriter, r *http.Request) {
+	history := s.aggregator.History()
+	if history == nil {
+		http.Error(w, "history not enabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	query := r.URL.Query()
+	var q monitor.HistoryQuery
+	var err error
+
+	if q.From, err = parseHistoryTime(query.Get("from")); err != nil {
+		http.Error(w, "invalid from: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if q.To, err = parseHistoryTime(query.Get("to")); err != nil {
+		http.Error(w, "invalid to: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if q.Step, err = parseHistoryStep(query.Get("step")); err != nil {
+		http.Error(w, "invalid step: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if metrics := query.Get("metrics"); metrics != "" {
+		q.Metrics = strings.Split(metrics, ",")
+	}
+
+	result, err := history.Query(q)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.writeJSON(w, http.StatusOK, result)
+}
+
+// parseHistoryTime parses an RFC 3339 time or unix seconds. Empty means unbounded.
+func parseHistoryTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if secs, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.UnixMilli(int64(secs * 1000)), nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// parseHistoryStep parses a duration such as "30s" or a number of seconds.
+func parseHistoryStep(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, nil
+	}
+	step, err := time.ParseDuration(value)
+	if err != nil {
+		secs, numErr := strconv.ParseFloat(value, 64)
+		if numErr != nil {
+			return 0, err
+		}
+		step = time.Duration(secs * float64(time.Second))
+	}
+	if step < 0 {
+		return 0, fmt.Errorf("must be non-negative")
+	}
+	return step, nil
+}
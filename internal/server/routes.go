@@ -17,6 +17,7 @@ func (s *Server) setupRoutes() *http.ServeMux {
 	mux.HandleFunc("GET /status", s.handleStatus)
 	mux.HandleFunc("POST /ask", s.handleAsk)
 	mux.HandleFunc("POST /task/notify", s.handleTaskStart)
+	mux.HandleFunc("POST /task/usage", s.handleTaskUsage)
 	mux.HandleFunc("GET /stats", s.handleStats)
 
 	// V2 routes (new decision engine)
@@ -24,6 +25,12 @@ func (s *Server) setupRoutes() *http.ServeMux {
 	mux.HandleFunc("GET /v2/model/stats", s.handleModelStats)
 	mux.HandleFunc("GET /v2/scheduler/stats", s.handleSchedulerStats)
 	mux.HandleFunc("POST /v2/scheduler/retrain", s.handleSchedulerRetrain)
+	mux.HandleFunc("GET /v2/history", s.handleHistory)
+	mux.HandleFunc("GET /v2/health", s.handleHealthV2)
+	mux.HandleFunc("POST /v2/metrics", s.handleMetricsPush)
+	mux.HandleFunc("POST /v2/reserve", s.handleReserve)
+	mux.HandleFunc("DELETE /v2/reserve/{id}", s.handleRelease)
+	mux.HandleFunc("POST /v2/reserve/{id}/heartbeat", s.handleHeartbeat)
 
 	// Setup debug routes with separate authentication
 	s.setupDebugRoutes(mux)
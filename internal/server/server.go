@@ -5,13 +5,16 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/haskel/capfox/internal/capacity"
 	"github.com/haskel/capfox/internal/config"
+	"github.com/haskel/capfox/internal/decision"
 	"github.com/haskel/capfox/internal/learning"
 	"github.com/haskel/capfox/internal/monitor"
 	"github.com/haskel/capfox/internal/server/middleware"
+	"github.com/haskel/capfox/internal/trace"
 )
 
 type Server struct {
@@ -19,13 +22,19 @@ type Server struct {
 	aggregator      *monitor.Aggregator
 	capacityManager *capacity.Manager
 	learningEngine  *learning.Engine
-	config          *config.Config
 	logger          *slog.Logger
 	version         string
 	authConfig      *middleware.AuthConfig
 
+	// configMu guards config, which ReloadConfig replaces
+	configMu sync.RWMutex
+	config   *config.Config
+
 	// V2 components (new decision engine)
 	v2 *V2Components
+
+	// recorder traces asks, notifies and decisions; nil if disabled
+	recorder *trace.Recorder
 }
 
 func New(cfg *config.Config, agg *monitor.Aggregator, cm *capacity.Manager, le *learning.Engine, logger *slog.Logger, version string) *Server {
@@ -86,6 +95,19 @@ func New(cfg *config.Config, agg *monitor.Aggregator, cm *capacity.Manager, le *
 	return s
 }
 
+// SetRecorder sets the trace recorder for API requests.
+func (s *Server) SetRecorder(recorder *trace.Recorder) {
+	s.recorder = recorder
+}
+
+// currentConfig returns the configuration in effect, safe to call while
+// ReloadConfig runs.
+func (s *Server) currentConfig() *config.Config {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.config
+}
+
 // ReloadConfig reloads configuration that can be changed at runtime.
 // Note: host/port changes require restart.
 func (s *Server) ReloadConfig(cfg *config.Config) {
@@ -97,8 +119,39 @@ func (s *Server) ReloadConfig(cfg *config.Config) {
 	// Update thresholds in capacity manager
 	s.capacityManager.UpdateThresholds(cfg.Thresholds)
 
+	// Update thresholds in decision manager
+	if s.v2 != nil && s.v2.DecisionManager != nil {
+		s.v2.DecisionManager.UpdateThresholds(decision.ThresholdsFromConfig(cfg.Thresholds))
+	}
+
+	// Resolve per-task and per-priority overrides against the new global thresholds
+	if tasks, err := config.NewTaskThresholds(cfg.Thresholds, cfg.Tasks, cfg.Priorities); err != nil {
+		s.logger.Warn("ignoring task thresholds", "error", err)
+	} else {
+		s.capacityManager.SetTaskThresholds(tasks)
+		if s.v2 != nil && s.v2.DecisionManager != nil {
+			s.v2.DecisionManager.SetTaskThresholds(tasks)
+		}
+	}
+
+	// Restart smoothed series with the new aggregation per resource
+	if smoothing, err := monitor.ParseSmoothingSpecs(cfg.Monitoring.Smoothing.Specs()); err != nil {
+		s.logger.Warn("keeping previous smoothing", "error", err)
+	} else {
+		s.aggregator.SetSmoothing(smoothing)
+	}
+
+	s.aggregator.SetTimeouts(cfg.CollectTimeouts())
+
+	// Keep learned memory deltas on the same basis as the memory threshold
+	if s.learningEngine != nil {
+		s.learningEngine.SetMemoryMode(cfg.Thresholds.Memory.Mode)
+	}
+
 	// Update stored config
+	s.configMu.Lock()
 	s.config = cfg
+	s.configMu.Unlock()
 
 	s.logger.Info("configuration reloaded",
 		"auth_enabled", cfg.Auth.Enabled,
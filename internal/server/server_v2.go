@@ -4,6 +4,7 @@ import (
 	"github.com/haskel/capfox/internal/decision"
 	"github.com/haskel/capfox/internal/decision/model"
 	"github.com/haskel/capfox/internal/decision/scheduler"
+	"github.com/haskel/capfox/internal/storage"
 )
 
 // V2Components holds the new decision engine components.
@@ -11,6 +12,8 @@ type V2Components struct {
 	DecisionManager *decision.Manager
 	Scheduler       *scheduler.Scheduler
 	Model           model.PredictionModel
+	// LeaseStorage persists leases on every change, nil keeps them in memory
+	LeaseStorage *storage.LeaseStorage
 }
 
 // SetDecisionComponents sets the new decision engine components.
@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+const (
+	leasesFileName = "capfox_leases.json"
+)
+
+// LeaseStorage handles persistence of capacity leases, so reservations
+// survive a restart.
+type LeaseStorage struct {
+	storage *Storage
+}
+
+// NewLeaseStorage creates a new LeaseStorage.
+func NewLeaseStorage(s *Storage) *LeaseStorage {
+	return &LeaseStorage{storage: s}
+}
+
+// SaveLeases saves the leases to disk.
+func (ls *LeaseStorage) SaveLeases(leases Saveable) error {
+	ls.storage.mu.Lock()
+	defer ls.storage.mu.Unlock()
+
+	filePath, err := writeFileAtomic(ls.storage.dataDir, leasesFileName, leases)
+	if err != nil {
+		return err
+	}
+
+	ls.storage.logger.Debug("saved leases to disk", "path", filePath)
+	return nil
+}
+
+// LoadLeases loads the leases from disk. A missing file leaves leases
+// unchanged.
+func (ls *LeaseStorage) LoadLeases(leases Loadable) error {
+	ls.storage.mu.Lock()
+	defer ls.storage.mu.Unlock()
+
+	filePath := filepath.Join(ls.storage.dataDir, leasesFileName)
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to open leases file: %w", err)
+	}
+	defer file.Close()
+
+	if err := leases.Load(file); err != nil {
+		ls.storage.logger.Warn("failed to load leases, starting without", "error", err)
+		return nil
+	}
+
+	ls.storage.logger.Info("loaded leases from disk", "path", filePath)
+	return nil
+}
@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestLeaseStorage_SaveLoad(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	ls := NewLeaseStorage(New(t.TempDir(), time.Hour, logger))
+
+	// A missing file leaves the leases untouched
+	loaded := &mockModel{Data: "unchanged"}
+	if err := ls.LoadLeases(loaded); err != nil {
+		t.Fatalf("LoadLeases error: %v", err)
+	}
+	if loaded.Data != "unchanged" {
+		t.Errorf("expected leases untouched without a file, got %+v", loaded)
+	}
+
+	original := &mockModel{Data: "lease", Value: 2}
+	if err := ls.SaveLeases(original); err != nil {
+		t.Fatalf("SaveLeases error: %v", err)
+	}
+
+	loaded = &mockModel{}
+	if err := ls.LoadLeases(loaded); err != nil {
+		t.Fatalf("LoadLeases error: %v", err)
+	}
+	if *loaded != *original {
+		t.Errorf("expected %+v, got %+v", original, loaded)
+	}
+}
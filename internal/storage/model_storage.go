@@ -41,38 +41,57 @@ func (ms *ModelStorage) SaveModel(model Saveable) error {
 }
 
 func (ms *ModelStorage) saveModelLocked(model Saveable) error {
+	filePath, err := writeFileAtomic(ms.storage.dataDir, modelFileName, model)
+	if err != nil {
+		return err
+	}
+
+	ms.storage.logger.Debug("saved model to disk", "path", filePath)
+	return nil
+}
+
+// SaveOnFlush saves model whenever the storage data is saved, by the
+// periodic flush and on Stop, so a crash loses at most one flush interval.
+func (ms *ModelStorage) SaveOnFlush(model Saveable) {
+	ms.storage.mu.Lock()
+	defer ms.storage.mu.Unlock()
+	ms.storage.model = model
+}
+
+// writeFileAtomic saves v to name in dir through a temp file and a rename,
+// so readers never see a partial file. Returns the path written.
+func writeFileAtomic(dir, name string, v Saveable) (string, error) {
 	// Ensure data directory exists
-	if err := os.MkdirAll(ms.storage.dataDir, 0755); err != nil {
-		return fmt.Errorf("failed to create data directory: %w", err)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create data directory: %w", err)
 	}
 
-	filePath := filepath.Join(ms.storage.dataDir, modelFileName)
+	filePath := filepath.Join(dir, name)
 	tempPath := filePath + ".tmp"
 
 	file, err := os.Create(tempPath)
 	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
+		return "", fmt.Errorf("failed to create temp file: %w", err)
 	}
 
-	if err := model.Save(file); err != nil {
+	if err := v.Save(file); err != nil {
 		file.Close()
 		os.Remove(tempPath)
-		return fmt.Errorf("failed to save model: %w", err)
+		return "", fmt.Errorf("failed to save %s: %w", name, err)
 	}
 
 	if err := file.Close(); err != nil {
 		os.Remove(tempPath)
-		return fmt.Errorf("failed to close temp file: %w", err)
+		return "", fmt.Errorf("failed to close temp file: %w", err)
 	}
 
 	// Atomic rename
 	if err := os.Rename(tempPath, filePath); err != nil {
 		os.Remove(tempPath)
-		return fmt.Errorf("failed to rename temp file: %w", err)
+		return "", fmt.Errorf("failed to rename temp file: %w", err)
 	}
 
-	ms.storage.logger.Debug("saved model to disk", "path", filePath)
-	return nil
+	return filePath, nil
 }
 
 // LoadModel loads a model from disk.
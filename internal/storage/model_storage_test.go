@@ -191,3 +191,25 @@ func TestModelStorage_AtomicWrite(t *testing.T) {
 		t.Errorf("expected Value 4, got %d", model.Value)
 	}
 }
+
+func TestModelStorage_SaveOnFlush(t *testing.T) {
+	tmpDir := t.TempDir()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	storage := New(tmpDir, time.Hour, logger)
+	ms := NewModelStorage(storage)
+	ms.SaveOnFlush(&mockModel{Data: "flushed", Value: 7})
+
+	storage.UpdateTaskStats("task1", 1, 1.0, 1.0, 0, 0)
+	if err := storage.Save(); err != nil {
+		t.Fatalf("Save error: %v", err)
+	}
+
+	model := &mockModel{}
+	if err := ms.LoadModel(model); err != nil {
+		t.Fatalf("LoadModel error: %v", err)
+	}
+	if model.Data != "flushed" || model.Value != 7 {
+		t.Errorf("expected model saved with the data, got %+v", model)
+	}
+}
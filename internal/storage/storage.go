@@ -41,6 +41,7 @@ type Storage struct {
 	mu       sync.RWMutex
 	data     *Data
 	dirty    bool
+	model    Saveable // saved along with data, see ModelStorage.SaveOnFlush
 	cancel   context.CancelFunc
 	done     chan struct{}
 	stopOnce sync.Once
@@ -161,6 +162,14 @@ func (s *Storage) saveLocked() error {
 	s.dirty = false
 	s.logger.Debug("saved data to disk", "path", filePath)
 
+	if s.model != nil {
+		modelPath, err := writeFileAtomic(s.dataDir, modelFileName, s.model)
+		if err != nil {
+			return err
+		}
+		s.logger.Debug("saved model to disk", "path", modelPath)
+	}
+
 	return nil
 }
 
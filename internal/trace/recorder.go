@@ -0,0 +1,123 @@
+// Package trace records what capfox saw and decided as an NDJSON trace,
+// and replays recorded snapshots for offline reproduction.
+package trace
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/haskel/capfox/internal/monitor"
+)
+
+// Entry types.
+const (
+	// TypeSnapshot is a collected system state, before smoothing
+	TypeSnapshot = "snapshot"
+	// TypeAsk is a POST /ask request and its response
+	TypeAsk = "ask"
+	// TypeNotify is a POST /task/notify request
+	TypeNotify = "notify"
+	// TypeDecision is a POST /v2/ask request and its result
+	TypeDecision = "decision"
+)
+
+// Entry is one line of a trace. Snapshots carry State, the other types
+// carry the request and, if any, the response as sent over the API.
+type Entry struct {
+	Time     time.Time            `json:"time"`
+	Type     string               `json:"type"`
+	State    *monitor.SystemState `json:"state,omitempty"`
+	Request  json.RawMessage      `json:"request,omitempty"`
+	Response json.RawMessage      `json:"response,omitempty"`
+}
+
+// Recorder appends entries to a trace file. It is safe for concurrent use;
+// a nil Recorder discards entries.
+type Recorder struct {
+	mu     sync.Mutex
+	file   *os.File
+	enc    *json.Encoder
+	logger *slog.Logger
+	failed bool // a write error has been logged
+}
+
+// NewRecorder opens the trace at path for appending, creating it if needed.
+func NewRecorder(path string, logger *slog.Logger) (*Recorder, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace: %w", err)
+	}
+
+	return &Recorder{
+		file:   file,
+		enc:    json.NewEncoder(file),
+		logger: logger,
+	}, nil
+}
+
+// RecordSnapshot appends a collected state. It matches
+// monitor.SnapshotObserver.
+func (r *Recorder) RecordSnapshot(state *monitor.SystemState) {
+	if r == nil {
+		return
+	}
+	r.write(Entry{Time: state.Timestamp, Type: TypeSnapshot, State: state})
+}
+
+// Record appends an API exchange of the given type. response may be nil.
+func (r *Recorder) Record(entryType string, request, response any) {
+	if r == nil {
+		return
+	}
+
+	entry := Entry{Time: time.Now(), Type: entryType}
+	var err error
+	if entry.Request, err = json.Marshal(request); err != nil {
+		r.fail(err)
+		return
+	}
+	if response != nil {
+		if entry.Response, err = json.Marshal(response); err != nil {
+			r.fail(err)
+			return
+		}
+	}
+	r.write(entry)
+}
+
+// Close closes the trace file.
+func (r *Recorder) Close() error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.file.Close()
+}
+
+func (r *Recorder) write(entry Entry) {
+	r.mu.Lock()
+	err := r.enc.Encode(entry)
+	r.mu.Unlock()
+
+	if err != nil {
+		r.fail(err)
+	}
+}
+
+// fail logs the first write error; a full disk would otherwise log on
+// every collection.
+func (r *Recorder) fail(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.failed && r.logger != nil {
+		r.logger.Error("failed to write trace", "error", err)
+	}
+	r.failed = true
+}
@@ -0,0 +1,101 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/haskel/capfox/internal/monitor"
+)
+
+func readEntries(t *testing.T, path string) []Entry {
+	t.Helper()
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open trace: %v", err)
+	}
+	defer file.Close()
+
+	var entries []Entry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid trace line %q: %v", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestRecorder_Record(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.ndjson")
+
+	recorder, err := NewRecorder(path, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	recorder.RecordSnapshot(&monitor.SystemState{
+		CPU:       monitor.CPUState{UsagePercent: 91},
+		Timestamp: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+	})
+	recorder.Record(TypeAsk, map[string]any{"task": "render"}, map[string]any{"allowed": false})
+	recorder.Record(TypeNotify, map[string]any{"task": "render"}, nil)
+
+	if err := recorder.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	entries := readEntries(t, path)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries, got %d", len(entries))
+	}
+
+	if entries[0].Type != TypeSnapshot || entries[0].State == nil || entries[0].State.CPU.UsagePercent != 91 {
+		t.Errorf("unexpected snapshot entry: %+v", entries[0])
+	}
+	if !entries[0].Time.Equal(time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Errorf("expected snapshot time from the state, got %v", entries[0].Time)
+	}
+
+	if entries[1].Type != TypeAsk || string(entries[1].Response) != `{"allowed":false}` {
+		t.Errorf("unexpected ask entry: type %s, response %s", entries[1].Type, entries[1].Response)
+	}
+
+	if entries[2].Type != TypeNotify || entries[2].Response != nil {
+		t.Errorf("expected notify entry without response, got %+v", entries[2])
+	}
+}
+
+func TestRecorder_Appends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.ndjson")
+
+	for range 2 {
+		recorder, err := NewRecorder(path, nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		recorder.Record(TypeNotify, map[string]any{"task": "render"}, nil)
+		_ = recorder.Close()
+	}
+
+	if entries := readEntries(t, path); len(entries) != 2 {
+		t.Errorf("expected entries of both runs, got %d", len(entries))
+	}
+}
+
+func TestRecorder_Nil(t *testing.T) {
+	var recorder *Recorder
+
+	// Must not panic
+	recorder.RecordSnapshot(&monitor.SystemState{})
+	recorder.Record(TypeAsk, nil, nil)
+	if err := recorder.Close(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,151 @@
+package trace
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/haskel/capfox/internal/monitor"
+)
+
+// maxLineBytes bounds one trace line; snapshots of large hosts run to
+// tens of kilobytes.
+const maxLineBytes = 16 << 20
+
+// ReplayMonitor feeds the snapshots of a trace back into an aggregator.
+// Recorded time advances with wall time multiplied by speed, starting at
+// the first Collect; after the last snapshot it keeps returning the last.
+type ReplayMonitor struct {
+	snapshots []Entry
+	speed     float64
+	now       func() time.Time
+	started   time.Time
+}
+
+// NewReplayMonitor loads the snapshots of the trace at path. speed 1
+// replays at recorded speed, 10 ten times faster.
+func NewReplayMonitor(path string, speed float64) (*ReplayMonitor, error) {
+	if speed <= 0 {
+		return nil, fmt.Errorf("replay speed must be positive, got %v", speed)
+	}
+
+	snapshots, err := ReadSnapshots(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReplayMonitor{
+		snapshots: snapshots,
+		speed:     speed,
+		now:       time.Now,
+	}, nil
+}
+
+// ReadSnapshots returns the snapshot entries of a trace in time order.
+// Other entry types are skipped.
+func ReadSnapshots(path string) ([]Entry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open trace: %w", err)
+	}
+	defer file.Close()
+
+	var snapshots []Entry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), maxLineBytes)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var entry Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("trace line %d: %w", line, err)
+		}
+		if entry.Type == TypeSnapshot && entry.State != nil {
+			snapshots = append(snapshots, entry)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read trace: %w", err)
+	}
+
+	if len(snapshots) == 0 {
+		return nil, errors.New("trace has no snapshots")
+	}
+
+	sort.SliceStable(snapshots, func(i, j int) bool {
+		return snapshots[i].Time.Before(snapshots[j].Time)
+	})
+	return snapshots, nil
+}
+
+func (m *ReplayMonitor) Name() string {
+	return "replay"
+}
+
+func (m *ReplayMonitor) Section() string {
+	return monitor.SectionAll
+}
+
+// Thresholds contributes the limits of the built-in monitors the replay
+// stands in for.
+func (m *ReplayMonitor) Thresholds() []monitor.Threshold {
+	return monitor.ProcessThresholds()
+}
+
+func (m *ReplayMonitor) Collect() (monitor.Sample, error) {
+	return replaySample{state: m.current().State}, nil
+}
+
+// Duration returns the recorded time span of the trace.
+func (m *ReplayMonitor) Duration() time.Duration {
+	return m.snapshots[len(m.snapshots)-1].Time.Sub(m.snapshots[0].Time)
+}
+
+// Len returns the number of snapshots in the trace.
+func (m *ReplayMonitor) Len() int {
+	return len(m.snapshots)
+}
+
+// current returns the latest snapshot at the replay position.
+func (m *ReplayMonitor) current() Entry {
+	now := m.now()
+	if m.started.IsZero() {
+		m.started = now
+	}
+
+	elapsed := time.Duration(float64(now.Sub(m.started)) * m.speed)
+	position := m.snapshots[0].Time.Add(elapsed)
+
+	// First snapshot recorded after the position
+	next := sort.Search(len(m.snapshots), func(i int) bool {
+		return m.snapshots[i].Time.After(position)
+	})
+	return m.snapshots[max(next-1, 0)]
+}
+
+// replaySample replaces the collected state with a recorded one. The
+// timestamp stays current; recorded monitor health is kept so stale
+// metrics reproduce, alongside the health of the replay monitor itself.
+type replaySample struct {
+	state *monitor.SystemState
+}
+
+func (r replaySample) Apply(s *monitor.SystemState) {
+	timestamp, health := s.Timestamp, s.Health
+
+	*s = *r.state.Clone()
+	s.Timestamp = timestamp
+
+	if s.Health == nil {
+		s.Health = make(monitor.HealthState, len(health))
+	}
+	for name, h := range health {
+		s.Health[name] = h
+	}
+}
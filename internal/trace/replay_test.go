@@ -0,0 +1,140 @@
+package trace
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/haskel/capfox/internal/monitor"
+)
+
+// writeTrace records snapshots with the given CPU usage one second apart,
+// interleaved with an ask.
+func writeTrace(t *testing.T, cpu ...float64) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), "trace.ndjson")
+	recorder, err := NewRecorder(path, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer recorder.Close()
+
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i, usage := range cpu {
+		recorder.RecordSnapshot(&monitor.SystemState{
+			CPU:       monitor.CPUState{UsagePercent: usage},
+			Health:    monitor.HealthState{"gpu": {Stale: true, ConsecutiveFailures: 3}},
+			Timestamp: start.Add(time.Duration(i) * time.Second),
+		})
+		recorder.Record(TypeAsk, map[string]any{"task": "render"}, nil)
+	}
+	return path
+}
+
+// clock is a manually advanced time source.
+type clock struct {
+	now time.Time
+}
+
+func (c *clock) Now() time.Time {
+	return c.now
+}
+
+func collectCPU(t *testing.T, m *ReplayMonitor) float64 {
+	t.Helper()
+
+	sample, err := m.Collect()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	state := &monitor.SystemState{}
+	sample.Apply(state)
+	return state.CPU.UsagePercent
+}
+
+func TestReplayMonitor_Speed(t *testing.T) {
+	path := writeTrace(t, 10, 20, 30, 40, 50)
+
+	m, err := NewReplayMonitor(path, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	c := &clock{now: time.Now()}
+	m.now = c.Now
+
+	if m.Len() != 5 || m.Duration() != 4*time.Second {
+		t.Errorf("expected 5 snapshots over 4s, got %d over %s", m.Len(), m.Duration())
+	}
+
+	steps := []struct {
+		advance time.Duration
+		want    float64
+	}{
+		{0, 10},
+		{500 * time.Millisecond, 20}, // 1s recorded at 2x
+		{750 * time.Millisecond, 30}, // 2.5s recorded
+		{time.Hour, 50},              // holds the last snapshot
+	}
+	for _, step := range steps {
+		c.now = c.now.Add(step.advance)
+		if got := collectCPU(t, m); got != step.want {
+			t.Errorf("after %s: expected cpu %v, got %v", step.advance, step.want, got)
+		}
+	}
+}
+
+func TestReplaySample_Apply(t *testing.T) {
+	path := writeTrace(t, 75)
+	m, err := NewReplayMonitor(path, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	now := time.Now()
+	state := &monitor.SystemState{
+		Timestamp: now,
+		Health:    monitor.HealthState{"replay": {Section: monitor.SectionAll}},
+	}
+	sample, _ := m.Collect()
+	sample.Apply(state)
+
+	if state.CPU.UsagePercent != 75 {
+		t.Errorf("expected recorded cpu 75, got %v", state.CPU.UsagePercent)
+	}
+	if !state.Timestamp.Equal(now) {
+		t.Errorf("expected current timestamp, got %v", state.Timestamp)
+	}
+	if !state.Health["gpu"].Stale {
+		t.Error("expected recorded health to be kept")
+	}
+	if _, ok := state.Health["replay"]; !ok {
+		t.Error("expected replay monitor health to be kept")
+	}
+}
+
+func TestNewReplayMonitor_Errors(t *testing.T) {
+	dir := t.TempDir()
+
+	empty := filepath.Join(dir, "empty.ndjson")
+	if err := os.WriteFile(empty, []byte(`{"time":"2026-03-01T12:00:00Z","type":"ask","request":{}}`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewReplayMonitor(empty, 1); err == nil || !strings.Contains(err.Error(), "no snapshots") {
+		t.Errorf("expected no snapshots error, got %v", err)
+	}
+
+	invalid := filepath.Join(dir, "invalid.ndjson")
+	if err := os.WriteFile(invalid, []byte("{}\nnot json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewReplayMonitor(invalid, 1); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("expected line 2 error, got %v", err)
+	}
+
+	if _, err := NewReplayMonitor(writeTrace(t, 10), 0); err == nil {
+		t.Error("expected error for zero speed")
+	}
+}